@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net/http"
 	"os"
@@ -10,35 +11,89 @@ import (
 	"syscall"
 	"time"
 
+	apikey_svc "github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_http "github.com/0x0FACED/pvz-avito/internal/apikey/delivery/http"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	apikey_memory "github.com/0x0FACED/pvz-avito/internal/apikey/infra/memory"
+	apikey_db "github.com/0x0FACED/pvz-avito/internal/apikey/infra/postgres"
+	apikey_sqlite "github.com/0x0FACED/pvz-avito/internal/apikey/infra/sqlite"
 	"github.com/0x0FACED/pvz-avito/internal/app"
+	audit_svc "github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_http "github.com/0x0FACED/pvz-avito/internal/audit/delivery/http"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_memory "github.com/0x0FACED/pvz-avito/internal/audit/infra/memory"
+	audit_db "github.com/0x0FACED/pvz-avito/internal/audit/infra/postgres"
+	audit_sqlite "github.com/0x0FACED/pvz-avito/internal/audit/infra/sqlite"
 	auth_svc "github.com/0x0FACED/pvz-avito/internal/auth/application"
 	auth_http "github.com/0x0FACED/pvz-avito/internal/auth/delivery/http"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	auth_memory "github.com/0x0FACED/pvz-avito/internal/auth/infra/memory"
+	auth_oidc "github.com/0x0FACED/pvz-avito/internal/auth/infra/oidc"
 	auth_db "github.com/0x0FACED/pvz-avito/internal/auth/infra/postgres"
+	auth_sqlite "github.com/0x0FACED/pvz-avito/internal/auth/infra/sqlite"
+	events_svc "github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_grpc "github.com/0x0FACED/pvz-avito/internal/events/delivery/grpc"
+	events_pb "github.com/0x0FACED/pvz-avito/internal/events/delivery/grpc/v1"
+	events_http "github.com/0x0FACED/pvz-avito/internal/events/delivery/http"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/cache"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/health"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/ratelimit"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	product_svc "github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_grpc "github.com/0x0FACED/pvz-avito/internal/product/delivery/grpc"
 	product_http "github.com/0x0FACED/pvz-avito/internal/product/delivery/http"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	product_memory "github.com/0x0FACED/pvz-avito/internal/product/infra/memory"
 	product_db "github.com/0x0FACED/pvz-avito/internal/product/infra/postgres"
+	product_sqlite "github.com/0x0FACED/pvz-avito/internal/product/infra/sqlite"
 	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
 
 	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
 	pvz_grpc "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc"
 	pvz_http "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/http"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pvz/infra/cached"
+	pvz_memory "github.com/0x0FACED/pvz-avito/internal/pvz/infra/memory"
 	pvz_db "github.com/0x0FACED/pvz-avito/internal/pvz/infra/postgres"
+	pvz_sqlite "github.com/0x0FACED/pvz-avito/internal/pvz/infra/sqlite"
 	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_grpc "github.com/0x0FACED/pvz-avito/internal/reception/delivery/grpc"
 	reception_http "github.com/0x0FACED/pvz-avito/internal/reception/delivery/http"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_memory "github.com/0x0FACED/pvz-avito/internal/reception/infra/memory"
 	reception_db "github.com/0x0FACED/pvz-avito/internal/reception/infra/postgres"
+	reception_sqlite "github.com/0x0FACED/pvz-avito/internal/reception/infra/sqlite"
+	sync_svc "github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_http "github.com/0x0FACED/pvz-avito/internal/sync/delivery/http"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	sync_memory "github.com/0x0FACED/pvz-avito/internal/sync/infra/memory"
+	sync_db "github.com/0x0FACED/pvz-avito/internal/sync/infra/postgres"
+	sync_sqlite "github.com/0x0FACED/pvz-avito/internal/sync/infra/sqlite"
+	"github.com/0x0FACED/pvz-avito/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// migrate subcommand does not start servers
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(ctx, os.Args[2:])
+		stop()
+		os.Exit(code)
+	}
+
 	cfg := config.MustLoad()
 
 	defer func() {
@@ -60,49 +115,316 @@ func main() {
 	pvzSvcLogger := logger.WithFeature("pvz_svc")
 	productSvcLogger := logger.WithFeature("product_svc")
 	receptionSvcLogger := logger.WithFeature("reception_svc")
+	autoCloserLogger := logger.WithFeature("reception_auto_closer")
+	auditSvcLogger := logger.WithFeature("audit_svc")
+	apiKeySvcLogger := logger.WithFeature("apikey_svc")
+	syncSvcLogger := logger.WithFeature("sync_svc")
+	eventsSvcLogger := logger.WithFeature("events_svc")
+	healthLogger := logger.WithFeature("health")
 
 	appLogger.Info().Msg("Loggers with features created")
 
-	appLogger.Info().Msg("Connecting to database...")
-	// connect to db pool
-	pool, err := database.ConnectPool(ctx, cfg.Database)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
-		appLogger.Fatal().Err(err).Msg("Failed to connect to database")
+		appLogger.Fatal().Err(err).Msg("Failed to setup tracing")
 	}
+	defer func() {
+		// flush buffered spans
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-	appLogger.Info().Msg("Successfully connected to database")
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Error().Err(err).Msg("Failed to shutdown tracing")
+		}
+	}()
+
+	if cfg.Tracing.Enabled {
+		appLogger.Info().Str("exporter", cfg.Tracing.Exporter).Msg("Tracing enabled")
+	}
+
+	var (
+		pool          *pgxpool.Pool
+		sqliteDB      *sql.DB
+		dbCheck       health.CheckFunc
+		schemaCheck   health.CheckFunc
+		authRepo      auth_domain.UserRepository
+		pvzRepo       pvz_domain.PVZRepository
+		productRepo   product_domain.ProductRepository
+		receptionRepo reception_domain.ReceptionRepository
+		auditRepo     audit_domain.AuditRepository
+		apiKeyRepo    apikey_domain.APIKeyRepository
+		journalRepo   sync_domain.JournalRepository
+		transactor    database.Transactor
+	)
 
 	// creating all repos
-	authRepo := auth_db.NewAuthPostgresRepository(pool)
-	pvzRepo := pvz_db.NewPVZPostgresRepository(pool)
-	productRepo := product_db.NewProductPostgresRepository(pool)
-	receptionRepo := reception_db.NewReceptionPostgresRepository(pool)
+	switch cfg.Storage.Driver {
+	case "postgres":
+		appLogger.Info().Msg("Connecting to database...")
+		// connect to db pool
+		pool, err = database.ConnectPool(ctx, cfg.Database)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to connect to database")
+		}
+
+		appLogger.Info().Msg("Successfully connected to database")
+
+		if cfg.Database.AutoMigrate {
+			migrator, err := database.NewMigrator(pool)
+			if err != nil {
+				appLogger.Fatal().Err(err).Msg("Failed to create migrator")
+			}
+			if err := migrator.Up(); err != nil {
+				appLogger.Fatal().Err(err).Msg("Failed to apply migrations")
+			}
+			if err := migrator.Close(); err != nil {
+				appLogger.Error().Err(err).Msg("Failed to close migrator")
+			}
+
+			appLogger.Info().Msg("Migrations applied")
+		}
+
+		// schema must be migrated to version of embedded migrations,
+		// same check is used by readiness probe
+		expectedVersion, err := migrations.LatestVersion()
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to read embedded migrations")
+		}
+
+		schemaCheck = health.MigrationCheck(expectedVersion, func(ctx context.Context) (uint, bool, error) {
+			return database.SchemaVersion(ctx, pool)
+		})
+		if err := schemaCheck(ctx); err != nil {
+			appLogger.Fatal().Err(err).Uint("expected", expectedVersion).Msg("Database schema is not up to date, run migrate up")
+		}
+
+		dbCheck = pool.Ping
+
+		authRepo = auth_db.NewAuthPostgresRepository(pool)
+		pvzRepo = pvz_db.NewPVZPostgresRepository(pool)
+		productRepo = product_db.NewProductPostgresRepository(pool)
+		receptionRepo = reception_db.NewReceptionPostgresRepository(pool)
+		auditRepo = audit_db.NewAuditPostgresRepository(pool)
+		apiKeyRepo = apikey_db.NewAPIKeyPostgresRepository(pool)
+		journalRepo = sync_db.NewJournalPostgresRepository(pool)
+		transactor = database.NewPgxTransactor(pool)
+	case "sqlite":
+		appLogger.Info().Str("path", cfg.SQLite.Path).Msg("Opening sqlite database...")
+
+		if cfg.Database.AutoMigrate {
+			migrator, err := database.NewSQLiteMigrator(ctx, cfg.SQLite)
+			if err != nil {
+				appLogger.Fatal().Err(err).Msg("Failed to create migrator")
+			}
+			if err := migrator.Up(); err != nil {
+				appLogger.Fatal().Err(err).Msg("Failed to apply migrations")
+			}
+			if err := migrator.Close(); err != nil {
+				appLogger.Error().Err(err).Msg("Failed to close migrator")
+			}
+
+			appLogger.Info().Msg("Migrations applied")
+		}
+
+		sqliteDB, err = database.OpenSQLite(ctx, cfg.SQLite)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to open sqlite database")
+		}
+		defer sqliteDB.Close()
+
+		expectedVersion, err := migrations.SQLiteLatestVersion()
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to read embedded migrations")
+		}
+
+		schemaCheck = health.MigrationCheck(expectedVersion, func(ctx context.Context) (uint, bool, error) {
+			return database.SQLiteSchemaVersion(ctx, sqliteDB)
+		})
+		if err := schemaCheck(ctx); err != nil {
+			appLogger.Fatal().Err(err).Uint("expected", expectedVersion).Msg("Database schema is not up to date, run migrate up")
+		}
+
+		dbCheck = sqliteDB.PingContext
+
+		authRepo = auth_sqlite.NewAuthSQLiteRepository(sqliteDB)
+		pvzRepo = pvz_sqlite.NewPVZSQLiteRepository(sqliteDB)
+		productRepo = product_sqlite.NewProductSQLiteRepository(sqliteDB)
+		receptionRepo = reception_sqlite.NewReceptionSQLiteRepository(sqliteDB)
+		auditRepo = audit_sqlite.NewAuditSQLiteRepository(sqliteDB)
+		apiKeyRepo = apikey_sqlite.NewAPIKeySQLiteRepository(sqliteDB)
+		journalRepo = sync_sqlite.NewJournalSQLiteRepository(sqliteDB)
+		transactor = database.NewSQLiteTransactor(sqliteDB)
+
+		// database stores are kept in sqlite file, rate limit is per process,
+		// which is exact for single box
+		if cfg.Auth.LoginAttemptsStore == "postgres" {
+			cfg.Auth.LoginAttemptsStore = "sqlite"
+		}
+		cfg.RateLimit.Store = "memory"
+	case "memory":
+		db := memdb.New()
+		authRepo = auth_memory.NewUserMemoryRepository(db)
+		pvzRepo = pvz_memory.NewPVZMemoryRepository(db)
+		productRepo = product_memory.NewProductMemoryRepository(db)
+		receptionRepo = reception_memory.NewReceptionMemoryRepository(db)
+		auditRepo = audit_memory.NewAuditMemoryRepository(db)
+		apiKeyRepo = apikey_memory.NewAPIKeyMemoryRepository(db)
+		journalRepo = sync_memory.NewJournalMemoryRepository(db)
+		transactor = db
+
+		// there is no database for postgres stores
+		cfg.Auth.LoginAttemptsStore = "memory"
+		cfg.RateLimit.Store = "memory"
+
+		appLogger.Warn().Msg("Memory storage is used, all data is lost on restart")
+	default:
+		appLogger.Fatal().Str("driver", cfg.Storage.Driver).Msg("Unknown storage driver")
+	}
 
 	appLogger.Info().Msg("Repos for application services created")
 
+	// pvz listings are cached, reception and product writes invalidate them
+	if cfg.PVZCache.Enabled {
+		cachedPVZRepo := cached.NewPVZRepository(pvzRepo, cache.NewLRU(cfg.PVZCache.Size), cfg.PVZCache.TTL)
+		pvzRepo = cachedPVZRepo
+		receptionRepo = cached.NewReceptionRepository(receptionRepo, cachedPVZRepo)
+		productRepo = cached.NewProductRepository(productRepo, cachedPVZRepo)
+
+		appLogger.Info().Dur("ttl", cfg.PVZCache.TTL).Int("size", cfg.PVZCache.Size).Msg("PVZ listings cache enabled")
+	}
+
 	// creating all svcs
-	authSvc := auth_svc.NewAuthService(authRepo, authSvcLogger)
-	pvzSvc := pvz_svc.NewPVZService(pvzRepo, receptionRepo, productRepo, pvzSvcLogger)
-	productSvc := product_svc.NewProductService(productRepo, receptionRepo, productSvcLogger)
-	receptionSvc := reception_svc.NewReceptionService(receptionRepo, receptionSvcLogger)
+	auditSvc := audit_svc.NewAuditService(auditRepo, auditSvcLogger)
+	// audited reception and product changes are published to event subscribers
+	broker := events_svc.NewBroker(cfg.Events.BackfillSize, cfg.Events.SubscriberBuffer)
+	publisher := events_svc.NewPublisher(auditSvc, broker, receptionRepo, pvzRepo, eventsSvcLogger)
+	var loginAttemptRepo auth_domain.LoginAttemptRepository
+	switch cfg.Auth.LoginAttemptsStore {
+	case "postgres":
+		loginAttemptRepo = auth_db.NewLoginAttemptPostgresRepository(pool)
+	case "sqlite":
+		loginAttemptRepo = auth_sqlite.NewLoginAttemptSQLiteRepository(sqliteDB)
+	case "memory":
+		loginAttemptRepo = auth_memory.NewLoginAttemptMemoryRepository()
+	default:
+		appLogger.Fatal().Str("store", cfg.Auth.LoginAttemptsStore).Msg("Unknown login attempts store")
+	}
+
+	loginGuard := auth_svc.NewLoginGuard(loginAttemptRepo, auth_svc.LoginPolicy{
+		FreeAttempts:    cfg.Auth.LoginFreeAttempts,
+		MaxAttempts:     cfg.Auth.LoginMaxAttempts,
+		IPMaxAttempts:   cfg.Auth.LoginIPMaxAttempts,
+		BaseDelay:       cfg.Auth.LoginBaseDelay,
+		MaxDelay:        cfg.Auth.LoginMaxDelay,
+		LockoutDuration: cfg.Auth.LoginLockoutDuration,
+		Window:          cfg.Auth.LoginAttemptsWindow,
+	}, auditSvc, authSvcLogger)
+
+	hasher, err := auth_svc.NewMultiHasher(
+		cfg.Auth.PasswordHashAlgo,
+		auth_svc.NewBcryptHasher(cfg.Auth.BcryptCost),
+		auth_svc.NewArgon2idHasher(auth_svc.Argon2Params{
+			Time:    cfg.Auth.Argon2Time,
+			Memory:  cfg.Auth.Argon2Memory,
+			Threads: cfg.Auth.Argon2Threads,
+			KeyLen:  cfg.Auth.Argon2KeyLen,
+			SaltLen: cfg.Auth.Argon2SaltLen,
+		}),
+	)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Failed to create password hasher")
+	}
+
+	passwordPolicy := auth_svc.PasswordPolicy{
+		MinLength:      cfg.Auth.PasswordMinLength,
+		MaxLength:      cfg.Auth.PasswordMaxLength,
+		RequireUpper:   cfg.Auth.PasswordRequireUpper,
+		RequireLower:   cfg.Auth.PasswordRequireLower,
+		RequireDigit:   cfg.Auth.PasswordRequireDigit,
+		RequireSpecial: cfg.Auth.PasswordRequireSpecial,
+	}
+	if cfg.Auth.PasswordDenylistFile != "" {
+		passwordPolicy.Denylist, err = auth_svc.LoadDenylist(cfg.Auth.PasswordDenylistFile)
+		if err != nil {
+			appLogger.Fatal().Err(err).Str("file", cfg.Auth.PasswordDenylistFile).Msg("Failed to load password denylist")
+		}
+	}
+
+	authSvc := auth_svc.NewAuthService(authRepo, loginGuard, hasher, passwordPolicy, auditSvc, authSvcLogger)
+	pvzSvc := pvz_svc.NewPVZService(pvzRepo, receptionRepo, productRepo, publisher, pvzSvcLogger)
+	productSvc := product_svc.NewProductService(productRepo, receptionRepo, publisher, productSvcLogger)
+	receptionSvc := reception_svc.NewReceptionService(receptionRepo, publisher, receptionSvcLogger)
+	apiKeySvc := apikey_svc.NewAPIKeyService(apiKeyRepo, auditSvc, apiKeySvcLogger)
+	syncSvc := sync_svc.NewSyncService(journalRepo, transactor, receptionSvc, productSvc, pvzSvc, syncSvcLogger)
+	eventsSvc := events_svc.NewEventsService(broker, eventsSvcLogger)
 
 	appLogger.Info().Msg("Application services created")
 
-	// jwt manager (move diration to cfg)
-	jwt := httpcommon.NewManager(cfg.Server.JWTSecret, time.Hour*240)
+	// jwt manager, asymmetric keys are preferred, hs256 secret is fallback
+	var jwt *httpcommon.JWTManager
+	if cfg.Server.JWTSigningKey != "" {
+		keys, err := httpcommon.LoadKeySet(cfg.Server.JWTSigningKey, cfg.Server.JWTVerifyKeys...)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to load jwt keys")
+		}
+		jwt = httpcommon.NewKeySetManager(keys, cfg.Server.JWTTokenTTL)
+		appLogger.Info().Str("kid", keys.Signing().ID).Str("alg", keys.Signing().Method.Alg()).Msg("JWT signing key loaded")
+	} else {
+		jwt = httpcommon.NewManager(cfg.Server.JWTSecret, cfg.Server.JWTTokenTTL)
+	}
 
 	appLogger.Info().Msg("JWT Manager created")
 
+	// access log with redacted secrets
+	accessLog := middleware.AccessLogConfig{
+		LogBody:      cfg.Logger.AccessLogBody,
+		MaxBodySize:  cfg.Logger.MaxBodySize,
+		RedactFields: cfg.Logger.RedactFields,
+	}
+
 	// create middleware
-	middleware := middleware.NewMiddlewareHandler(jwt, httpLogger)
+	middleware := middleware.NewMiddlewareHandler(jwt, apiKeySvc, cfg.Server.DummyLogin(), cfg.Server.DummyRejectMutating, httpLogger)
 
 	appLogger.Info().Msg("Middleware instance created")
 
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		policy, err := ratelimit.ParsePolicy(cfg.RateLimit.Default, cfg.RateLimit.Routes)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to parse rate limit policy")
+		}
+
+		var store ratelimit.Store
+		switch cfg.RateLimit.Store {
+		case "memory":
+			store = ratelimit.NewMemoryStore()
+		case "postgres":
+			store = ratelimit.NewPostgresStore(pool)
+		default:
+			appLogger.Fatal().Str("store", cfg.RateLimit.Store).Msg("Unknown rate limit store")
+		}
+
+		limiter = ratelimit.NewLimiter(store, policy)
+
+		appLogger.Info().Str("store", cfg.RateLimit.Store).Str("default", policy.Default.String()).Msg("Rate limiter created")
+	}
+	rateLimit := middleware.RateLimit(limiter)
+
+	// gRPC servers of pvz api, REST routes of pvz, receptions
+	// and products are transcoded to them by gateway
+	pvzGrpcHandler := pvz_grpc.NewGRPCHandler(pvzSvc)
+	productGrpcHandler := product_grpc.NewGRPCHandler(productSvc)
+	receptionGrpcHandler := reception_grpc.NewGRPCHandler(receptionSvc)
+
 	// create all handlers
-	authHandler := auth_http.NewHandler(authSvc, jwt)
-	pvzHandler := pvz_http.NewHandler(pvzSvc)
-	productHandler := product_http.NewHandler(productSvc)
-	receptionHandler := reception_http.NewHandler(receptionSvc)
+	authHandler := auth_http.NewHandler(authSvc, jwt, cfg.Server.DummyLogin())
+	pvzHandler := pvz_http.NewHandler(pvzGrpcHandler)
+	productHandler := product_http.NewHandler(productGrpcHandler)
+	receptionHandler := reception_http.NewHandler(receptionGrpcHandler)
+	auditHandler := audit_http.NewHandler(auditSvc)
+	apiKeyHandler := apikey_http.NewHandler(apiKeySvc)
+	syncHandler := sync_http.NewHandler(syncSvc)
+	eventsHandler := events_http.NewHandler(eventsSvc, cfg.Events.Heartbeat)
 
 	appLogger.Info().Msg("Handlers created")
 
@@ -110,18 +432,67 @@ func main() {
 	mux := http.NewServeMux()
 	authHandler.RegisterRoutes(mux)
 
+	if cfg.Auth.OIDCEnabled {
+		provider, err := auth_oidc.NewProvider(ctx, auth_oidc.Config{
+			Issuer:       cfg.Auth.OIDCIssuer,
+			ClientID:     cfg.Auth.OIDCClientID,
+			ClientSecret: cfg.Auth.OIDCClientSecret,
+			RedirectURL:  cfg.Auth.OIDCRedirectURL,
+			Scopes:       cfg.Auth.OIDCScopes,
+			GroupsClaim:  cfg.Auth.OIDCGroupsClaim,
+		})
+		if err != nil {
+			appLogger.Fatal().Err(err).Str("issuer", cfg.Auth.OIDCIssuer).Msg("Failed to create oidc provider")
+		}
+
+		groups, err := auth_svc.ParseRoleMapping(cfg.Auth.OIDCRoleMapping)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Failed to parse oidc role mapping")
+		}
+
+		ssoSvc := auth_svc.NewSSOService(authRepo, provider, auth_svc.RoleMapping{
+			Groups:  groups,
+			Default: auth_domain.Role(cfg.Auth.OIDCDefaultRole),
+		}, auditSvc, authSvcLogger)
+		auth_http.NewSSOHandler(ssoSvc, jwt, !cfg.Server.DebugMode).RegisterRoutes(mux)
+
+		appLogger.Info().Str("issuer", cfg.Auth.OIDCIssuer).Msg("OIDC login enabled")
+	}
+
 	// protected with auth middleware
 	privateMux := http.NewServeMux()
 	pvzHandler.RegisterRoutes(privateMux)
 	productHandler.RegisterRoutes(privateMux)
 	receptionHandler.RegisterRoutes(privateMux)
+	auditHandler.RegisterRoutes(privateMux)
+	apiKeyHandler.RegisterRoutes(privateMux)
+	syncHandler.RegisterRoutes(privateMux)
+	eventsHandler.RegisterRoutes(privateMux)
+	authHandler.RegisterPrivateRoutes(privateMux)
 
 	// apply auth for '/' routes (all expect public /login, /dummyLogin, /register)
-	mux.Handle("/", middleware.Auth(privateMux))
+	mux.Handle("/", middleware.Auth(middleware.Route(privateMux)))
 
 	// apply logger middleware for all routes
+	// and request info (request id, client ip) for audit and rate limit,
+	// rate limit verifies credentials for bucket key once, auth reuses them,
+	// anonymous requests are rate limited by ip,
+	// tracing span covers whole request
 	// this is final mux
-	loggedMux := middleware.Logger(mux)
+	loggedMux := middleware.Tracing(middleware.RequestInfo(middleware.Logger(accessLog)(rateLimit(middleware.Route(mux)))))
+
+	// readiness: db is reachable and schema is migrated to version of embedded migrations,
+	// memory storage is always ready
+	appHealth := health.New(cfg.Health.CheckTimeout, cfg.Health.GRPCInterval, healthLogger)
+	if dbCheck != nil {
+		appHealth.AddCheck("database", dbCheck)
+		appHealth.AddCheck("migrations", schemaCheck)
+	}
+
+	// probes bypass tracing, access log and rate limit
+	rootMux := http.NewServeMux()
+	appHealth.RegisterRoutes(rootMux)
+	rootMux.Handle("/", loggedMux)
 
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", promhttp.Handler())
@@ -130,12 +501,15 @@ func main() {
 
 	srv := &http.Server{
 		Addr:         cfg.Server.Host + ":" + cfg.Server.Port,
-		Handler:      loggedMux,
+		Handler:      rootMux,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	// event streams never end on their own, close them on shutdown
+	srv.RegisterOnShutdown(broker.Close)
+
 	appLogger.Info().Msg("Application server created")
 
 	var metricsSrv *http.Server
@@ -153,11 +527,38 @@ func main() {
 	}
 
 	// adding grpc server
-	grpcServer := grpc.NewServer()
-	pvzGrpcHandler := pvz_grpc.NewGRPCHandler(pvzSvc)
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(middleware.GRPCAuth),
+		grpc.StreamInterceptor(middleware.GRPCStreamAuth),
+	)
 	pb.RegisterPVZServiceServer(grpcServer, pvzGrpcHandler)
+	pb.RegisterReceptionServiceServer(grpcServer, receptionGrpcHandler)
+	pb.RegisterProductServiceServer(grpcServer, productGrpcHandler)
+	eventsGrpcHandler := events_grpc.NewGRPCHandler(eventsSvc)
+	events_pb.RegisterEventsServiceServer(grpcServer, eventsGrpcHandler)
+	healthpb.RegisterHealthServer(grpcServer, appHealth.GRPC())
+
+	appOpts := []app.Option{
+		app.WithHealth(appHealth, cfg.Health.ShutdownDelay),
+		app.WithWorker("health", appHealth),
+	}
+
+	if cfg.Reception.AutoCloseEnabled {
+		autoCloser := reception_svc.NewAutoCloser(
+			receptionRepo,
+			publisher,
+			pvzSvc,
+			cfg.Reception.AutoCloseIdleTimeout,
+			cfg.Reception.AutoCloseInterval,
+			autoCloserLogger,
+		)
+		appOpts = append(appOpts, app.WithWorker("reception_auto_closer", autoCloser))
+
+		appLogger.Info().Msg("Reception auto closer created")
+	}
 
-	app := app.New(srv, metricsSrv, grpcServer, appLogger, cfg)
+	app := app.New(srv, metricsSrv, grpcServer, appLogger, cfg, appOpts...)
 
 	appLogger.Info().Msg("App instance created, starting servers...")
 
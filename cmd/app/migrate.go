@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+)
+
+const migrateUsage = `usage: pvz-avito migrate <command>
+
+commands:
+  up        apply all pending migrations
+  down [N]  roll back N migrations (default 1)
+  status    print applied and expected schema version`
+
+// runMigrate runs "migrate" subcommand and returns exit code.
+func runMigrate(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	steps := 1
+	switch args[0] {
+	case "up", "status":
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+	case "down":
+		if len(args) > 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				fmt.Fprintf(os.Stderr, "invalid steps %q, must be positive integer\n", args[1])
+				return 2
+			}
+			steps = n
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, closeMigrator, err := newMigrator(ctx, config.MustLoadMigrate())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeMigrator()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down(steps)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("version: %d\ndirty: %t\nexpected: %d\n", status.Version, status.Dirty, status.Expected)
+
+	return 0
+}
+
+// newMigrator creates migrator for configured storage driver.
+// Returned func releases migrator and its database connection.
+func newMigrator(ctx context.Context, cfg *config.MigrateConfig) (*database.Migrator, func(), error) {
+	switch cfg.Storage.Driver {
+	case "postgres":
+		pool, err := database.ConnectPool(ctx, cfg.Database)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		migrator, err := database.NewMigrator(pool)
+		if err != nil {
+			pool.Close()
+			return nil, nil, err
+		}
+
+		return migrator, func() {
+			_ = migrator.Close()
+			pool.Close()
+		}, nil
+	case "sqlite":
+		migrator, err := database.NewSQLiteMigrator(ctx, cfg.SQLite)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return migrator, func() { _ = migrator.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("storage driver %q has no migrations", cfg.Storage.Driver)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	auth_svc "github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// cli runs commands with application services. Services check roles,
+// so every command passes role which is allowed to do the action,
+// audit actor is taken from ctx.
+type cli struct {
+	auth      *auth_svc.AuthService
+	pvz       *pvz_svc.PVZService
+	reception *reception_svc.ReceptionService
+
+	// read-only commands without service methods use repositories directly
+	receptionRepo reception_domain.ReceptionRepository
+	productRepo   product_domain.ProductRepository
+
+	// stdin is read by commands which take secrets, see readPassword
+	stdin  io.Reader
+	out    *printer
+	stderr io.Writer
+}
+
+type commandFunc func(ctx context.Context, c *cli, args []string) error
+
+var commands = map[string]commandFunc{
+	"user create":       userCreate,
+	"user role":         userRole,
+	"pvz create":        pvzCreate,
+	"pvz list":          pvzList,
+	"reception open":    receptionOpen,
+	"reception close":   receptionClose,
+	"reception inspect": receptionInspect,
+	"product list":      productList,
+	"export":            export,
+}
+
+type boundCommand struct {
+	run  commandFunc
+	args []string
+}
+
+// lookupCommand finds command by first one or two words of args.
+func lookupCommand(args []string) (boundCommand, bool) {
+	if len(args) >= 2 {
+		if run, ok := commands[args[0]+" "+args[1]]; ok {
+			return boundCommand{run: run, args: args[2:]}, true
+		}
+	}
+
+	if len(args) >= 1 {
+		if run, ok := commands[args[0]]; ok {
+			return boundCommand{run: run, args: args[1:]}, true
+		}
+	}
+
+	return boundCommand{}, false
+}
+
+// parseFlags parses command flags, usage errors are wrapped with errUsage.
+func (c *cli) parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %s", errUsage, strings.Join(fs.Args(), " "))
+	}
+
+	return nil
+}
+
+func required(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%w: -%s is required", errUsage, name)
+	}
+
+	return nil
+}
+
+// parseTime parses optional RFC3339 time flag.
+func parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: -%s: %w", errUsage, name, err)
+	}
+
+	return &t, nil
+}
+
+// passwordEnv is environment variable with password for user create.
+const passwordEnv = "PVZCTL_PASSWORD"
+
+// readPassword returns password from PVZCTL_PASSWORD or, if it is not set,
+// from first line of stdin. There is no flag for password,
+// command line is visible in ps output and shell history.
+func readPassword(stdin io.Reader) (string, error) {
+	if password := os.Getenv(passwordEnv); password != "" {
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("%w: password is required, set %s or pass it on stdin", errUsage, passwordEnv)
+	}
+
+	return password, nil
+}
+
+// actorEmail returns email of audit actor, it is written to receptions closed by cli.
+func actorEmail(ctx context.Context) string {
+	actor, _ := requestinfo.ActorFrom(ctx)
+	return actor.Email
+}
+
+func userCreate(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "user email")
+	role := fs.String("role", "", "employee or moderator")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := errors.Join(required("email", *email), required("role", *role)); err != nil {
+		return err
+	}
+
+	password, err := readPassword(c.stdin)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.auth.Register(ctx, auth_svc.RegisterParams{
+		Email:    auth_domain.Email(*email),
+		Password: password,
+		Role:     auth_domain.Role(*role),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.users(user)
+}
+
+func userRole(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("user role", flag.ContinueOnError)
+	email := fs.String("email", "", "user email")
+	role := fs.String("role", "", "new role, employee or moderator")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := errors.Join(required("email", *email), required("role", *role)); err != nil {
+		return err
+	}
+
+	user, err := c.auth.ChangeRole(ctx, auth_svc.ChangeRoleParams{
+		Email:    auth_domain.Email(*email),
+		Role:     auth_domain.Role(*role),
+		UserRole: auth_domain.RoleModerator,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.users(user)
+}
+
+func pvzCreate(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("pvz create", flag.ContinueOnError)
+	city := fs.String("city", "", "pvz city")
+	id := fs.String("id", "", "pvz id, generated if empty")
+	regDate := fs.String("registration-date", "", "registration date in RFC3339, now if empty")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := required("city", *city); err != nil {
+		return err
+	}
+
+	params := pvz_svc.CreateParams{
+		City:     pvz_domain.City(*city),
+		UserRole: auth_domain.RoleModerator,
+	}
+	if *id != "" {
+		params.ID = id
+	}
+
+	var err error
+	if params.RegistrationDate, err = parseTime("registration-date", *regDate); err != nil {
+		return err
+	}
+
+	pvz, err := c.pvz.Create(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return c.out.pvzs(pvz)
+}
+
+func pvzList(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("pvz list", flag.ContinueOnError)
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	pvzs, err := c.pvz.ListAllPVZs(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.out.pvzs(pvzs...)
+}
+
+func receptionOpen(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("reception open", flag.ContinueOnError)
+	pvzID := fs.String("pvz", "", "pvz id")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := required("pvz", *pvzID); err != nil {
+		return err
+	}
+
+	reception, err := c.reception.Create(ctx, reception_svc.CreateParams{
+		PVZID:    *pvzID,
+		UserRole: auth_domain.RoleEmployee,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.receptions(reception)
+}
+
+func receptionClose(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("reception close", flag.ContinueOnError)
+	pvzID := fs.String("pvz", "", "pvz id, its last open reception is closed")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := required("pvz", *pvzID); err != nil {
+		return err
+	}
+
+	reception, err := c.pvz.CloseLastReception(ctx, pvz_svc.CloseLastReceptionParams{
+		PVZID:     *pvzID,
+		UserRole:  auth_domain.RoleEmployee,
+		UserEmail: actorEmail(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.receptions(reception)
+}
+
+func receptionInspect(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("reception inspect", flag.ContinueOnError)
+	id := fs.String("id", "", "reception id")
+	pvzID := fs.String("pvz", "", "pvz id, its last open reception is shown")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if (*id == "") == (*pvzID == "") {
+		return fmt.Errorf("%w: exactly one of -id and -pvz is required", errUsage)
+	}
+
+	var (
+		reception *reception_domain.Reception
+		err       error
+	)
+	if *id != "" {
+		reception, err = c.receptionRepo.FindByID(ctx, *id)
+	} else {
+		reception, err = c.receptionRepo.FindLastOpenByPVZ(ctx, *pvzID)
+	}
+	if err != nil {
+		return err
+	}
+
+	products, err := c.productRepo.ListByReception(ctx, reception.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.out.receptionWithProducts(reception, products)
+}
+
+func productList(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("product list", flag.ContinueOnError)
+	receptionID := fs.String("reception", "", "reception id")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := required("reception", *receptionID); err != nil {
+		return err
+	}
+
+	products, err := c.productRepo.ListByReception(ctx, *receptionID)
+	if err != nil {
+		return err
+	}
+
+	return c.out.products(products...)
+}
+
+// export dumps all pvz with receptions and alive products.
+// Receptions are filtered by opening time if -from or -to is set.
+func export(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "receptions opened at or after, RFC3339")
+	toFlag := fs.String("to", "", "receptions opened at or before, RFC3339")
+	if err := c.parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	from, err := parseTime("from", *fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseTime("to", *toFlag)
+	if err != nil {
+		return err
+	}
+
+	pvzs, err := c.pvz.ListAllPVZs(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := make([]*pvz_domain.PVZWithReceptions, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		receptions, err := c.receptionRepo.ListByPVZ(ctx, *pvz.ID)
+		if err != nil {
+			return err
+		}
+
+		item := &pvz_domain.PVZWithReceptions{PVZ: pvz}
+		for _, r := range receptions {
+			if (from != nil && r.DateTime.Before(*from)) || (to != nil && r.DateTime.After(*to)) {
+				continue
+			}
+
+			products, err := c.productRepo.ListByReception(ctx, r.ID)
+			if err != nil {
+				return err
+			}
+
+			item.Receptions = append(item.Receptions, &pvz_domain.ReceptionWithProducts{
+				Reception: r,
+				Products:  products,
+			})
+		}
+
+		result = append(result, item)
+	}
+
+	return c.out.export(result)
+}
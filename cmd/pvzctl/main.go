@@ -0,0 +1,170 @@
+// pvzctl is admin cli for operational tasks: seeding users, creating pvz,
+// closing stuck receptions and exporting data. It works with same database
+// and same application services as app, so actions are validated and audited.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	audit_svc "github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_db "github.com/0x0FACED/pvz-avito/internal/audit/infra/postgres"
+	auth_svc "github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_db "github.com/0x0FACED/pvz-avito/internal/auth/infra/postgres"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	product_db "github.com/0x0FACED/pvz-avito/internal/product/infra/postgres"
+	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_db "github.com/0x0FACED/pvz-avito/internal/pvz/infra/postgres"
+	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_db "github.com/0x0FACED/pvz-avito/internal/reception/infra/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const usage = `usage: pvzctl [flags] <command> <subcommand> [args]
+
+commands:
+  user create -email EMAIL -role ROLE
+      password is taken from PVZCTL_PASSWORD or first line of stdin
+  user role -email EMAIL -role ROLE
+  pvz create -city CITY [-id ID] [-registration-date RFC3339]
+  pvz list
+  reception open -pvz PVZ_ID
+  reception close -pvz PVZ_ID
+  reception inspect (-id RECEPTION_ID | -pvz PVZ_ID)
+  product list -reception RECEPTION_ID
+  export [-from RFC3339] [-to RFC3339]
+
+flags:`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// errUsage is returned for invalid command line, exit code is 2.
+var errUsage = errors.New("invalid usage")
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("pvzctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	output := fs.String("o", formatTable, "output format: table or json")
+	actor := fs.String("actor", "pvzctl", "actor email written to audit log")
+	logLevel := fs.String("log-level", "disabled", "log level of application services, logs are written to stderr")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *output != formatTable && *output != formatJSON {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *output)
+		return 2
+	}
+
+	cmd, ok := lookupCommand(fs.Args())
+	if !ok {
+		fs.Usage()
+		return 2
+	}
+
+	l, err := logger.NewConsoleLogger(stderr, *logLevel)
+	if err != nil {
+		fmt.Fprintln(stderr, "failed to create logger:", err)
+		return 2
+	}
+
+	cfg := config.MustLoadCLI()
+
+	pool, err := database.ConnectPool(ctx, cfg.Database)
+	if err != nil {
+		fmt.Fprintln(stderr, "failed to connect to database:", err)
+		return 1
+	}
+	defer pool.Close()
+
+	c, err := newCLI(pool, cfg.Auth, l, stdin, newPrinter(stdout, *output), stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	// audit entries of cli actions are attributed to actor
+	ctx = requestinfo.WithActor(ctx, requestinfo.Actor{Email: *actor, Role: "admin"})
+
+	if err := cmd.run(ctx, c, cmd.args); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		return 1
+	}
+
+	return 0
+}
+
+func newCLI(pool *pgxpool.Pool, authCfg config.AuthConfig, l *logger.ZerologLogger, stdin io.Reader, p *printer, stderr io.Writer) (*cli, error) {
+	authRepo := auth_db.NewAuthPostgresRepository(pool)
+	pvzRepo := pvz_db.NewPVZPostgresRepository(pool)
+	productRepo := product_db.NewProductPostgresRepository(pool)
+	receptionRepo := reception_db.NewReceptionPostgresRepository(pool)
+	auditRepo := audit_db.NewAuditPostgresRepository(pool)
+
+	auditSvc := audit_svc.NewAuditService(auditRepo, l.WithFeature("audit_svc"))
+
+	hasher, err := auth_svc.NewMultiHasher(
+		authCfg.PasswordHashAlgo,
+		auth_svc.NewBcryptHasher(authCfg.BcryptCost),
+		auth_svc.NewArgon2idHasher(auth_svc.Argon2Params{
+			Time:    authCfg.Argon2Time,
+			Memory:  authCfg.Argon2Memory,
+			Threads: authCfg.Argon2Threads,
+			KeyLen:  authCfg.Argon2KeyLen,
+			SaltLen: authCfg.Argon2SaltLen,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password hasher: %w", err)
+	}
+
+	passwordPolicy := auth_svc.PasswordPolicy{
+		MinLength:      authCfg.PasswordMinLength,
+		MaxLength:      authCfg.PasswordMaxLength,
+		RequireUpper:   authCfg.PasswordRequireUpper,
+		RequireLower:   authCfg.PasswordRequireLower,
+		RequireDigit:   authCfg.PasswordRequireDigit,
+		RequireSpecial: authCfg.PasswordRequireSpecial,
+	}
+	if authCfg.PasswordDenylistFile != "" {
+		passwordPolicy.Denylist, err = auth_svc.LoadDenylist(authCfg.PasswordDenylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load password denylist: %w", err)
+		}
+	}
+
+	return &cli{
+		// cli does not log users in, so login guard is not needed
+		auth:          auth_svc.NewAuthService(authRepo, nil, hasher, passwordPolicy, auditSvc, l.WithFeature("auth_svc")),
+		pvz:           pvz_svc.NewPVZService(pvzRepo, receptionRepo, productRepo, auditSvc, l.WithFeature("pvz_svc")),
+		reception:     reception_svc.NewReceptionService(receptionRepo, auditSvc, l.WithFeature("reception_svc")),
+		receptionRepo: receptionRepo,
+		productRepo:   productRepo,
+		stdin:         stdin,
+		out:           p,
+		stderr:        stderr,
+	}, nil
+}
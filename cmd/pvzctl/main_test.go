@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOK   bool
+		wantArgs []string
+	}{
+		{name: "two words", args: []string{"pvz", "create", "-city", "Казань"}, wantOK: true, wantArgs: []string{"-city", "Казань"}},
+		{name: "one word", args: []string{"export", "-from", "2025-01-01T00:00:00Z"}, wantOK: true, wantArgs: []string{"-from", "2025-01-01T00:00:00Z"}},
+		{name: "unknown subcommand", args: []string{"pvz", "delete"}},
+		{name: "group only", args: []string{"pvz"}},
+		{name: "empty", args: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := lookupCommand(tt.args)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantArgs, cmd.args)
+			}
+		})
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: nil},
+		{name: "unknown command", args: []string{"pvz", "delete"}},
+		{name: "unknown output", args: []string{"-o", "yaml", "pvz", "list"}},
+		{name: "unknown flag", args: []string{"-unknown", "pvz", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(""), &stdout, &stderr)
+			assert.Equal(t, 2, code)
+			assert.Empty(t, stdout.String())
+			assert.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv(passwordEnv, "Passw0rd!")
+
+		password, err := readPassword(strings.NewReader("ignored\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Passw0rd!", password)
+	})
+
+	t.Run("first line of stdin", func(t *testing.T) {
+		t.Setenv(passwordEnv, "")
+
+		password, err := readPassword(strings.NewReader("Passw0rd!\r\nsecond line\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Passw0rd!", password)
+	})
+
+	t.Run("stdin without newline", func(t *testing.T) {
+		t.Setenv(passwordEnv, "")
+
+		password, err := readPassword(strings.NewReader("Passw0rd!"))
+		require.NoError(t, err)
+		assert.Equal(t, "Passw0rd!", password)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Setenv(passwordEnv, "")
+
+		_, err := readPassword(strings.NewReader(""))
+		assert.ErrorIs(t, err, errUsage)
+	})
+}
+
+func TestPrinterExport(t *testing.T) {
+	pvzID := "11111111-1111-1111-1111-111111111111"
+	regDate := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	opened := regDate.Add(time.Hour)
+
+	items := []*pvz_domain.PVZWithReceptions{
+		{
+			PVZ: &pvz_domain.PVZ{ID: &pvzID, RegistrationDate: &regDate, City: pvz_domain.Kazan},
+			Receptions: []*pvz_domain.ReceptionWithProducts{
+				{
+					Reception: &reception_domain.Reception{ID: "r1", DateTime: opened, PVZID: pvzID, Status: reception_domain.InProgress},
+					Products: []*product_domain.Product{
+						{ID: "p1", DateTime: opened, Type: product_domain.Shoes, ReceptionID: "r1"},
+						{ID: "p2", DateTime: opened, Type: product_domain.Clothes, ReceptionID: "r1"},
+					},
+				},
+				{
+					Reception: &reception_domain.Reception{ID: "r2", DateTime: opened, PVZID: pvzID, Status: reception_domain.Close},
+				},
+			},
+		},
+	}
+
+	t.Run("table has row per product", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newPrinter(&buf, formatTable).export(items))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 4)
+		assert.Contains(t, string(lines[0]), "PRODUCT ID")
+		assert.Contains(t, string(lines[1]), "p1")
+		assert.Contains(t, string(lines[2]), "p2")
+		assert.Contains(t, string(lines[3]), "r2")
+	})
+
+	t.Run("json is nested", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newPrinter(&buf, formatJSON).export(items))
+
+		var got []pvzWithReceptionsView
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Len(t, got, 1)
+		assert.Equal(t, pvzID, got[0].PVZ.ID)
+		require.Len(t, got[0].Receptions, 2)
+		assert.Len(t, got[0].Receptions[0].Products, 2)
+		assert.Empty(t, got[0].Receptions[1].Products)
+	})
+}
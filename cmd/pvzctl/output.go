@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes command results as aligned table or indented json.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, format: format}
+}
+
+// print writes v as json, or header and rows as table.
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	if p.format == formatJSON {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+type userView struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type pvzView struct {
+	ID               string     `json:"id"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
+	City             string     `json:"city"`
+}
+
+type receptionView struct {
+	ID          string     `json:"id"`
+	DateTime    time.Time  `json:"dateTime"`
+	PVZID       string     `json:"pvzId"`
+	Status      string     `json:"status"`
+	CloseReason string     `json:"closeReason,omitempty"`
+	ClosedAt    *time.Time `json:"closedAt,omitempty"`
+	ClosedBy    string     `json:"closedBy,omitempty"`
+}
+
+type productView struct {
+	ID          string    `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	Type        string    `json:"type"`
+	ReceptionID string    `json:"receptionId"`
+}
+
+type receptionWithProductsView struct {
+	Reception receptionView `json:"reception"`
+	Products  []productView `json:"products"`
+}
+
+type pvzWithReceptionsView struct {
+	PVZ        pvzView                     `json:"pvz"`
+	Receptions []receptionWithProductsView `json:"receptions"`
+}
+
+func newUserView(u *auth_domain.User) userView {
+	return userView{ID: u.ID, Email: u.Email.String(), Role: u.Role.String()}
+}
+
+func newPVZView(p *pvz_domain.PVZ) pvzView {
+	v := pvzView{RegistrationDate: p.RegistrationDate, City: p.City.String()}
+	if p.ID != nil {
+		v.ID = *p.ID
+	}
+
+	return v
+}
+
+func newReceptionView(r *reception_domain.Reception) receptionView {
+	return receptionView{
+		ID:          r.ID,
+		DateTime:    r.DateTime,
+		PVZID:       r.PVZID,
+		Status:      r.Status.String(),
+		CloseReason: r.CloseReason.String(),
+		ClosedAt:    r.ClosedAt,
+		ClosedBy:    r.ClosedBy,
+	}
+}
+
+func newProductView(p *product_domain.Product) productView {
+	return productView{ID: p.ID, DateTime: p.DateTime, Type: p.Type.String(), ReceptionID: p.ReceptionID}
+}
+
+func newProductViews(products []*product_domain.Product) []productView {
+	views := make([]productView, 0, len(products))
+	for _, p := range products {
+		views = append(views, newProductView(p))
+	}
+
+	return views
+}
+
+var (
+	userHeader      = []string{"ID", "EMAIL", "ROLE"}
+	pvzHeader       = []string{"ID", "CITY", "REGISTRATION DATE"}
+	receptionHeader = []string{"ID", "PVZ ID", "STATUS", "OPENED AT", "CLOSED AT", "CLOSE REASON", "CLOSED BY"}
+	productHeader   = []string{"ID", "TYPE", "RECEPTION ID", "ADDED AT"}
+	exportHeader    = []string{"PVZ ID", "CITY", "RECEPTION ID", "STATUS", "OPENED AT", "CLOSED AT", "PRODUCT ID", "TYPE", "ADDED AT"}
+)
+
+func (v userView) row() []string {
+	return []string{v.ID, v.Email, v.Role}
+}
+
+func (v pvzView) row() []string {
+	return []string{v.ID, v.City, formatTime(v.RegistrationDate)}
+}
+
+func (v receptionView) row() []string {
+	return []string{v.ID, v.PVZID, v.Status, formatTime(&v.DateTime), formatTime(v.ClosedAt), v.CloseReason, v.ClosedBy}
+}
+
+func (v productView) row() []string {
+	return []string{v.ID, v.Type, v.ReceptionID, formatTime(&v.DateTime)}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func (p *printer) users(users ...*auth_domain.User) error {
+	views := make([]userView, 0, len(users))
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		v := newUserView(u)
+		views = append(views, v)
+		rows = append(rows, v.row())
+	}
+
+	return p.print(views, userHeader, rows)
+}
+
+func (p *printer) pvzs(pvzs ...*pvz_domain.PVZ) error {
+	views := make([]pvzView, 0, len(pvzs))
+	rows := make([][]string, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		v := newPVZView(pvz)
+		views = append(views, v)
+		rows = append(rows, v.row())
+	}
+
+	return p.print(views, pvzHeader, rows)
+}
+
+func (p *printer) receptions(receptions ...*reception_domain.Reception) error {
+	views := make([]receptionView, 0, len(receptions))
+	rows := make([][]string, 0, len(receptions))
+	for _, r := range receptions {
+		v := newReceptionView(r)
+		views = append(views, v)
+		rows = append(rows, v.row())
+	}
+
+	return p.print(views, receptionHeader, rows)
+}
+
+func (p *printer) products(products ...*product_domain.Product) error {
+	views := newProductViews(products)
+	rows := make([][]string, 0, len(views))
+	for _, v := range views {
+		rows = append(rows, v.row())
+	}
+
+	return p.print(views, productHeader, rows)
+}
+
+// receptionWithProducts prints reception, table output has products below it.
+func (p *printer) receptionWithProducts(r *reception_domain.Reception, products []*product_domain.Product) error {
+	if p.format == formatJSON {
+		return p.print(receptionWithProductsView{
+			Reception: newReceptionView(r),
+			Products:  newProductViews(products),
+		}, nil, nil)
+	}
+
+	if err := p.receptions(r); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.w, "\nproducts: %d\n", len(products))
+
+	return p.products(products...)
+}
+
+// export prints nested json or flat table with one row per product,
+// receptions without products and pvz without receptions get one row too.
+func (p *printer) export(items []*pvz_domain.PVZWithReceptions) error {
+	views := make([]pvzWithReceptionsView, 0, len(items))
+	var rows [][]string
+	for _, item := range items {
+		pv := newPVZView(item.PVZ)
+		view := pvzWithReceptionsView{PVZ: pv, Receptions: make([]receptionWithProductsView, 0, len(item.Receptions))}
+
+		if len(item.Receptions) == 0 {
+			rows = append(rows, []string{pv.ID, pv.City, "-", "-", "-", "-", "-", "-", "-"})
+		}
+
+		for _, r := range item.Receptions {
+			rv := newReceptionView(r.Reception)
+			products := newProductViews(r.Products)
+			view.Receptions = append(view.Receptions, receptionWithProductsView{Reception: rv, Products: products})
+
+			receptionCols := []string{pv.ID, pv.City, rv.ID, rv.Status, formatTime(&rv.DateTime), formatTime(rv.ClosedAt)}
+			if len(products) == 0 {
+				rows = append(rows, append(receptionCols, "-", "-", "-"))
+			}
+			for _, prod := range products {
+				rows = append(rows, append(receptionCols[:len(receptionCols):len(receptionCols)], prod.ID, prod.Type, formatTime(&prod.DateTime)))
+			}
+		}
+
+		views = append(views, view)
+	}
+
+	return p.print(views, exportHeader, rows)
+}
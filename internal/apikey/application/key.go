@@ -0,0 +1,49 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Key format is pvz_<prefix>_<secret>, prefix is 12 hex chars and is stored
+// as is for lookup, secret is 64 hex chars (32 random bytes).
+const (
+	keyPrefix    = "pvz"
+	prefixBytes  = 6
+	secretBytes  = 32
+	keySeparator = "_"
+)
+
+var errMalformedKey = errors.New("malformed key")
+
+func generateKey() (key, prefix string, err error) {
+	buf := make([]byte, prefixBytes+secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(buf[:prefixBytes])
+	secret := hex.EncodeToString(buf[prefixBytes:])
+
+	return keyPrefix + keySeparator + prefix + keySeparator + secret, prefix, nil
+}
+
+func parseKey(key string) (prefix string, err error) {
+	parts := strings.Split(key, keySeparator)
+	if len(parts) != 3 || parts[0] != keyPrefix ||
+		len(parts[1]) != prefixBytes*2 || len(parts[2]) != secretBytes*2 {
+		return "", errMalformedKey
+	}
+
+	return parts[1], nil
+}
+
+// hashKey is sha256 of whole key. Key has 256 bits of entropy,
+// so slow password hash is not needed and lookup stays cheap.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
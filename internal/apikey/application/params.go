@@ -0,0 +1,76 @@
+package application
+
+import (
+	"fmt"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+const MaxNameLength = 100
+
+type CreateParams struct {
+	Name      string
+	Scopes    []apikey_domain.Scope
+	PVZID     string
+	UserRole  auth_domain.Role
+	UserEmail string
+}
+
+func (p CreateParams) Validate() error {
+	if p.Name == "" || len(p.Name) > MaxNameLength {
+		return fmt.Errorf("%w: name must be 1..%d characters", apikey_domain.ErrInvalidName, MaxNameLength)
+	}
+
+	if len(p.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", apikey_domain.ErrInvalidScope)
+	}
+
+	for _, scope := range p.Scopes {
+		if err := scope.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.PVZID != "" {
+		if err := uuid.Validate(p.PVZID); err != nil {
+			return fmt.Errorf("%w: %w", apikey_domain.ErrInvalidIDFormat, err)
+		}
+	}
+
+	if p.UserRole != auth_domain.RoleModerator {
+		return apikey_domain.ErrAccessDenied
+	}
+
+	return nil
+}
+
+type ListParams struct {
+	UserRole auth_domain.Role
+}
+
+func (p ListParams) Validate() error {
+	if p.UserRole != auth_domain.RoleModerator {
+		return apikey_domain.ErrAccessDenied
+	}
+
+	return nil
+}
+
+type RevokeParams struct {
+	ID       string
+	UserRole auth_domain.Role
+}
+
+func (p RevokeParams) Validate() error {
+	if err := uuid.Validate(p.ID); err != nil {
+		return fmt.Errorf("%w: %w", apikey_domain.ErrInvalidIDFormat, err)
+	}
+
+	if p.UserRole != auth_domain.RoleModerator {
+		return apikey_domain.ErrAccessDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateParams_Validate(t *testing.T) {
+	valid := application.CreateParams{
+		Name:     "carrier",
+		Scopes:   []apikey_domain.Scope{apikey_domain.ScopeReceptionCreate, apikey_domain.ScopeProductAdd},
+		PVZID:    uuid.NewString(),
+		UserRole: auth_domain.RoleModerator,
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(p *application.CreateParams)
+		expectErr error
+	}{
+		{"valid", func(p *application.CreateParams) {}, nil},
+		{"without pvz restriction", func(p *application.CreateParams) { p.PVZID = "" }, nil},
+		{"empty name", func(p *application.CreateParams) { p.Name = "" }, apikey_domain.ErrInvalidName},
+		{"no scopes", func(p *application.CreateParams) { p.Scopes = nil }, apikey_domain.ErrInvalidScope},
+		{"unknown scope", func(p *application.CreateParams) { p.Scopes = []apikey_domain.Scope{"pvz:delete"} }, apikey_domain.ErrInvalidScope},
+		{"invalid pvz id", func(p *application.CreateParams) { p.PVZID = "123" }, apikey_domain.ErrInvalidIDFormat},
+		{"employee", func(p *application.CreateParams) { p.UserRole = auth_domain.RoleEmployee }, apikey_domain.ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+
+			err := p.Validate()
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRevokeParams_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    application.RevokeParams
+		expectErr error
+	}{
+		{"valid", application.RevokeParams{ID: uuid.NewString(), UserRole: auth_domain.RoleModerator}, nil},
+		{"invalid id", application.RevokeParams{ID: "123", UserRole: auth_domain.RoleModerator}, apikey_domain.ErrInvalidIDFormat},
+		{"employee", application.RevokeParams{ID: uuid.NewString(), UserRole: auth_domain.RoleEmployee}, apikey_domain.ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+// lastUsedResolution limits last_used_at writes to one per key per minute.
+const lastUsedResolution = time.Minute
+
+type APIKeyService struct {
+	repo    apikey_domain.APIKeyRepository
+	auditor audit_domain.Auditor
+
+	log *logger.ZerologLogger
+}
+
+func NewAPIKeyService(repo apikey_domain.APIKeyRepository, auditor audit_domain.Auditor, l *logger.ZerologLogger) *APIKeyService {
+	return &APIKeyService{
+		repo:    repo,
+		auditor: auditor,
+		log:     l,
+	}
+}
+
+// apiKeyState is api key representation for audit log, without hash.
+type apiKeyState struct {
+	ID        string                `json:"id"`
+	Name      string                `json:"name"`
+	Prefix    string                `json:"prefix"`
+	Scopes    []apikey_domain.Scope `json:"scopes"`
+	PVZID     string                `json:"pvz_id,omitempty"`
+	RevokedAt *time.Time            `json:"revoked_at,omitempty"`
+}
+
+func newAPIKeyState(k *apikey_domain.APIKey) *apiKeyState {
+	return &apiKeyState{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		PVZID:     k.PVZID,
+		RevokedAt: k.RevokedAt,
+	}
+}
+
+// Create returns created key and plain key, which is never shown again.
+func (s *APIKeyService) Create(ctx context.Context, params CreateParams) (_ *apikey_domain.APIKey, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Create")
+		return nil, "", err
+	}
+
+	plain, prefix, err := generateKey()
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error generating api key")
+		return nil, "", err
+	}
+
+	key := &apikey_domain.APIKey{
+		ID:        uuid.NewString(),
+		Name:      params.Name,
+		Prefix:    prefix,
+		Hash:      hashKey(plain),
+		Scopes:    params.Scopes,
+		PVZID:     params.PVZID,
+		CreatedBy: params.UserEmail,
+		CreatedAt: time.Now(),
+	}
+
+	created, err := s.repo.Create(ctx, key)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error creating api key")
+		return nil, "", err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionAPIKeyCreate,
+		EntityType: audit_domain.EntityAPIKey,
+		EntityID:   created.ID,
+		After:      newAPIKeyState(created),
+	})
+
+	s.log.Info().Ctx(ctx).Str("id", created.ID).Str("prefix", created.Prefix).Msg("Create successful")
+
+	return created, plain, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, params ListParams) (_ []*apikey_domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.List")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("List")
+		return nil, err
+	}
+
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error listing api keys")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, params RevokeParams) (_ *apikey_domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Revoke")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Revoke")
+		return nil, err
+	}
+
+	revoked, err := s.repo.Revoke(ctx, params.ID, time.Now())
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error revoking api key")
+		return nil, err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionAPIKeyRevoke,
+		EntityType: audit_domain.EntityAPIKey,
+		EntityID:   revoked.ID,
+		After:      newAPIKeyState(revoked),
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Msg("Revoke successful")
+
+	return revoked, nil
+}
+
+// Authenticate finds key by prefix and checks its hash.
+// Unknown and malformed keys are both ErrInvalidAPIKey.
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (_ *apikey_domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer func() { tracing.End(span, err) }()
+
+	prefix, err := parseKey(plain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInvalidAPIKey, err)
+	}
+
+	key, err := s.repo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey_domain.ErrAPIKeyNotFound) {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInvalidAPIKey, err)
+		}
+		s.log.Error().Ctx(ctx).Str("prefix", prefix).Err(err).Msg("Error finding api key")
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashKey(plain))) != 1 {
+		return nil, fmt.Errorf("%w: hash mismatch", apikey_domain.ErrInvalidAPIKey)
+	}
+
+	if key.IsRevoked() {
+		return nil, apikey_domain.ErrAPIKeyRevoked
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// usage tracking must not fail request
+		if err := s.repo.TouchLastUsed(context.WithoutCancel(ctx), key.ID, now); err != nil {
+			s.log.Error().Ctx(ctx).Str("id", key.ID).Err(err).Msg("Error updating api key last usage")
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,253 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/apikey/mocks"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// createKey creates key through service and returns stored key and plain key.
+func createKey(t *testing.T, ctrl *gomock.Controller) (*apikey_domain.APIKey, string) {
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *apikey_domain.APIKey) (*apikey_domain.APIKey, error) {
+			return k, nil
+		})
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+	svc := application.NewAPIKeyService(repo, auditor, logger.NewTestLogger())
+
+	key, plain, err := svc.Create(context.Background(), application.CreateParams{
+		Name:      "carrier",
+		Scopes:    []apikey_domain.Scope{apikey_domain.ScopeProductAdd},
+		UserRole:  auth_domain.RoleModerator,
+		UserEmail: "moderator@example.com",
+	})
+	require.NoError(t, err)
+
+	return key, plain
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, plain := createKey(t, ctrl)
+
+	assert.True(t, strings.HasPrefix(plain, "pvz_"+key.Prefix+"_"))
+	assert.NotContains(t, key.Hash, plain)
+	assert.NotEqual(t, plain, key.Hash)
+	assert.Equal(t, "moderator@example.com", key.CreatedBy)
+
+	_, other := createKey(t, ctrl)
+	assert.NotEqual(t, plain, other)
+}
+
+func TestCreate_Audit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var hash string
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *apikey_domain.APIKey) (*apikey_domain.APIKey, error) {
+			hash = k.Hash
+			return k, nil
+		})
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().Record(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, e audit_domain.Event) {
+			assert.Equal(t, audit_domain.ActionAPIKeyCreate, e.Action)
+
+			after, err := json.Marshal(e.After)
+			require.NoError(t, err)
+			assert.NotContains(t, string(after), hash)
+		})
+
+	svc := application.NewAPIKeyService(repo, auditor, logger.NewTestLogger())
+
+	_, _, err := svc.Create(context.Background(), application.CreateParams{
+		Name:     "carrier",
+		Scopes:   []apikey_domain.Scope{apikey_domain.ScopePVZRead},
+		UserRole: auth_domain.RoleModerator,
+	})
+	require.NoError(t, err)
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored, plain := createKey(t, ctrl)
+
+	recently := time.Now().Add(-time.Second)
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		key       string
+		mockSetup func(m *mocks.MockAPIKeyRepository)
+		expectErr error
+	}{
+		{
+			name: "valid, last usage updated",
+			key:  plain,
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				k := *stored
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(&k, nil)
+				m.EXPECT().TouchLastUsed(gomock.Any(), stored.ID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "valid, recently used",
+			key:  plain,
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				k := *stored
+				k.LastUsedAt = &recently
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(&k, nil)
+			},
+		},
+		{
+			name: "touch failed, still authenticated",
+			key:  plain,
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				k := *stored
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(&k, nil)
+				m.EXPECT().TouchLastUsed(gomock.Any(), stored.ID, gomock.Any()).Return(apikey_domain.ErrInternalDatabase)
+			},
+		},
+		{
+			name:      "malformed",
+			key:       "not-a-key",
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {},
+			expectErr: apikey_domain.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown prefix",
+			key:  plain,
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(nil, apikey_domain.ErrAPIKeyNotFound)
+			},
+			expectErr: apikey_domain.ErrInvalidAPIKey,
+		},
+		{
+			name: "wrong secret",
+			key:  plain[:len(plain)-1] + flipHex(plain[len(plain)-1]),
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				k := *stored
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(&k, nil)
+			},
+			expectErr: apikey_domain.ErrInvalidAPIKey,
+		},
+		{
+			name: "revoked",
+			key:  plain,
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				k := *stored
+				k.RevokedAt = &revokedAt
+				m.EXPECT().FindByPrefix(gomock.Any(), stored.Prefix).Return(&k, nil)
+			},
+			expectErr: apikey_domain.ErrAPIKeyRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mockSetup(repo)
+
+			svc := application.NewAPIKeyService(repo, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+
+			key, err := svc.Authenticate(context.Background(), tt.key)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, key)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, stored.ID, key.ID)
+			}
+		})
+	}
+}
+
+func flipHex(c byte) string {
+	if c == '0' {
+		return "1"
+	}
+	return "0"
+}
+
+func TestRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		params    application.RevokeParams
+		mockSetup func(m *mocks.MockAPIKeyRepository)
+		expectErr error
+	}{
+		{
+			name:   "revoked",
+			params: application.RevokeParams{ID: id, UserRole: auth_domain.RoleModerator},
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				now := time.Now()
+				m.EXPECT().Revoke(gomock.Any(), id, gomock.Any()).Return(&apikey_domain.APIKey{ID: id, RevokedAt: &now}, nil)
+			},
+		},
+		{
+			name:   "not found",
+			params: application.RevokeParams{ID: id, UserRole: auth_domain.RoleModerator},
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().Revoke(gomock.Any(), id, gomock.Any()).Return(nil, apikey_domain.ErrAPIKeyNotFound)
+			},
+			expectErr: apikey_domain.ErrAPIKeyNotFound,
+		},
+		{
+			name:      "access denied",
+			params:    application.RevokeParams{ID: id, UserRole: auth_domain.RoleEmployee},
+			mockSetup: func(m *mocks.MockAPIKeyRepository) {},
+			expectErr: apikey_domain.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mockSetup(repo)
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			svc := application.NewAPIKeyService(repo, auditor, logger.NewTestLogger())
+
+			key, err := svc.Revoke(context.Background(), tt.params)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, key.IsRevoked())
+			}
+		})
+	}
+}
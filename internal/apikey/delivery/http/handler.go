@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, params application.CreateParams) (*apikey_domain.APIKey, string, error)
+	List(ctx context.Context, params application.ListParams) ([]*apikey_domain.APIKey, error)
+	Revoke(ctx context.Context, params application.RevokeParams) (*apikey_domain.APIKey, error)
+}
+
+type Handler struct {
+	svc APIKeyService
+}
+
+func NewHandler(svc APIKeyService) *Handler {
+	return &Handler{
+		svc: svc,
+	}
+}
+
+func (h Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api-keys", h.Create)
+	mux.HandleFunc("GET /api-keys", h.List)
+	mux.HandleFunc("DELETE /api-keys/{keyId}", h.Revoke)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
+		return
+	}
+
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	scopes := make([]apikey_domain.Scope, 0, len(req.Scopes))
+	for _, s := range req.Scopes {
+		scopes = append(scopes, apikey_domain.Scope(s))
+	}
+
+	params := application.CreateParams{
+		Name:      req.Name,
+		Scopes:    scopes,
+		PVZID:     req.PVZID,
+		UserRole:  auth_domain.Role(claims.Role),
+		UserEmail: claims.Email,
+	}
+
+	key, plain, err := h.svc.Create(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	httpcommon.JSONResponse(w, http.StatusCreated, CreateResponse{
+		APIKeyResponse: toResponse(key),
+		Key:            plain,
+	})
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	keys, err := h.svc.List(r.Context(), application.ListParams{UserRole: auth_domain.Role(claims.Role)})
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, toResponse(k))
+	}
+
+	httpcommon.JSONResponse(w, http.StatusOK, resp)
+}
+
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	params := application.RevokeParams{
+		ID:       r.PathValue("keyId"),
+		UserRole: auth_domain.Role(claims.Role),
+	}
+
+	key, err := h.svc.Revoke(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	httpcommon.JSONResponse(w, http.StatusOK, toResponse(key))
+}
+
+func toResponse(k *apikey_domain.APIKey) APIKeyResponse {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, s.String())
+	}
+
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		PVZID:      k.PVZID,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
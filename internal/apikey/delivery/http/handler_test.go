@@ -0,0 +1,193 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_http "github.com/0x0FACED/pvz-avito/internal/apikey/delivery/http"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/apikey/mocks"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pvzID := uuid.NewString()
+
+	tests := []struct {
+		name           string
+		request        apikey_http.CreateRequest
+		userRole       string
+		mockSetup      func(*mocks.MockAPIKeyService)
+		expectedStatus int
+		expectError    string
+	}{
+		{
+			name: "successful create",
+			request: apikey_http.CreateRequest{
+				Name:   "carrier",
+				Scopes: []string{"reception:create", "product:add"},
+				PVZID:  pvzID,
+			},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAPIKeyService) {
+				m.EXPECT().Create(gomock.Any(), application.CreateParams{
+					Name:      "carrier",
+					Scopes:    []apikey_domain.Scope{apikey_domain.ScopeReceptionCreate, apikey_domain.ScopeProductAdd},
+					PVZID:     pvzID,
+					UserRole:  auth_domain.RoleModerator,
+					UserEmail: "moderator@example.com",
+				}).Return(&apikey_domain.APIKey{
+					ID:        "key-1",
+					Name:      "carrier",
+					Prefix:    "abcdef012345",
+					Scopes:    []apikey_domain.Scope{apikey_domain.ScopeReceptionCreate, apikey_domain.ScopeProductAdd},
+					PVZID:     pvzID,
+					CreatedAt: time.Now(),
+				}, "pvz_abcdef012345_secret", nil)
+			},
+			expectedStatus: nethttp.StatusCreated,
+		},
+		{
+			name:     "invalid scope",
+			request:  apikey_http.CreateRequest{Name: "carrier", Scopes: []string{"pvz:delete"}},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAPIKeyService) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, "", fmt.Errorf("%w: pvz:delete", apikey_domain.ErrInvalidScope))
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectError:    "invalid scope: pvz:delete",
+		},
+		{
+			name:     "access denied",
+			request:  apikey_http.CreateRequest{Name: "carrier", Scopes: []string{"pvz:read"}},
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockAPIKeyService) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, "", apikey_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectError:    "access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockAPIKeyService(ctrl)
+			tt.mockSetup(svc)
+
+			handler := apikey_http.NewHandler(svc)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Email: "moderator@example.com",
+				Role:  tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.Create(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectError != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Equal(t, tt.expectError, errResp.Error())
+			} else {
+				var resp apikey_http.CreateResponse
+				_ = json.NewDecoder(rec.Body).Decode(&resp)
+				assert.Equal(t, "pvz_abcdef012345_secret", resp.Key)
+				assert.Equal(t, pvzID, resp.PVZID)
+				assert.Equal(t, []string{"reception:create", "product:add"}, resp.Scopes)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mocks.NewMockAPIKeyService(ctrl)
+	svc.EXPECT().List(gomock.Any(), application.ListParams{UserRole: auth_domain.RoleModerator}).
+		Return([]*apikey_domain.APIKey{
+			{ID: "key-1", Name: "carrier", Prefix: "abcdef012345", Hash: "secret-hash"},
+		}, nil)
+
+	handler := apikey_http.NewHandler(svc)
+
+	req := httptest.NewRequest("GET", "/api-keys", nil)
+	req = req.WithContext(context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{Role: "moderator"}))
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, req)
+
+	assert.Equal(t, nethttp.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret-hash")
+
+	var resp []apikey_http.APIKeyResponse
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	assert.Len(t, resp, 1)
+}
+
+func TestAPIKeyHandler_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockAPIKeyService)
+		expectedStatus int
+	}{
+		{
+			name: "revoked",
+			mockSetup: func(m *mocks.MockAPIKeyService) {
+				m.EXPECT().Revoke(gomock.Any(), application.RevokeParams{ID: id, UserRole: auth_domain.RoleModerator}).
+					Return(&apikey_domain.APIKey{ID: id, RevokedAt: &now}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+		},
+		{
+			name: "not found",
+			mockSetup: func(m *mocks.MockAPIKeyService) {
+				m.EXPECT().Revoke(gomock.Any(), gomock.Any()).Return(nil, apikey_domain.ErrAPIKeyNotFound)
+			},
+			expectedStatus: nethttp.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockAPIKeyService(ctrl)
+			tt.mockSetup(svc)
+
+			handler := apikey_http.NewHandler(svc)
+
+			req := httptest.NewRequest("DELETE", "/api-keys/"+id, nil)
+			req.SetPathValue("keyId", id)
+			req = req.WithContext(context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{Role: "moderator"}))
+			rec := httptest.NewRecorder()
+
+			handler.Revoke(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package http
+
+type CreateRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	PVZID  string   `json:"pvzId,omitempty"`
+}
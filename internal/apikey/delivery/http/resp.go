@@ -0,0 +1,21 @@
+package http
+
+import "time"
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	PVZID      string     `json:"pvzId,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreateResponse contains plain key, it is returned only once.
+type CreateResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopePVZRead         Scope = "pvz:read"
+	ScopeReceptionCreate Scope = "reception:create"
+	ScopeProductAdd      Scope = "product:add"
+)
+
+func (s Scope) String() string {
+	return string(s)
+}
+
+func (s Scope) Validate() error {
+	switch s {
+	case ScopePVZRead, ScopeReceptionCreate, ScopeProductAdd:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidScope, s)
+}
+
+// APIKey is machine credential for integrations.
+// Only sha256 hash of the key is stored, key itself is shown once on create.
+type APIKey struct {
+	ID   string
+	Name string
+	// Prefix is public part of key, used for lookup.
+	Prefix string
+	Hash   string
+	Scopes []Scope
+	// PVZID restricts key to one pvz, empty means any pvz.
+	PVZID      string
+	CreatedBy  string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
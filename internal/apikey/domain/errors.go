@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInternalDatabase = errors.New("apikey: internal database error")
+	ErrAPIKeyNotFound   = errors.New("apikey: api key not found")
+)
+
+var (
+	ErrAccessDenied    = errors.New("apikey: only moderators can manage api keys")
+	ErrInvalidScope    = errors.New("apikey: invalid scope")
+	ErrInvalidName     = errors.New("apikey: invalid name")
+	ErrInvalidIDFormat = errors.New("apikey: invalid id format")
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("apikey: invalid api key")
+	ErrAPIKeyRevoked = errors.New("apikey: api key revoked")
+)
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) (*APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	// Revoke sets revoked_at, revoking already revoked key keeps first revoked_at.
+	Revoke(ctx context.Context, id string, at time.Time) (*APIKey, error)
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+)
+
+// APIKeyMemoryRepository keeps api keys in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type APIKeyMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewAPIKeyMemoryRepository(db *memdb.DB) *APIKeyMemoryRepository {
+	return &APIKeyMemoryRepository{db: db}
+}
+
+func (r *APIKeyMemoryRepository) Create(_ context.Context, key *apikey_domain.APIKey) (*apikey_domain.APIKey, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	for _, row := range r.db.APIKeys {
+		if row.ID == key.ID || row.Prefix == key.Prefix {
+			return nil, fmt.Errorf("%w: duplicate api key %s", apikey_domain.ErrInternalDatabase, key.Prefix)
+		}
+	}
+
+	row := cloneAPIKey(key)
+	row.LastUsedAt = nil
+	row.RevokedAt = nil
+	r.db.APIKeys = append(r.db.APIKeys, row)
+
+	return cloneAPIKey(row), nil
+}
+
+func (r *APIKeyMemoryRepository) FindByPrefix(_ context.Context, prefix string) (*apikey_domain.APIKey, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	for _, row := range r.db.APIKeys {
+		if row.Prefix == prefix {
+			return cloneAPIKey(row), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: prefix %s", apikey_domain.ErrAPIKeyNotFound, prefix)
+}
+
+func (r *APIKeyMemoryRepository) List(_ context.Context) ([]*apikey_domain.APIKey, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	keys := make([]*apikey_domain.APIKey, 0, len(r.db.APIKeys))
+	for _, row := range r.db.APIKeys {
+		keys = append(keys, cloneAPIKey(row))
+	}
+
+	memdb.SortNewestFirst(keys, func(k *apikey_domain.APIKey) time.Time { return k.CreatedAt })
+
+	return keys, nil
+}
+
+func (r *APIKeyMemoryRepository) Revoke(_ context.Context, id string, at time.Time) (*apikey_domain.APIKey, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := r.find(id)
+	if row == nil {
+		return nil, fmt.Errorf("%w: id %s", apikey_domain.ErrAPIKeyNotFound, id)
+	}
+
+	if row.RevokedAt == nil {
+		row.RevokedAt = &at
+	}
+
+	return cloneAPIKey(row), nil
+}
+
+func (r *APIKeyMemoryRepository) TouchLastUsed(_ context.Context, id string, at time.Time) error {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	if row := r.find(id); row != nil {
+		row.LastUsedAt = &at
+	}
+
+	return nil
+}
+
+func (r *APIKeyMemoryRepository) find(id string) *apikey_domain.APIKey {
+	for _, row := range r.db.APIKeys {
+		if row.ID == id {
+			return row
+		}
+	}
+
+	return nil
+}
+
+func cloneAPIKey(k *apikey_domain.APIKey) *apikey_domain.APIKey {
+	c := *k
+	c.Scopes = slices.Clone(k.Scopes)
+	if k.LastUsedAt != nil {
+		at := *k.LastUsedAt
+		c.LastUsedAt = &at
+	}
+	if k.RevokedAt != nil {
+		at := *k.RevokedAt
+		c.RevokedAt = &at
+	}
+
+	return &c
+}
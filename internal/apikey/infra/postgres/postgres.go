@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyPostgresRepository(pgx *pgxpool.Pool) *APIKeyPostgresRepository {
+	return &APIKeyPostgresRepository{pool: pgx}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, COALESCE(pvz_id::text, ''), created_by, created_at, last_used_at, revoked_at`
+
+func (r *APIKeyPostgresRepository) Create(ctx context.Context, key *apikey_domain.APIKey) (*apikey_domain.APIKey, error) {
+	query := `
+		INSERT INTO avito.api_keys (id, name, prefix, key_hash, scopes, pvz_id, created_by, created_at)
+		VALUES (@id, @name, @prefix, @key_hash, @scopes, NULLIF(@pvz_id, '')::uuid, @created_by, @created_at)
+		RETURNING ` + apiKeyColumns
+
+	args := pgx.NamedArgs{
+		"id":         key.ID,
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+		"key_hash":   key.Hash,
+		"scopes":     scopesToStrings(key.Scopes),
+		"pvz_id":     key.PVZID,
+		"created_by": key.CreatedBy,
+		"created_at": key.CreatedAt,
+	}
+
+	created, err := scanAPIKey(r.pool.QueryRow(ctx, query, args))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return created, nil
+}
+
+func (r *APIKeyPostgresRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey_domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM avito.api_keys WHERE prefix = @prefix`
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, pgx.NamedArgs{"prefix": prefix}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrAPIKeyNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyPostgresRepository) List(ctx context.Context) ([]*apikey_domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM avito.api_keys ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	keys := make([]*apikey_domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyPostgresRepository) Revoke(ctx context.Context, id string, at time.Time) (*apikey_domain.APIKey, error) {
+	query := `
+		UPDATE avito.api_keys
+		SET revoked_at = COALESCE(revoked_at, @revoked_at)
+		WHERE id = @id
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id, "revoked_at": at}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrAPIKeyNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyPostgresRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE avito.api_keys SET last_used_at = @at WHERE id = @id`
+
+	if _, err := r.pool.Exec(ctx, query, pgx.NamedArgs{"id": id, "at": at}); err != nil {
+		return fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*apikey_domain.APIKey, error) {
+	var (
+		key    apikey_domain.APIKey
+		scopes []string
+	)
+
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.PVZID,
+		&key.CreatedBy, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]apikey_domain.Scope, 0, len(scopes))
+	for _, s := range scopes {
+		key.Scopes = append(key.Scopes, apikey_domain.Scope(s))
+	}
+
+	return &key, nil
+}
+
+func scopesToStrings(scopes []apikey_domain.Scope) []string {
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		out = append(out, s.String())
+	}
+
+	return out
+}
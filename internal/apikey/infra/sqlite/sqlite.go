@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+)
+
+type APIKeySQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeySQLiteRepository(db *sql.DB) *APIKeySQLiteRepository {
+	return &APIKeySQLiteRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, COALESCE(pvz_id, ''), created_by, created_at, last_used_at, revoked_at`
+
+func (r *APIKeySQLiteRepository) Create(ctx context.Context, key *apikey_domain.APIKey) (*apikey_domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, pvz_id, created_by, created_at)
+		VALUES (@id, @name, @prefix, @key_hash, @scopes, NULLIF(@pvz_id, ''), @created_by, @created_at)
+		RETURNING ` + apiKeyColumns
+
+	// scopes are stored as json array, there are no arrays in sqlite
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	created, err := scanAPIKey(r.db.QueryRowContext(ctx, query,
+		sql.Named("id", key.ID),
+		sql.Named("name", key.Name),
+		sql.Named("prefix", key.Prefix),
+		sql.Named("key_hash", key.Hash),
+		sql.Named("scopes", string(scopes)),
+		sql.Named("pvz_id", key.PVZID),
+		sql.Named("created_by", key.CreatedBy),
+		sql.Named("created_at", database.SQLiteTime(key.CreatedAt)),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return created, nil
+}
+
+func (r *APIKeySQLiteRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey_domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = @prefix`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, sql.Named("prefix", prefix)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrAPIKeyNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeySQLiteRepository) List(ctx context.Context) ([]*apikey_domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	keys := make([]*apikey_domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeySQLiteRepository) Revoke(ctx context.Context, id string, at time.Time) (*apikey_domain.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, @revoked_at)
+		WHERE id = @id
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query,
+		sql.Named("id", id),
+		sql.Named("revoked_at", database.SQLiteTime(at)),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", apikey_domain.ErrAPIKeyNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeySQLiteRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = @at WHERE id = @id`
+
+	if _, err := r.db.ExecContext(ctx, query, sql.Named("id", id), sql.Named("at", database.SQLiteTime(at))); err != nil {
+		return fmt.Errorf("%w: %w", apikey_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row scanner) (*apikey_domain.APIKey, error) {
+	var (
+		key    apikey_domain.APIKey
+		scopes string
+	)
+
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.PVZID,
+		&key.CreatedBy, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]apikey_domain.Scope, 0)
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
@@ -4,30 +4,74 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/health"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"google.golang.org/grpc"
 )
 
+// Worker is a background job that lives as long as the app.
+// Run must return when ctx is done.
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+type Option func(a *App)
+
+// WithWorker adds background worker started with the servers.
+func WithWorker(name string, w Worker) Option {
+	return func(a *App) {
+		a.workers = append(a.workers, namedWorker{name: name, worker: w})
+	}
+}
+
+// WithHealth makes app not ready at the start of Shutdown and waits drainDelay
+// before stopping servers, so load balancer stops sending new requests
+// while in-flight ones drain.
+func WithHealth(h *health.Health, drainDelay time.Duration) Option {
+	return func(a *App) {
+		a.health = h
+		a.drainDelay = drainDelay
+	}
+}
+
+type namedWorker struct {
+	name   string
+	worker Worker
+}
+
 type App struct {
 	server        *http.Server
 	metricsServer *http.Server
 	grpcServer    *grpc.Server
 
+	workers   []namedWorker
+	workersWg sync.WaitGroup
+
+	health     *health.Health
+	drainDelay time.Duration
+
 	log    *logger.ZerologLogger
 	config *config.AppConfig
 }
 
-func New(srv *http.Server, merticsSrv *http.Server, grpcSrv *grpc.Server, l *logger.ZerologLogger, cfg *config.AppConfig) *App {
-	return &App{
+func New(srv *http.Server, merticsSrv *http.Server, grpcSrv *grpc.Server, l *logger.ZerologLogger, cfg *config.AppConfig, opts ...Option) *App {
+	a := &App{
 		server:        srv,
 		metricsServer: merticsSrv,
 		grpcServer:    grpcSrv,
 		log:           l,
 		config:        cfg,
 	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
 }
 
 func (a *App) Start(ctx context.Context) error {
@@ -65,6 +109,19 @@ func (a *App) Start(ctx context.Context) error {
 		}()
 	}
 
+	// workers are stopped by ctx, Shutdown waits for them
+	for _, w := range a.workers {
+		a.workersWg.Add(1)
+		go func() {
+			defer a.workersWg.Done()
+
+			a.log.Info().Str("worker", w.name).Msg("Starting background worker")
+			if err := w.worker.Run(ctx); err != nil {
+				a.log.Error().Str("worker", w.name).Err(err).Msg("Background worker failed")
+			}
+		}()
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil
@@ -74,6 +131,12 @@ func (a *App) Start(ctx context.Context) error {
 }
 
 func (a *App) Shutdown() error {
+	if a.health != nil {
+		a.health.Shutdown()
+		a.log.Info().Dur("drain_delay", a.drainDelay).Msg("Readiness is failing, draining requests...")
+		time.Sleep(a.drainDelay)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -102,5 +165,21 @@ func (a *App) Shutdown() error {
 		}
 	}
 
+	if len(a.workers) > 0 {
+		done := make(chan struct{})
+		go func() {
+			a.workersWg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			a.log.Info().Msg("Background workers stopped")
+		case <-ctx.Done():
+			a.log.Error().Err(ctx.Err()).Msg("Failed to wait for background workers")
+			retErr = ctx.Err()
+		}
+	}
+
 	return retErr
 }
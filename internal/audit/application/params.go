@@ -0,0 +1,51 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+type ListParams struct {
+	ActorEmail string
+	Action     audit_domain.Action
+	EntityType audit_domain.EntityType
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+	UserRole   auth_domain.Role
+}
+
+func (p *ListParams) Validate() error {
+	if p.UserRole != auth_domain.RoleModerator {
+		return audit_domain.ErrAccessDenied
+	}
+
+	if p.Action != "" {
+		if err := p.Action.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.Page == 0 {
+		p.Page = 1
+	}
+	if p.Limit == 0 {
+		p.Limit = DefaultListLimit
+	}
+
+	if p.Page < 0 || p.Limit < 0 || p.Limit > MaxListLimit {
+		return fmt.Errorf("%w: page %d, limit %d", audit_domain.ErrInvalidPaging, p.Page, p.Limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  application.ListParams
+		wantErr error
+	}{
+		{"valid defaults", application.ListParams{UserRole: auth_domain.RoleModerator}, nil},
+		{"valid action", application.ListParams{UserRole: auth_domain.RoleModerator, Action: audit_domain.ActionProductDelete}, nil},
+		{"access denied", application.ListParams{UserRole: auth_domain.RoleEmployee}, audit_domain.ErrAccessDenied},
+		{"invalid action", application.ListParams{UserRole: auth_domain.RoleModerator, Action: "product.burn"}, audit_domain.ErrInvalidAction},
+		{"negative page", application.ListParams{UserRole: auth_domain.RoleModerator, Page: -1}, audit_domain.ErrInvalidPaging},
+		{"limit too big", application.ListParams{UserRole: auth_domain.RoleModerator, Limit: application.MaxListLimit + 1}, audit_domain.ErrInvalidPaging},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("defaults are set", func(t *testing.T) {
+		params := application.ListParams{UserRole: auth_domain.RoleModerator}
+		assert.NoError(t, params.Validate())
+		assert.Equal(t, 1, params.Page)
+		assert.Equal(t, application.DefaultListLimit, params.Limit)
+	})
+}
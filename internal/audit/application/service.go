@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+type AuditService struct {
+	repo audit_domain.AuditRepository
+
+	log *logger.ZerologLogger
+}
+
+func NewAuditService(repo audit_domain.AuditRepository, l *logger.ZerologLogger) *AuditService {
+	return &AuditService{
+		repo: repo,
+		log:  l,
+	}
+}
+
+// Record writes audit entry. Failures are logged and not returned,
+// audit must not break the operation which is already done.
+func (s *AuditService) Record(ctx context.Context, event audit_domain.Event) {
+	entry := &audit_domain.Entry{
+		ID:         uuid.NewString(),
+		CreatedAt:  time.Now(),
+		ActorEmail: event.ActorEmail,
+		ActorRole:  event.ActorRole,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		ClientIP:   requestinfo.ClientIP(ctx),
+		RequestID:  requestinfo.RequestID(ctx),
+	}
+
+	if actor, ok := requestinfo.ActorFrom(ctx); ok {
+		if entry.ActorEmail == "" {
+			entry.ActorEmail = actor.Email
+		}
+		if entry.ActorRole == "" {
+			entry.ActorRole = actor.Role
+		}
+	}
+
+	var err error
+	if entry.Before, err = marshalState(event.Before); err != nil {
+		s.log.Error().Ctx(ctx).Any("event", event).Err(err).Msg("Error marshaling audit before state")
+		return
+	}
+	if entry.After, err = marshalState(event.After); err != nil {
+		s.log.Error().Ctx(ctx).Any("event", event).Err(err).Msg("Error marshaling audit after state")
+		return
+	}
+
+	// write even if request ctx is canceled right after operation
+	if err := s.repo.Create(context.WithoutCancel(ctx), entry); err != nil {
+		s.log.Error().Ctx(ctx).Any("entry", entry).Err(err).Msg("Error creating audit entry")
+		return
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, params ListParams) (_ []*audit_domain.Entry, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.List")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("ListAudit")
+		return nil, err
+	}
+
+	filter := audit_domain.Filter{
+		ActorEmail: params.ActorEmail,
+		Action:     params.Action,
+		EntityType: params.EntityType,
+		EntityID:   params.EntityID,
+		From:       params.From,
+		To:         params.To,
+		Page:       params.Page,
+		Limit:      params.Limit,
+	}
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error listing audit entries")
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func marshalState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(state)
+}
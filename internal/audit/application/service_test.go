@@ -0,0 +1,194 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := requestinfo.WithRequestID(context.Background(), "req-1")
+	ctx = requestinfo.WithClientIP(ctx, "10.0.0.1")
+	ctx = requestinfo.WithActor(ctx, requestinfo.Actor{Email: "emp@example.com", Role: "employee"})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		event     audit_domain.Event
+		mockSetup func(*mocks.MockAuditRepository)
+	}{
+		{
+			name: "actor and request info from context",
+			ctx:  ctx,
+			event: audit_domain.Event{
+				Action:     audit_domain.ActionProductAdd,
+				EntityType: audit_domain.EntityProduct,
+				EntityID:   "product-1",
+				After:      map[string]string{"type": "обувь"},
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, e *audit_domain.Entry) error {
+						assert.NotEmpty(t, e.ID)
+						assert.Equal(t, "emp@example.com", e.ActorEmail)
+						assert.Equal(t, "employee", e.ActorRole)
+						assert.Equal(t, "10.0.0.1", e.ClientIP)
+						assert.Equal(t, "req-1", e.RequestID)
+						assert.Equal(t, audit_domain.ActionProductAdd, e.Action)
+						assert.Nil(t, e.Before)
+						assert.JSONEq(t, `{"type":"обувь"}`, string(e.After))
+						return nil
+					})
+			},
+		},
+		{
+			name: "explicit actor overrides context",
+			ctx:  ctx,
+			event: audit_domain.Event{
+				Action:     audit_domain.ActionUserLogin,
+				EntityType: audit_domain.EntityUser,
+				ActorEmail: "mod@example.com",
+				ActorRole:  "moderator",
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, e *audit_domain.Entry) error {
+						assert.Equal(t, "mod@example.com", e.ActorEmail)
+						assert.Equal(t, "moderator", e.ActorRole)
+						return nil
+					})
+			},
+		},
+		{
+			name: "anonymous request",
+			ctx:  context.Background(),
+			event: audit_domain.Event{
+				Action:     audit_domain.ActionUserLoginFailed,
+				EntityType: audit_domain.EntityUser,
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, e *audit_domain.Entry) error {
+						assert.Empty(t, e.ActorEmail)
+						assert.Empty(t, e.RequestID)
+						return nil
+					})
+			},
+		},
+		{
+			name: "repository error is not propagated",
+			ctx:  ctx,
+			event: audit_domain.Event{
+				Action:     audit_domain.ActionPVZCreate,
+				EntityType: audit_domain.EntityPVZ,
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(audit_domain.ErrInternalDatabase)
+			},
+		},
+		{
+			name: "unmarshalable state is skipped",
+			ctx:  ctx,
+			event: audit_domain.Event{
+				Action:     audit_domain.ActionPVZCreate,
+				EntityType: audit_domain.EntityPVZ,
+				After:      make(chan int),
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAuditRepository(ctrl)
+			tt.mockSetup(repo)
+
+			svc := application.NewAuditService(repo, logger.NewTestLogger())
+			svc.Record(tt.ctx, tt.event)
+		})
+	}
+}
+
+func TestAuditService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name      string
+		params    application.ListParams
+		mockSetup func(*mocks.MockAuditRepository)
+		expectErr error
+		expectLen int
+	}{
+		{
+			name: "successful list with filter",
+			params: application.ListParams{
+				ActorEmail: "emp@example.com",
+				Action:     audit_domain.ActionProductDelete,
+				UserRole:   auth_domain.RoleModerator,
+			},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					List(gomock.Any(), audit_domain.Filter{
+						ActorEmail: "emp@example.com",
+						Action:     audit_domain.ActionProductDelete,
+						Page:       1,
+						Limit:      application.DefaultListLimit,
+					}).
+					Return([]*audit_domain.Entry{{ID: "1"}, {ID: "2"}}, nil)
+			},
+			expectLen: 2,
+		},
+		{
+			name:      "access denied",
+			params:    application.ListParams{UserRole: auth_domain.RoleEmployee},
+			mockSetup: func(m *mocks.MockAuditRepository) {},
+			expectErr: audit_domain.ErrAccessDenied,
+		},
+		{
+			name:   "database error",
+			params: application.ListParams{UserRole: auth_domain.RoleModerator},
+			mockSetup: func(m *mocks.MockAuditRepository) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return(nil, audit_domain.ErrInternalDatabase)
+			},
+			expectErr: audit_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAuditRepository(ctrl)
+			tt.mockSetup(repo)
+
+			svc := application.NewAuditService(repo, logger.NewTestLogger())
+			entries, err := svc.List(context.Background(), tt.params)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, entries, tt.expectLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+)
+
+type AuditService interface {
+	List(ctx context.Context, params application.ListParams) ([]*audit_domain.Entry, error)
+}
+
+type Handler struct {
+	svc AuditService
+}
+
+func NewHandler(svc AuditService) *Handler {
+	return &Handler{
+		svc: svc,
+	}
+}
+
+func (h Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /audit", h.List)
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	query := r.URL.Query()
+
+	params := application.ListParams{
+		ActorEmail: query.Get("actor"),
+		Action:     audit_domain.Action(query.Get("action")),
+		EntityType: audit_domain.EntityType(query.Get("entityType")),
+		EntityID:   query.Get("entityId"),
+		UserRole:   auth_domain.Role(claims.Role),
+	}
+
+	var err error
+	if params.From, err = parseTime(query.Get("from")); err != nil {
+		apperr.WriteHTTP(w, r, fmt.Errorf("%w: invalid from", apperr.ErrInvalidRequest))
+		return
+	}
+	if params.To, err = parseTime(query.Get("to")); err != nil {
+		apperr.WriteHTTP(w, r, fmt.Errorf("%w: invalid to", apperr.ErrInvalidRequest))
+		return
+	}
+	if params.Page, err = parseInt(query.Get("page")); err != nil {
+		apperr.WriteHTTP(w, r, fmt.Errorf("%w: invalid page", apperr.ErrInvalidRequest))
+		return
+	}
+	if params.Limit, err = parseInt(query.Get("limit")); err != nil {
+		apperr.WriteHTTP(w, r, fmt.Errorf("%w: invalid limit", apperr.ErrInvalidRequest))
+		return
+	}
+
+	entries, err := h.svc.List(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	resp := make([]EntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, EntryResponse{
+			ID:         e.ID,
+			CreatedAt:  e.CreatedAt,
+			ActorEmail: e.ActorEmail,
+			ActorRole:  e.ActorRole,
+			Action:     e.Action.String(),
+			EntityType: e.EntityType.String(),
+			EntityID:   e.EntityID,
+			ClientIP:   e.ClientIP,
+			RequestID:  e.RequestID,
+			Before:     e.Before,
+			After:      e.After,
+		})
+	}
+
+	httpcommon.JSONResponse(w, http.StatusOK, resp)
+}
+
+// parseTime accepts RFC3339 or date only.
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 {
+		return 0, errors.New("must be positive integer")
+	}
+
+	return v, nil
+}
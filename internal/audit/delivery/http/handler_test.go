@@ -0,0 +1,140 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_http "github.com/0x0FACED/pvz-avito/internal/audit/delivery/http"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditHandler_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		userRole       string
+		mockSetup      func(*mocks.MockAuditService)
+		expectedStatus int
+		expectError    string
+		expectLen      int
+	}{
+		{
+			name:     "successful list with filters",
+			query:    "?actor=emp@example.com&action=product.delete&entityType=product&from=2025-04-01&page=2&limit=5",
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAuditService) {
+				m.EXPECT().List(gomock.Any(), application.ListParams{
+					ActorEmail: "emp@example.com",
+					Action:     audit_domain.ActionProductDelete,
+					EntityType: audit_domain.EntityProduct,
+					From:       &from,
+					Page:       2,
+					Limit:      5,
+					UserRole:   auth_domain.RoleModerator,
+				}).Return([]*audit_domain.Entry{
+					{
+						ID:         "entry-1",
+						CreatedAt:  from,
+						ActorEmail: "emp@example.com",
+						Action:     audit_domain.ActionProductDelete,
+						EntityType: audit_domain.EntityProduct,
+						Before:     json.RawMessage(`{"ID":"product-1"}`),
+					},
+				}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+			expectLen:      1,
+		},
+		{
+			name:     "access denied for employee",
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockAuditService) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, audit_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectError:    "access denied",
+		},
+		{
+			name:           "invalid from",
+			query:          "?from=yesterday",
+			userRole:       "moderator",
+			expectedStatus: nethttp.StatusBadRequest,
+			expectError:    "invalid from",
+		},
+		{
+			name:           "invalid page",
+			query:          "?page=0",
+			userRole:       "moderator",
+			expectedStatus: nethttp.StatusBadRequest,
+			expectError:    "invalid page",
+		},
+		{
+			name:     "invalid action",
+			query:    "?action=pvz.burn",
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAuditService) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, audit_domain.ErrInvalidAction)
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectError:    "invalid action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditSvcMock := mocks.NewMockAuditService(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(auditSvcMock)
+			}
+
+			handler := audit_http.NewHandler(auditSvcMock)
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/audit"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Role: tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.List(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectError != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Contains(t, errResp.Error(), tt.expectError)
+			} else {
+				var resp []audit_http.EntryResponse
+				_ = json.NewDecoder(rec.Body).Decode(&resp)
+				assert.Len(t, resp, tt.expectLen)
+			}
+		})
+	}
+
+	t.Run("missing claims in context", func(t *testing.T) {
+		handler := audit_http.NewHandler(mocks.NewMockAuditService(ctrl))
+
+		req := httptest.NewRequest(nethttp.MethodGet, "/audit", nil)
+		rec := httptest.NewRecorder()
+
+		handler.List(rec, req)
+
+		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
+	})
+}
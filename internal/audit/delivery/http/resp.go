@@ -0,0 +1,20 @@
+package http
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EntryResponse struct {
+	ID         string          `json:"id"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	ActorEmail string          `json:"actorEmail,omitempty"`
+	ActorRole  string          `json:"actorRole,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId,omitempty"`
+	ClientIP   string          `json:"clientIp,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// Event describes mutating operation to be recorded.
+// Before and After are marshaled to JSON as is, so dont put secrets there.
+type Event struct {
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	Before     any
+	After      any
+
+	// ActorEmail and ActorRole override actor from context.
+	// Used for operations without authenticated user (login, register).
+	ActorEmail string
+	ActorRole  string
+}
+
+// Auditor records mutating operations. Actor, client ip and request id
+// are taken from context.
+type Auditor interface {
+	Record(ctx context.Context, event Event)
+}
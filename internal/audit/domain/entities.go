@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionPVZCreate       Action = "pvz.create"
+	ActionReceptionOpen   Action = "reception.open"
+	ActionReceptionClose  Action = "reception.close"
+	ActionProductAdd      Action = "product.add"
+	ActionProductDelete   Action = "product.delete"
+	ActionProductRestore  Action = "product.restore"
+	ActionUserRegister    Action = "user.register"
+	ActionUserLogin       Action = "user.login"
+	ActionUserLoginFailed Action = "user.login_failed"
+	ActionUserRoleChange  Action = "user.role_change"
+	ActionUserLockout     Action = "user.lockout"
+	ActionUserUnlock      Action = "user.unlock"
+	ActionAPIKeyCreate    Action = "apikey.create"
+	ActionAPIKeyRevoke    Action = "apikey.revoke"
+)
+
+func (a Action) String() string {
+	return string(a)
+}
+
+func (a Action) Validate() error {
+	switch a {
+	case ActionPVZCreate, ActionReceptionOpen, ActionReceptionClose,
+		ActionProductAdd, ActionProductDelete, ActionProductRestore,
+		ActionUserRegister, ActionUserLogin, ActionUserLoginFailed, ActionUserRoleChange,
+		ActionUserLockout, ActionUserUnlock,
+		ActionAPIKeyCreate, ActionAPIKeyRevoke:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidAction, a)
+}
+
+type EntityType string
+
+const (
+	EntityPVZ       EntityType = "pvz"
+	EntityReception EntityType = "reception"
+	EntityProduct   EntityType = "product"
+	EntityUser      EntityType = "user"
+	EntityAPIKey    EntityType = "apikey"
+)
+
+func (e EntityType) String() string {
+	return string(e)
+}
+
+// Entry is one durable record of mutating operation.
+type Entry struct {
+	ID         string
+	CreatedAt  time.Time
+	ActorEmail string
+	ActorRole  string
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	ClientIP   string
+	RequestID  string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+// Filter for audit log listing. Empty fields are ignored.
+type Filter struct {
+	ActorEmail string
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInternalDatabase = errors.New("audit: internal database error")
+)
+
+var (
+	ErrAccessDenied  = errors.New("audit: only moderators can read audit log")
+	ErrInvalidAction = errors.New("audit: invalid action")
+	ErrInvalidPaging = errors.New("audit: invalid page or limit")
+)
@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *Entry) error
+	List(ctx context.Context, filter Filter) ([]*Entry, error)
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+)
+
+// AuditMemoryRepository keeps audit log in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type AuditMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewAuditMemoryRepository(db *memdb.DB) *AuditMemoryRepository {
+	return &AuditMemoryRepository{db: db}
+}
+
+func (r *AuditMemoryRepository) Create(_ context.Context, entry *audit_domain.Entry) error {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := *entry
+	r.db.Audit = append(r.db.Audit, &row)
+
+	return nil
+}
+
+func (r *AuditMemoryRepository) List(_ context.Context, filter audit_domain.Filter) ([]*audit_domain.Entry, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	var matched []*audit_domain.Entry
+	for _, e := range r.db.Audit {
+		if matches(e, filter) {
+			matched = append(matched, e)
+		}
+	}
+
+	// newest first, ties by id
+	slices.SortStableFunc(matched, func(a, b *audit_domain.Entry) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	entries := make([]*audit_domain.Entry, 0)
+	offset := (filter.Page - 1) * filter.Limit
+	if offset < 0 || offset >= len(matched) {
+		return entries, nil
+	}
+
+	for _, e := range matched[offset:min(offset+filter.Limit, len(matched))] {
+		entry := *e
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+func matches(e *audit_domain.Entry, f audit_domain.Filter) bool {
+	switch {
+	case f.ActorEmail != "" && e.ActorEmail != f.ActorEmail:
+		return false
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case f.EntityType != "" && e.EntityType != f.EntityType:
+		return false
+	case f.EntityID != "" && e.EntityID != f.EntityID:
+		return false
+	case f.From != nil && e.CreatedAt.Before(*f.From):
+		return false
+	case f.To != nil && e.CreatedAt.After(*f.To):
+		return false
+	}
+
+	return true
+}
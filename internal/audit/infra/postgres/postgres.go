@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditPostgresRepository(pgx *pgxpool.Pool) *AuditPostgresRepository {
+	return &AuditPostgresRepository{pool: pgx}
+}
+
+func (r *AuditPostgresRepository) Create(ctx context.Context, entry *audit_domain.Entry) error {
+	query := `
+		INSERT INTO avito.audit_log (
+			id, created_at, actor_email, actor_role, action, entity_type, entity_id, client_ip, request_id, before, after
+		)
+		VALUES (
+			@id, @created_at, NULLIF(@actor_email, ''), NULLIF(@actor_role, ''), @action, @entity_type,
+			NULLIF(@entity_id, ''), NULLIF(@client_ip, ''), NULLIF(@request_id, ''), @before, @after
+		)
+	`
+
+	args := pgx.NamedArgs{
+		"id":          entry.ID,
+		"created_at":  entry.CreatedAt,
+		"actor_email": entry.ActorEmail,
+		"actor_role":  entry.ActorRole,
+		"action":      entry.Action.String(),
+		"entity_type": entry.EntityType.String(),
+		"entity_id":   entry.EntityID,
+		"client_ip":   entry.ClientIP,
+		"request_id":  entry.RequestID,
+		"before":      nullableJSON(entry.Before),
+		"after":       nullableJSON(entry.After),
+	}
+
+	if _, err := database.Pgx(ctx, r.pool).Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+func (r *AuditPostgresRepository) List(ctx context.Context, filter audit_domain.Filter) ([]*audit_domain.Entry, error) {
+	var conditions []string
+	args := pgx.NamedArgs{
+		"limit":  filter.Limit,
+		"offset": (filter.Page - 1) * filter.Limit,
+	}
+
+	if filter.ActorEmail != "" {
+		conditions = append(conditions, "actor_email = @actor_email")
+		args["actor_email"] = filter.ActorEmail
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = @action")
+		args["action"] = filter.Action.String()
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = @entity_type")
+		args["entity_type"] = filter.EntityType.String()
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = @entity_id")
+		args["entity_id"] = filter.EntityID
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= @from")
+		args["from"] = *filter.From
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= @to")
+		args["to"] = *filter.To
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+		SELECT id, created_at, COALESCE(actor_email, ''), COALESCE(actor_role, ''), action, entity_type,
+		       COALESCE(entity_id, ''), COALESCE(client_ip, ''), COALESCE(request_id, ''), before, after
+		FROM avito.audit_log
+		` + where + `
+		ORDER BY created_at DESC, id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	entries := make([]*audit_domain.Entry, 0)
+	for rows.Next() {
+		var entry audit_domain.Entry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.CreatedAt,
+			&entry.ActorEmail,
+			&entry.ActorRole,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.ClientIP,
+			&entry.RequestID,
+			&entry.Before,
+			&entry.After,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+
+	return entries, nil
+}
+
+// nullableJSON keeps empty state as SQL NULL instead of invalid empty jsonb.
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return string(raw)
+}
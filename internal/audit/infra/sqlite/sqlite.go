@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+)
+
+type AuditSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewAuditSQLiteRepository(db *sql.DB) *AuditSQLiteRepository {
+	return &AuditSQLiteRepository{db: db}
+}
+
+func (r *AuditSQLiteRepository) Create(ctx context.Context, entry *audit_domain.Entry) error {
+	query := `
+		INSERT INTO audit_log (
+			id, created_at, actor_email, actor_role, action, entity_type, entity_id, client_ip, request_id, before, after
+		)
+		VALUES (
+			@id, @created_at, NULLIF(@actor_email, ''), NULLIF(@actor_role, ''), @action, @entity_type,
+			NULLIF(@entity_id, ''), NULLIF(@client_ip, ''), NULLIF(@request_id, ''), @before, @after
+		)
+	`
+
+	_, err := database.SQLite(ctx, r.db).ExecContext(ctx, query,
+		sql.Named("id", entry.ID),
+		sql.Named("created_at", database.SQLiteTime(entry.CreatedAt)),
+		sql.Named("actor_email", entry.ActorEmail),
+		sql.Named("actor_role", entry.ActorRole),
+		sql.Named("action", entry.Action.String()),
+		sql.Named("entity_type", entry.EntityType.String()),
+		sql.Named("entity_id", entry.EntityID),
+		sql.Named("client_ip", entry.ClientIP),
+		sql.Named("request_id", entry.RequestID),
+		sql.Named("before", nullableJSON(entry.Before)),
+		sql.Named("after", nullableJSON(entry.After)),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+func (r *AuditSQLiteRepository) List(ctx context.Context, filter audit_domain.Filter) ([]*audit_domain.Entry, error) {
+	var conditions []string
+	args := []any{
+		sql.Named("limit", filter.Limit),
+		sql.Named("offset", (filter.Page-1)*filter.Limit),
+	}
+
+	if filter.ActorEmail != "" {
+		conditions = append(conditions, "actor_email = @actor_email")
+		args = append(args, sql.Named("actor_email", filter.ActorEmail))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = @action")
+		args = append(args, sql.Named("action", filter.Action.String()))
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = @entity_type")
+		args = append(args, sql.Named("entity_type", filter.EntityType.String()))
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = @entity_id")
+		args = append(args, sql.Named("entity_id", filter.EntityID))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= @from")
+		args = append(args, sql.Named("from", database.SQLiteTime(*filter.From)))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= @to")
+		args = append(args, sql.Named("to", database.SQLiteTime(*filter.To)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+		SELECT id, created_at, COALESCE(actor_email, ''), COALESCE(actor_role, ''), action, entity_type,
+		       COALESCE(entity_id, ''), COALESCE(client_ip, ''), COALESCE(request_id, ''), before, after
+		FROM audit_log
+		` + where + `
+		ORDER BY created_at DESC, id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	entries := make([]*audit_domain.Entry, 0)
+	for rows.Next() {
+		var (
+			entry         audit_domain.Entry
+			before, after sql.NullString
+		)
+		err := rows.Scan(
+			&entry.ID,
+			&entry.CreatedAt,
+			&entry.ActorEmail,
+			&entry.ActorRole,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.ClientIP,
+			&entry.RequestID,
+			&before,
+			&after,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+		}
+
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", audit_domain.ErrInternalDatabase, err)
+	}
+
+	return entries, nil
+}
+
+// nullableJSON keeps empty state as SQL NULL instead of invalid empty json.
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return string(raw)
+}
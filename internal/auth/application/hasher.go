@@ -0,0 +1,197 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HashAlgoBcrypt   = "bcrypt"
+	HashAlgoArgon2id = "argon2id"
+)
+
+// PasswordHasher hashes passwords and checks them against stored hashes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+	// NeedsRehash reports if hash was made by other algorithm or with other params.
+	NeedsRehash(hash string) bool
+}
+
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	return HashPasswordString(password, h.cost)
+}
+
+func (h *BcryptHasher) Compare(hash, password string) error {
+	return CompareHashAndPassword(hash, password)
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
+// Argon2Params are argon2id parameters, Memory is in KiB.
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// Argon2idHasher stores hashes in PHC string format:
+// $argon2id$v=19$m=65536,t=1,p=2$<salt>$<key>
+type Argon2idHasher struct {
+	params Argon2Params
+}
+
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("%w: %w", auth_domain.ErrHashPassword, err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, h.params.KeyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Time, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Compare(hash, password string) error {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInvalidPassword, err)
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return fmt.Errorf("%w: hash mismatch", auth_domain.ErrInvalidPassword)
+	}
+
+	return nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.Time != h.params.Time ||
+		params.Memory != h.params.Memory ||
+		params.Threads != h.params.Threads ||
+		uint32(len(key)) != h.params.KeyLen ||
+		uint32(len(salt)) != h.params.SaltLen
+}
+
+func decodeArgon2id(hash string) (params Argon2Params, salt, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashAlgoArgon2id {
+		return params, nil, nil, fmt.Errorf("not argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, err
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, err
+	}
+
+	params.SaltLen = uint32(len(salt))
+	params.KeyLen = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// MultiHasher hashes with preferred hasher and compares with hasher
+// detected by hash prefix, so users with old hashes can still log in.
+type MultiHasher struct {
+	preferred string
+	bcrypt    *BcryptHasher
+	argon2id  *Argon2idHasher
+}
+
+func NewMultiHasher(preferred string, bcrypt *BcryptHasher, argon2id *Argon2idHasher) (*MultiHasher, error) {
+	if preferred != HashAlgoBcrypt && preferred != HashAlgoArgon2id {
+		return nil, fmt.Errorf("unknown password hash algorithm: %s", preferred)
+	}
+
+	return &MultiHasher{
+		preferred: preferred,
+		bcrypt:    bcrypt,
+		argon2id:  argon2id,
+	}, nil
+}
+
+func (h *MultiHasher) Hash(password string) (string, error) {
+	return h.byAlgo(h.preferred).Hash(password)
+}
+
+func (h *MultiHasher) Compare(hash, password string) error {
+	return h.byAlgo(hashAlgo(hash)).Compare(hash, password)
+}
+
+func (h *MultiHasher) NeedsRehash(hash string) bool {
+	algo := hashAlgo(hash)
+	if algo != h.preferred {
+		return true
+	}
+
+	return h.byAlgo(algo).NeedsRehash(hash)
+}
+
+func (h *MultiHasher) byAlgo(algo string) PasswordHasher {
+	if algo == HashAlgoArgon2id {
+		return h.argon2id
+	}
+
+	return h.bcrypt
+}
+
+func hashAlgo(hash string) string {
+	if strings.HasPrefix(hash, "$"+HashAlgoArgon2id+"$") {
+		return HashAlgoArgon2id
+	}
+
+	return HashAlgoBcrypt
+}
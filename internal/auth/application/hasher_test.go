@@ -0,0 +1,83 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArgon2Params = application.Argon2Params{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := application.NewArgon2idHasher(testArgon2Params)
+
+	hash, err := hasher.Hash("secure123")
+	require.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[^$]+\$[^$]+$`, hash)
+
+	assert.NoError(t, hasher.Compare(hash, "secure123"))
+	assert.ErrorIs(t, hasher.Compare(hash, "wrong"), auth_domain.ErrInvalidPassword)
+	assert.ErrorIs(t, hasher.Compare("$argon2id$broken", "secure123"), auth_domain.ErrInvalidPassword)
+
+	other, err := hasher.Hash("secure123")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salt must be random")
+
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	stronger := testArgon2Params
+	stronger.Time = 2
+	assert.True(t, application.NewArgon2idHasher(stronger).NeedsRehash(hash))
+}
+
+func TestBcryptHasher_NeedsRehash(t *testing.T) {
+	hash, err := application.NewBcryptHasher(4).Hash("secure123")
+	require.NoError(t, err)
+
+	assert.False(t, application.NewBcryptHasher(4).NeedsRehash(hash))
+	assert.True(t, application.NewBcryptHasher(5).NeedsRehash(hash))
+	assert.True(t, application.NewBcryptHasher(4).NeedsRehash("not a hash"))
+}
+
+func TestMultiHasher(t *testing.T) {
+	bcryptHasher := application.NewBcryptHasher(4)
+	argonHasher := application.NewArgon2idHasher(testArgon2Params)
+
+	bcryptHash, err := bcryptHasher.Hash("secure123")
+	require.NoError(t, err)
+	argonHash, err := argonHasher.Hash("secure123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		preferred   string
+		hash        string
+		needsRehash bool
+	}{
+		{"bcrypt preferred, bcrypt hash", application.HashAlgoBcrypt, bcryptHash, false},
+		{"bcrypt preferred, argon2id hash", application.HashAlgoBcrypt, argonHash, true},
+		{"argon2id preferred, bcrypt hash", application.HashAlgoArgon2id, bcryptHash, true},
+		{"argon2id preferred, argon2id hash", application.HashAlgoArgon2id, argonHash, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := application.NewMultiHasher(tt.preferred, bcryptHasher, argonHasher)
+			require.NoError(t, err)
+
+			assert.NoError(t, hasher.Compare(tt.hash, "secure123"))
+			assert.ErrorIs(t, hasher.Compare(tt.hash, "wrong"), auth_domain.ErrInvalidPassword)
+			assert.Equal(t, tt.needsRehash, hasher.NeedsRehash(tt.hash))
+
+			hash, err := hasher.Hash("secure123")
+			require.NoError(t, err)
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+
+	_, err = application.NewMultiHasher("md5", bcryptHasher, argonHasher)
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+)
+
+// LoginPolicy configures login brute-force protection.
+type LoginPolicy struct {
+	// FreeAttempts is number of failures without any delay.
+	FreeAttempts int
+	// MaxAttempts per email and IPMaxAttempts per ip lead to lockout.
+	MaxAttempts   int
+	IPMaxAttempts int
+	// BaseDelay is doubled for every failure after FreeAttempts, up to MaxDelay.
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	// Window is period after last failure when counter is reset.
+	Window time.Duration
+}
+
+// delay returns how long to wait after last failure before next attempt.
+func (p LoginPolicy) delay(failures int) time.Duration {
+	if failures < p.FreeAttempts || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+const (
+	emailKeyPrefix = "email:"
+	ipKeyPrefix    = "ip:"
+)
+
+// LoginGuard tracks failed logins per email and per client ip,
+// delays next attempts progressively and locks keys out.
+type LoginGuard struct {
+	repo    auth_domain.LoginAttemptRepository
+	policy  LoginPolicy
+	auditor audit_domain.Auditor
+
+	log *logger.ZerologLogger
+}
+
+func NewLoginGuard(
+	repo auth_domain.LoginAttemptRepository,
+	policy LoginPolicy,
+	auditor audit_domain.Auditor,
+	l *logger.ZerologLogger,
+) *LoginGuard {
+	return &LoginGuard{
+		repo:    repo,
+		policy:  policy,
+		auditor: auditor,
+		log:     l,
+	}
+}
+
+// Check registers login attempt for email and client ip from ctx, it must be
+// called before credentials are checked. Returns *auth_domain.AttemptsError
+// if login is delayed or locked, then attempt is not made.
+//
+// Attempt is counted and compared with limit in one atomic step of repository,
+// so parallel requests can't make more attempts than limit between lockouts.
+// Counted attempt stays a failure unless it is finished with Success or Release.
+func (g *LoginGuard) Check(ctx context.Context, email string) error {
+	// columns are timestamps without time zone, pgx drops zone of stored times,
+	// so times are kept in UTC to be compared with stored ones
+	now := time.Now().UTC()
+	keys := g.keys(ctx, email)
+
+	// delay only slows down sequential attempts, so it is checked before counting
+	for _, key := range keys {
+		attempts, err := g.repo.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if wait := g.wait(attempts, now); wait > 0 {
+			return &auth_domain.AttemptsError{RetryAfter: wait}
+		}
+	}
+
+	// storages keep microseconds, lockUntil is compared with stored value below
+	lockUntil := now.Add(g.policy.LockoutDuration).Truncate(time.Microsecond)
+
+	for i, key := range keys {
+		attempts, err := g.repo.RegisterAttempt(ctx, key, now, g.policy.Window, g.limit(key, email), lockUntil)
+		if err != nil {
+			g.release(ctx, keys[:i])
+			return err
+		}
+
+		if !attempts.IsLocked(now) {
+			continue
+		}
+
+		// rejected attempt is not counted for other keys
+		g.release(ctx, keys[:i])
+
+		if attempts.LockedUntil.Equal(lockUntil) {
+			g.lockedOut(ctx, email, attempts)
+		}
+
+		return &auth_domain.AttemptsError{RetryAfter: attempts.LockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+func (g *LoginGuard) wait(attempts *auth_domain.LoginAttempts, now time.Time) time.Duration {
+	if attempts.IsLocked(now) {
+		return attempts.LockedUntil.Sub(now)
+	}
+
+	if attempts.Failures == 0 || now.Sub(attempts.LastFailure) > g.policy.Window {
+		return 0
+	}
+
+	ready := attempts.LastFailure.Add(g.policy.delay(attempts.Failures))
+	if now.Before(ready) {
+		return ready.Sub(now)
+	}
+
+	return 0
+}
+
+// limit returns number of attempts allowed for key, 0 disables lockout.
+func (g *LoginGuard) limit(key, email string) int {
+	if key == emailKeyPrefix+email {
+		return g.policy.MaxAttempts
+	}
+
+	return g.policy.IPMaxAttempts
+}
+
+// lockedOut is called once for lockout, by attempt which exceeded limit.
+func (g *LoginGuard) lockedOut(ctx context.Context, email string, attempts *auth_domain.LoginAttempts) {
+	g.log.Warn().Ctx(ctx).Str("key", attempts.Key).Int("failures", attempts.Failures).Time("locked_until", *attempts.LockedUntil).Msg("Login locked out")
+
+	g.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserLockout,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   attempts.Key,
+		After:      attempts,
+		ActorEmail: email,
+	})
+}
+
+// Success resets failures of email and takes back attempt of ip. Ip counter
+// is not reset, otherwise own valid account could be used to reset it.
+func (g *LoginGuard) Success(ctx context.Context, email string) {
+	if err := g.repo.Reset(ctx, emailKeyPrefix+email); err != nil {
+		g.log.Error().Ctx(ctx).Str("email", email).Err(err).Msg("Error resetting login attempts")
+	}
+
+	g.release(ctx, g.keys(ctx, email)[1:])
+}
+
+// Release takes back attempt which ended before credentials were checked,
+// e.g. because of database error. Errors are only logged.
+func (g *LoginGuard) Release(ctx context.Context, email string) {
+	g.release(ctx, g.keys(ctx, email))
+}
+
+func (g *LoginGuard) release(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := g.repo.Release(ctx, key); err != nil {
+			g.log.Error().Ctx(ctx).Str("key", key).Err(err).Msg("Error releasing login attempt")
+		}
+	}
+}
+
+// Unlock resets failures and lockout of email and/or ip.
+func (g *LoginGuard) Unlock(ctx context.Context, email, ip string) error {
+	var keys []string
+	if email != "" {
+		keys = append(keys, emailKeyPrefix+email)
+	}
+	if ip != "" {
+		keys = append(keys, ipKeyPrefix+ip)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := g.repo.Reset(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *LoginGuard) keys(ctx context.Context, email string) []string {
+	keys := []string{emailKeyPrefix + email}
+	if ip := requestinfo.ClientIP(ctx); ip != "" {
+		keys = append(keys, ipKeyPrefix+ip)
+	}
+
+	return keys
+}
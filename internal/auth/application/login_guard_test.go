@@ -0,0 +1,335 @@
+package application_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/auth/infra/memory"
+	"github.com/0x0FACED/pvz-avito/internal/auth/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testLoginPolicy = application.LoginPolicy{
+	FreeAttempts:    3,
+	MaxAttempts:     5,
+	IPMaxAttempts:   20,
+	BaseDelay:       time.Second,
+	MaxDelay:        4 * time.Second,
+	LockoutDuration: 15 * time.Minute,
+	Window:          15 * time.Minute,
+}
+
+func TestLoginGuard_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name        string
+		attempts    *auth_domain.LoginAttempts
+		expectWait  bool
+		maxWait     time.Duration
+		minWait     time.Duration
+		expectOther error
+	}{
+		{
+			name:     "no failures",
+			attempts: &auth_domain.LoginAttempts{},
+		},
+		{
+			name:     "free attempts",
+			attempts: &auth_domain.LoginAttempts{Failures: 2, LastFailure: now},
+		},
+		{
+			name:       "delayed after free attempts",
+			attempts:   &auth_domain.LoginAttempts{Failures: 3, LastFailure: now},
+			expectWait: true,
+			minWait:    500 * time.Millisecond,
+			maxWait:    time.Second,
+		},
+		{
+			name:       "delay is doubled and capped",
+			attempts:   &auth_domain.LoginAttempts{Failures: 10, LastFailure: now},
+			expectWait: true,
+			minWait:    3 * time.Second,
+			maxWait:    4 * time.Second,
+		},
+		{
+			name:     "delay passed",
+			attempts: &auth_domain.LoginAttempts{Failures: 3, LastFailure: now.Add(-2 * time.Second)},
+		},
+		{
+			name:     "failures outside window",
+			attempts: &auth_domain.LoginAttempts{Failures: 4, LastFailure: now.Add(-time.Hour)},
+		},
+		{
+			name:       "locked",
+			attempts:   &auth_domain.LoginAttempts{Failures: 5, LastFailure: now, LockedUntil: &lockedUntil},
+			expectWait: true,
+			minWait:    9 * time.Minute,
+			maxWait:    10 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockLoginAttemptRepository(ctrl)
+			repo.EXPECT().Get(gomock.Any(), "email:user@example.com").Return(tt.attempts, nil)
+			if !tt.expectWait {
+				repo.EXPECT().
+					RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), testLoginPolicy.Window, testLoginPolicy.MaxAttempts, gomock.Any()).
+					Return(&auth_domain.LoginAttempts{Failures: tt.attempts.Failures + 1}, nil)
+			}
+
+			guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+			err := guard.Check(context.Background(), "user@example.com")
+
+			if !tt.expectWait {
+				assert.NoError(t, err)
+				return
+			}
+
+			var attemptsErr *auth_domain.AttemptsError
+			require.ErrorAs(t, err, &attemptsErr)
+			assert.ErrorIs(t, err, auth_domain.ErrTooManyAttempts)
+			assert.GreaterOrEqual(t, attemptsErr.RetryAfter, tt.minWait)
+			assert.LessOrEqual(t, attemptsErr.RetryAfter, tt.maxWait)
+		})
+	}
+
+	t.Run("ip is checked too", func(t *testing.T) {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Get(gomock.Any(), "email:user@example.com").Return(&auth_domain.LoginAttempts{}, nil)
+		repo.EXPECT().Get(gomock.Any(), "ip:10.0.0.1").Return(&auth_domain.LoginAttempts{
+			Failures:    20,
+			LastFailure: now,
+			LockedUntil: &lockedUntil,
+		}, nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		ctx := requestinfo.WithClientIP(context.Background(), "10.0.0.1")
+
+		assert.ErrorIs(t, guard.Check(ctx, "user@example.com"), auth_domain.ErrTooManyAttempts)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrInternalDatabase)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+
+		assert.ErrorIs(t, guard.Check(context.Background(), "user@example.com"), auth_domain.ErrInternalDatabase)
+	})
+}
+
+func TestLoginGuard_Attempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := requestinfo.WithClientIP(context.Background(), "10.0.0.1")
+
+	// newRepo returns repository without delays, attempts are checked by RegisterAttempt
+	newRepo := func() *mocks.MockLoginAttemptRepository {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&auth_domain.LoginAttempts{}, nil).AnyTimes()
+		return repo
+	}
+
+	t.Run("counted for email and ip", func(t *testing.T) {
+		repo := newRepo()
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), testLoginPolicy.Window, testLoginPolicy.MaxAttempts, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key string, at time.Time, _ time.Duration, _ int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+				assert.WithinDuration(t, at.Add(testLoginPolicy.LockoutDuration), lockUntil, time.Millisecond)
+				return &auth_domain.LoginAttempts{Key: key, Failures: 5}, nil
+			})
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "ip:10.0.0.1", gomock.Any(), testLoginPolicy.Window, testLoginPolicy.IPMaxAttempts, gomock.Any()).
+			Return(&auth_domain.LoginAttempts{Key: "ip:10.0.0.1", Failures: 5}, nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		assert.NoError(t, guard.Check(ctx, "user@example.com"))
+	})
+
+	t.Run("attempt over limit locks out", func(t *testing.T) {
+		repo := newRepo()
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key string, at time.Time, _ time.Duration, _ int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+				return &auth_domain.LoginAttempts{Key: key, Failures: 6, LastFailure: at, LockedUntil: &lockUntil}, nil
+			})
+
+		auditor := audit_mocks.NewMockAuditor(ctrl)
+		auditor.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, e audit_domain.Event) {
+				assert.Equal(t, audit_domain.ActionUserLockout, e.Action)
+				assert.Equal(t, "email:user@example.com", e.EntityID)
+			})
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, auditor, logger.NewTestLogger())
+		err := guard.Check(ctx, "user@example.com")
+
+		var attemptsErr *auth_domain.AttemptsError
+		require.ErrorAs(t, err, &attemptsErr)
+		assert.InDelta(t, testLoginPolicy.LockoutDuration, attemptsErr.RetryAfter, float64(time.Second))
+	})
+
+	t.Run("locked by parallel attempt", func(t *testing.T) {
+		lockedUntil := time.Now().Add(time.Minute)
+
+		repo := newRepo()
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&auth_domain.LoginAttempts{Failures: 7, LockedUntil: &lockedUntil}, nil)
+
+		// lockout is audited by attempt which locked key
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		assert.ErrorIs(t, guard.Check(ctx, "user@example.com"), auth_domain.ErrTooManyAttempts)
+	})
+
+	t.Run("ip locked, email attempt is released", func(t *testing.T) {
+		lockedUntil := time.Now().Add(time.Minute)
+
+		repo := newRepo()
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&auth_domain.LoginAttempts{Failures: 1}, nil)
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "ip:10.0.0.1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&auth_domain.LoginAttempts{Failures: 30, LockedUntil: &lockedUntil}, nil)
+		repo.EXPECT().Release(gomock.Any(), "email:user@example.com").Return(nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		assert.ErrorIs(t, guard.Check(ctx, "user@example.com"), auth_domain.ErrTooManyAttempts)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := newRepo()
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&auth_domain.LoginAttempts{Failures: 1}, nil)
+		repo.EXPECT().
+			RegisterAttempt(gomock.Any(), "ip:10.0.0.1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, auth_domain.ErrInternalDatabase)
+		repo.EXPECT().Release(gomock.Any(), "email:user@example.com").Return(nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		assert.ErrorIs(t, guard.Check(ctx, "user@example.com"), auth_domain.ErrInternalDatabase)
+	})
+
+	t.Run("parallel attempts do not exceed limit", func(t *testing.T) {
+		policy := testLoginPolicy
+		policy.BaseDelay = 0
+
+		auditor := audit_mocks.NewMockAuditor(ctrl)
+		auditor.EXPECT().Record(gomock.Any(), gomock.Any()).Times(1)
+
+		guard := application.NewLoginGuard(memory.NewLoginAttemptMemoryRepository(), policy, auditor, logger.NewTestLogger())
+
+		var allowed atomic.Int32
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if guard.Check(ctx, "user@example.com") == nil {
+					allowed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(policy.MaxAttempts), allowed.Load())
+	})
+}
+
+// zonelessRepository stores times as timestamp columns without time zone do:
+// zone is dropped and wall clock is read back as UTC.
+type zonelessRepository struct {
+	auth_domain.LoginAttemptRepository
+}
+
+func (r zonelessRepository) RegisterAttempt(ctx context.Context, key string, at time.Time, window time.Duration, limit int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+	return r.LoginAttemptRepository.RegisterAttempt(ctx, key, wallClock(at), window, limit, wallClock(lockUntil))
+}
+
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func TestLoginGuard_NonUTCLocalTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := time.Local
+	time.Local = time.FixedZone("UTC+3", 3*60*60)
+	defer func() { time.Local = local }()
+
+	ctx := requestinfo.WithClientIP(context.Background(), "10.0.0.1")
+	policy := testLoginPolicy
+	policy.BaseDelay = 0
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().
+		Record(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e audit_domain.Event) {
+			assert.Equal(t, audit_domain.ActionUserLockout, e.Action)
+		})
+
+	guard := application.NewLoginGuard(zonelessRepository{memory.NewLoginAttemptMemoryRepository()}, policy, auditor, logger.NewTestLogger())
+	for range policy.MaxAttempts {
+		require.NoError(t, guard.Check(ctx, "user@example.com"))
+	}
+
+	err := guard.Check(ctx, "user@example.com")
+
+	var attemptsErr *auth_domain.AttemptsError
+	require.ErrorAs(t, err, &attemptsErr)
+	assert.InDelta(t, policy.LockoutDuration, attemptsErr.RetryAfter, float64(time.Second))
+}
+
+func TestLoginGuard_SuccessAndUnlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success resets email and releases ip attempt", func(t *testing.T) {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reset(gomock.Any(), "email:user@example.com").Return(nil)
+		repo.EXPECT().Release(gomock.Any(), "ip:10.0.0.1").Return(nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		guard.Success(requestinfo.WithClientIP(context.Background(), "10.0.0.1"), "user@example.com")
+	})
+
+	t.Run("release email and ip", func(t *testing.T) {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Release(gomock.Any(), "email:user@example.com").Return(nil)
+		repo.EXPECT().Release(gomock.Any(), "ip:10.0.0.1").Return(nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		guard.Release(requestinfo.WithClientIP(context.Background(), "10.0.0.1"), "user@example.com")
+	})
+
+	t.Run("unlock email and ip", func(t *testing.T) {
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reset(gomock.Any(), "email:user@example.com").Return(nil)
+		repo.EXPECT().Reset(gomock.Any(), "ip:10.0.0.1").Return(nil)
+
+		guard := application.NewLoginGuard(repo, testLoginPolicy, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger())
+		assert.NoError(t, guard.Unlock(context.Background(), "user@example.com", "10.0.0.1"))
+	})
+}
@@ -1,7 +1,9 @@
 package application
 
 import (
+	"errors"
 	"fmt"
+	"net"
 
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 )
@@ -21,6 +23,10 @@ func (p RegisterParams) Validate() error {
 		return fmt.Errorf("%w: %w", auth_domain.ErrInvalidRole, err)
 	}
 
+	if p.Password == "" {
+		return fmt.Errorf("%w: password is required", auth_domain.ErrWeakPassword)
+	}
+
 	return nil
 }
 
@@ -36,3 +42,67 @@ func (p LoginParams) Validate() error {
 
 	return nil
 }
+
+type ChangeRoleParams struct {
+	Email    auth_domain.Email
+	Role     auth_domain.Role
+	UserRole auth_domain.Role
+}
+
+func (p ChangeRoleParams) Validate() error {
+	if err := p.Email.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInvalidEmail, err)
+	}
+
+	if err := p.Role.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInvalidRole, err)
+	}
+
+	if p.UserRole != auth_domain.RoleModerator {
+		return auth_domain.ErrAccessDenied
+	}
+
+	return nil
+}
+
+type UnlockParams struct {
+	Email    auth_domain.Email
+	IP       string
+	UserRole auth_domain.Role
+}
+
+func (p UnlockParams) Validate() error {
+	if p.Email == "" && p.IP == "" {
+		return errors.New("email or ip is required")
+	}
+
+	if p.Email != "" {
+		if err := p.Email.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", auth_domain.ErrInvalidEmail, err)
+		}
+	}
+
+	if p.IP != "" && net.ParseIP(p.IP) == nil {
+		return fmt.Errorf("invalid ip: %s", p.IP)
+	}
+
+	if p.UserRole != auth_domain.RoleModerator {
+		return auth_domain.ErrAccessDenied
+	}
+
+	return nil
+}
+
+type SSOLoginParams struct {
+	Code     string
+	Verifier string
+	Nonce    string
+}
+
+func (p SSOLoginParams) Validate() error {
+	if p.Code == "" || p.Verifier == "" || p.Nonce == "" {
+		return fmt.Errorf("%w: code, verifier and nonce are required", auth_domain.ErrSSOExchange)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +18,7 @@ func TestRegisterParams_Validate(t *testing.T) {
 		{"empty email", application.RegisterParams{"", "123", "employee"}, true},
 		{"invalid role", application.RegisterParams{"test@example.com", "123", "mod"}, true},
 		{"invalid email", application.RegisterParams{"test@", "123", "moderator"}, true},
+		{"empty password", application.RegisterParams{"test@example.com", "", "employee"}, true},
 	}
 
 	for _, tt := range tests {
@@ -53,3 +55,53 @@ func TestLoginParams_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeRoleParams_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    application.ChangeRoleParams
+		expectErr bool
+	}{
+		{"valid", application.ChangeRoleParams{Email: "test@example.com", Role: auth_domain.RoleModerator, UserRole: auth_domain.RoleModerator}, false},
+		{"invalid email", application.ChangeRoleParams{Email: "test@", Role: auth_domain.RoleModerator, UserRole: auth_domain.RoleModerator}, true},
+		{"invalid role", application.ChangeRoleParams{Email: "test@example.com", Role: "admin", UserRole: auth_domain.RoleModerator}, true},
+		{"not moderator", application.ChangeRoleParams{Email: "test@example.com", Role: auth_domain.RoleModerator, UserRole: auth_domain.RoleEmployee}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnlockParams_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    application.UnlockParams
+		expectErr bool
+	}{
+		{"valid email", application.UnlockParams{Email: "test@example.com", UserRole: auth_domain.RoleModerator}, false},
+		{"valid ip", application.UnlockParams{IP: "10.0.0.1", UserRole: auth_domain.RoleModerator}, false},
+		{"empty", application.UnlockParams{UserRole: auth_domain.RoleModerator}, true},
+		{"invalid email", application.UnlockParams{Email: "test@", UserRole: auth_domain.RoleModerator}, true},
+		{"invalid ip", application.UnlockParams{IP: "10.0.0", UserRole: auth_domain.RoleModerator}, true},
+		{"not moderator", application.UnlockParams{Email: "test@example.com", UserRole: auth_domain.RoleEmployee}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+)
+
+// PasswordPolicy is checked on register. Zero policy allows any non empty password.
+type PasswordPolicy struct {
+	MinLength int
+	// MaxLength is in bytes, bcrypt ignores everything after 72 bytes.
+	MaxLength int
+
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+
+	// Denylist contains lowercased breached passwords.
+	Denylist map[string]struct{}
+}
+
+func (p PasswordPolicy) Validate(password string) error {
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters", auth_domain.ErrWeakPassword, p.MinLength)
+	}
+
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return fmt.Errorf("%w: must be at most %d bytes", auth_domain.ErrWeakPassword, p.MaxLength)
+	}
+
+	var upper, lower, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			special = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return fmt.Errorf("%w: must contain uppercase letter", auth_domain.ErrWeakPassword)
+	case p.RequireLower && !lower:
+		return fmt.Errorf("%w: must contain lowercase letter", auth_domain.ErrWeakPassword)
+	case p.RequireDigit && !digit:
+		return fmt.Errorf("%w: must contain digit", auth_domain.ErrWeakPassword)
+	case p.RequireSpecial && !special:
+		return fmt.Errorf("%w: must contain special character", auth_domain.ErrWeakPassword)
+	}
+
+	if _, ok := p.Denylist[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: password is too common", auth_domain.ErrWeakPassword)
+	}
+
+	return nil
+}
+
+// LoadDenylist reads breached passwords file, one password per line.
+// Empty lines and lines starting with # are skipped.
+func LoadDenylist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	denylist := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return denylist, nil
+}
@@ -0,0 +1,65 @@
+package application_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	strict := application.PasswordPolicy{
+		MinLength:      8,
+		MaxLength:      72,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+		Denylist:       map[string]struct{}{"p@ssw0rd!": {}},
+	}
+
+	tests := []struct {
+		name      string
+		policy    application.PasswordPolicy
+		password  string
+		expectErr bool
+	}{
+		{"zero policy", application.PasswordPolicy{}, "1", false},
+		{"valid", strict, "Secure#123", false},
+		{"too short", strict, "Se#1", true},
+		{"too long", strict, "Secure#1" + string(make([]byte, 70)), true},
+		{"no upper", strict, "secure#123", true},
+		{"no lower", strict, "SECURE#123", true},
+		{"no digit", strict, "Secure#abc", true},
+		{"no special", strict, "Secure1234", true},
+		{"denylisted, case insensitive", strict, "P@ssw0rd!", true},
+		{"unicode length in runes", application.PasswordPolicy{MinLength: 4}, "пароль", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, auth_domain.ErrWeakPassword)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadDenylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# top passwords\nPassword\n\n  qwerty  \n"), 0o600))
+
+	denylist, err := application.LoadDenylist(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"password": {}, "qwerty": {}}, denylist)
+
+	_, err = application.LoadDenylist(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
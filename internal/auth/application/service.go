@@ -2,36 +2,71 @@ package application
 
 import (
 	"context"
+	"errors"
 
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	"github.com/google/uuid"
 )
 
 type AuthService struct {
-	repo auth_domain.UserRepository
+	repo    auth_domain.UserRepository
+	guard   *LoginGuard
+	hasher  PasswordHasher
+	policy  PasswordPolicy
+	auditor audit_domain.Auditor
 
 	log *logger.ZerologLogger
 }
 
-func NewAuthService(repo auth_domain.UserRepository, l *logger.ZerologLogger) *AuthService {
+func NewAuthService(
+	repo auth_domain.UserRepository,
+	guard *LoginGuard,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	auditor audit_domain.Auditor,
+	l *logger.ZerologLogger,
+) *AuthService {
 	return &AuthService{
-		repo: repo,
-		log:  l,
+		repo:    repo,
+		guard:   guard,
+		hasher:  hasher,
+		policy:  policy,
+		auditor: auditor,
+		log:     l,
 	}
 }
 
-func (s *AuthService) Register(ctx context.Context, params RegisterParams) (*auth_domain.User, error) {
+// userState is user representation for audit log, without password hash.
+type userState struct {
+	ID    string            `json:"id"`
+	Email auth_domain.Email `json:"email"`
+	Role  auth_domain.Role  `json:"role"`
+}
+
+func newUserState(u *auth_domain.User) *userState {
+	return &userState{ID: u.ID, Email: u.Email, Role: u.Role}
+}
+
+func (s *AuthService) Register(ctx context.Context, params RegisterParams) (_ *auth_domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("Register")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Register")
+		return nil, err
+	}
+
+	if err := s.policy.Validate(params.Password); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Register")
 		return nil, err
 	}
 
-	// using min cost, dont use in prod
-	// separate cost to .env file
-	hash, err := HashPasswordString(params.Password, 4)
+	hash, err := s.hasher.Hash(params.Password)
 	if err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("Error hash password")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error hash password")
 		return nil, err
 	}
 
@@ -44,33 +79,158 @@ func (s *AuthService) Register(ctx context.Context, params RegisterParams) (*aut
 
 	created, err := s.repo.Create(ctx, user)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("user", user).Err(err).Msg("Error creating user")
+		s.log.Error().Ctx(ctx).Any("params", params).Any("user", user).Err(err).Msg("Error creating user")
 		return nil, err
 	}
 
-	s.log.Info().Any("params", params).Any("user", user).Msg("Register successful")
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserRegister,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   created.ID,
+		After:      newUserState(created),
+		ActorEmail: created.Email.String(),
+		ActorRole:  created.Role.String(),
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("user", user).Msg("Register successful")
 
 	return created, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, params LoginParams) (*auth_domain.User, error) {
+func (s *AuthService) Login(ctx context.Context, params LoginParams) (_ *auth_domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("Login")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Login")
 		return nil, err
 	}
 
+	if err := s.guard.Check(ctx, params.Email.String()); err != nil {
+		s.log.Warn().Ctx(ctx).Any("params", params).Err(err).Msg("Login throttled")
+		return nil, err
+	}
+
+	// attempt is counted by Check, unknown email and wrong password stay failures
 	user, err := s.repo.FindByEmail(ctx, params.Email.String())
 	if err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("Error finding user by email")
+		if !errors.Is(err, auth_domain.ErrUserNotFound) {
+			s.guard.Release(ctx, params.Email.String())
+		}
+		s.recordLoginFailed(ctx, params.Email.String(), "")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error finding user by email")
 		return nil, err
 	}
 
-	err = CompareHashAndPassword(user.Password, params.Password)
+	err = s.hasher.Compare(user.Password, params.Password)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("user", user).Err(err).Msg("Password mismatch")
+		s.recordLoginFailed(ctx, user.Email.String(), user.ID)
+		s.log.Error().Ctx(ctx).Any("params", params).Any("user", user).Err(err).Msg("Password mismatch")
 		return nil, err
 	}
 
-	s.log.Info().Any("params", params).Any("user", user).Msg("Login successful")
+	s.guard.Success(ctx, params.Email.String())
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehash(ctx, user, params.Password)
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserLogin,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   user.ID,
+		ActorEmail: user.Email.String(),
+		ActorRole:  user.Role.String(),
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("user", user).Msg("Login successful")
 	return user, nil
 }
+
+// rehash updates user password hash with current algo and params.
+// Login must not fail because of it, so errors are only logged.
+func (s *AuthService) rehash(ctx context.Context, user *auth_domain.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Str("user_id", user.ID).Err(err).Msg("Error rehash password")
+		return
+	}
+
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		s.log.Error().Ctx(ctx).Str("user_id", user.ID).Err(err).Msg("Error updating password hash")
+		return
+	}
+
+	user.Password = hash
+
+	s.log.Info().Ctx(ctx).Str("user_id", user.ID).Msg("Password rehashed")
+}
+
+func (s *AuthService) recordLoginFailed(ctx context.Context, email, userID string) {
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserLoginFailed,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   userID,
+		ActorEmail: email,
+	})
+}
+
+func (s *AuthService) ChangeRole(ctx context.Context, params ChangeRoleParams) (_ *auth_domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ChangeRole")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("ChangeRole")
+		return nil, err
+	}
+
+	before, err := s.repo.FindByEmail(ctx, params.Email.String())
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error finding user by email")
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateRole(ctx, params.Email.String(), params.Role)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error updating user role")
+		return nil, err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserRoleChange,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   updated.ID,
+		Before:     newUserState(before),
+		After:      newUserState(updated),
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Msg("ChangeRole successful")
+
+	return updated, nil
+}
+
+func (s *AuthService) Unlock(ctx context.Context, params UnlockParams) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Unlock")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Unlock")
+		return err
+	}
+
+	if err := s.guard.Unlock(ctx, params.Email.String(), params.IP); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error unlocking login")
+		return err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserUnlock,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   params.Email.String(),
+		After:      map[string]string{"email": params.Email.String(), "ip": params.IP},
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Msg("Unlock successful")
+
+	return nil
+}
@@ -2,17 +2,33 @@ package application_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
 	"github.com/0x0FACED/pvz-avito/internal/auth/application"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/auth/mocks"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// newLoginGuard returns guard which never throttles logins.
+func newLoginGuard(ctrl *gomock.Controller, auditor *audit_mocks.MockAuditor) *application.LoginGuard {
+	repo := mocks.NewMockLoginAttemptRepository(ctrl)
+	repo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&auth_domain.LoginAttempts{}, nil).AnyTimes()
+	repo.EXPECT().RegisterAttempt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&auth_domain.LoginAttempts{Failures: 1}, nil).AnyTimes()
+	repo.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().Reset(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return application.NewLoginGuard(repo, application.LoginPolicy{}, auditor, logger.NewTestLogger())
+}
+
 func TestRegisterUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -73,6 +89,16 @@ func TestRegisterUser(t *testing.T) {
 			},
 			expectErr: auth_domain.ErrInternalDatabase,
 		},
+		{
+			name: "weak password",
+			params: application.RegisterParams{
+				Email:    "test@example.com",
+				Password: "short",
+				Role:     auth_domain.RoleEmployee,
+			},
+			mockSetup: func(m *mocks.MockUserRepository) {},
+			expectErr: auth_domain.ErrWeakPassword,
+		},
 	}
 
 	for _, tt := range tests {
@@ -82,7 +108,12 @@ func TestRegisterUser(t *testing.T) {
 
 			logger := logger.NewTestLogger()
 
-			svc := application.NewAuthService(mockRepo, logger)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			policy := application.PasswordPolicy{MinLength: 8}
+
+			svc := application.NewAuthService(mockRepo, newLoginGuard(ctrl, auditor), application.NewBcryptHasher(4), policy, auditor, logger)
 			_, err := svc.Register(context.Background(), tt.params)
 
 			if tt.expectErr != nil {
@@ -184,7 +215,10 @@ func TestLogin(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			log := logger.NewTestLogger()
-			service := application.NewAuthService(mockRepo, log)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewAuthService(mockRepo, newLoginGuard(ctrl, auditor), application.NewBcryptHasher(4), application.PasswordPolicy{}, auditor, log)
 
 			_, err := service.Login(context.Background(), tt.params)
 
@@ -197,3 +231,266 @@ func TestLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &auth_domain.User{
+		ID:       uuid.NewString(),
+		Email:    "user@example.com",
+		Password: "hash",
+		Role:     auth_domain.RoleEmployee,
+	}
+
+	validParams := application.ChangeRoleParams{
+		Email:    "user@example.com",
+		Role:     auth_domain.RoleModerator,
+		UserRole: auth_domain.RoleModerator,
+	}
+
+	tests := []struct {
+		name      string
+		params    application.ChangeRoleParams
+		mockSetup func(*mocks.MockUserRepository, *audit_mocks.MockAuditor)
+		expectErr error
+	}{
+		{
+			name:   "successful role change",
+			params: validParams,
+			mockSetup: func(m *mocks.MockUserRepository, a *audit_mocks.MockAuditor) {
+				m.EXPECT().
+					FindByEmail(gomock.Any(), "user@example.com").
+					Return(user, nil)
+
+				m.EXPECT().
+					UpdateRole(gomock.Any(), "user@example.com", auth_domain.RoleModerator).
+					Return(&auth_domain.User{ID: user.ID, Email: user.Email, Role: auth_domain.RoleModerator}, nil)
+
+				a.EXPECT().
+					Record(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, e audit_domain.Event) {
+						assert.Equal(t, audit_domain.ActionUserRoleChange, e.Action)
+						assert.Equal(t, user.ID, e.EntityID)
+
+						// password hash must not get into audit log
+						before, err := json.Marshal(e.Before)
+						require.NoError(t, err)
+						assert.NotContains(t, string(before), "hash")
+					})
+			},
+		},
+		{
+			name:      "access denied",
+			params:    application.ChangeRoleParams{Email: "user@example.com", Role: auth_domain.RoleModerator, UserRole: auth_domain.RoleEmployee},
+			mockSetup: func(m *mocks.MockUserRepository, a *audit_mocks.MockAuditor) {},
+			expectErr: auth_domain.ErrAccessDenied,
+		},
+		{
+			name:   "user not found",
+			params: validParams,
+			mockSetup: func(m *mocks.MockUserRepository, a *audit_mocks.MockAuditor) {
+				m.EXPECT().
+					FindByEmail(gomock.Any(), "user@example.com").
+					Return(nil, auth_domain.ErrUserNotFound)
+			},
+			expectErr: auth_domain.ErrUserNotFound,
+		},
+		{
+			name:   "update error",
+			params: validParams,
+			mockSetup: func(m *mocks.MockUserRepository, a *audit_mocks.MockAuditor) {
+				m.EXPECT().
+					FindByEmail(gomock.Any(), "user@example.com").
+					Return(user, nil)
+
+				m.EXPECT().
+					UpdateRole(gomock.Any(), "user@example.com", auth_domain.RoleModerator).
+					Return(nil, auth_domain.ErrInternalDatabase)
+			},
+			expectErr: auth_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepository(ctrl)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			tt.mockSetup(mockRepo, auditor)
+
+			svc := application.NewAuthService(mockRepo, newLoginGuard(ctrl, auditor), application.NewBcryptHasher(4), application.PasswordPolicy{}, auditor, logger.NewTestLogger())
+			_, err := svc.ChangeRole(context.Background(), tt.params)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogin_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lockedUntil := time.Now().Add(time.Minute)
+
+	attemptsRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	attemptsRepo.EXPECT().
+		Get(gomock.Any(), "email:user@example.com").
+		Return(&auth_domain.LoginAttempts{Failures: 10, LastFailure: time.Now(), LockedUntil: &lockedUntil}, nil)
+
+	// user repo must not be touched while locked
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+
+	guard := application.NewLoginGuard(attemptsRepo, application.LoginPolicy{MaxAttempts: 10}, auditor, logger.NewTestLogger())
+	svc := application.NewAuthService(mockRepo, guard, application.NewBcryptHasher(4), application.PasswordPolicy{}, auditor, logger.NewTestLogger())
+
+	_, err := svc.Login(context.Background(), application.LoginParams{Email: "user@example.com", Password: "any"})
+
+	var attemptsErr *auth_domain.AttemptsError
+	require.ErrorAs(t, err, &attemptsErr)
+	assert.Positive(t, attemptsErr.RetryAfter)
+}
+
+func TestLogin_DatabaseErrorReleasesAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	attemptsRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	attemptsRepo.EXPECT().Get(gomock.Any(), "email:user@example.com").Return(&auth_domain.LoginAttempts{}, nil)
+	attemptsRepo.EXPECT().
+		RegisterAttempt(gomock.Any(), "email:user@example.com", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&auth_domain.LoginAttempts{Failures: 1}, nil)
+	// credentials were not checked, so attempt is not a failure
+	attemptsRepo.EXPECT().Release(gomock.Any(), "email:user@example.com").Return(nil)
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), "user@example.com").Return(nil, auth_domain.ErrInternalDatabase)
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+	guard := application.NewLoginGuard(attemptsRepo, application.LoginPolicy{MaxAttempts: 10}, auditor, logger.NewTestLogger())
+	svc := application.NewAuthService(mockRepo, guard, application.NewBcryptHasher(4), application.PasswordPolicy{}, auditor, logger.NewTestLogger())
+
+	_, err := svc.Login(context.Background(), application.LoginParams{Email: "user@example.com", Password: "any"})
+	assert.ErrorIs(t, err, auth_domain.ErrInternalDatabase)
+}
+
+func TestUnlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name      string
+		params    application.UnlockParams
+		mockSetup func(*mocks.MockLoginAttemptRepository)
+		expectErr error
+	}{
+		{
+			name:   "successful unlock",
+			params: application.UnlockParams{Email: "user@example.com", UserRole: auth_domain.RoleModerator},
+			mockSetup: func(m *mocks.MockLoginAttemptRepository) {
+				m.EXPECT().Reset(gomock.Any(), "email:user@example.com").Return(nil)
+			},
+		},
+		{
+			name:      "access denied",
+			params:    application.UnlockParams{Email: "user@example.com", UserRole: auth_domain.RoleEmployee},
+			mockSetup: func(m *mocks.MockLoginAttemptRepository) {},
+			expectErr: auth_domain.ErrAccessDenied,
+		},
+		{
+			name:   "database error",
+			params: application.UnlockParams{IP: "10.0.0.1", UserRole: auth_domain.RoleModerator},
+			mockSetup: func(m *mocks.MockLoginAttemptRepository) {
+				m.EXPECT().Reset(gomock.Any(), "ip:10.0.0.1").Return(auth_domain.ErrInternalDatabase)
+			},
+			expectErr: auth_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attemptsRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+			tt.mockSetup(attemptsRepo)
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			guard := application.NewLoginGuard(attemptsRepo, application.LoginPolicy{}, auditor, logger.NewTestLogger())
+			svc := application.NewAuthService(mocks.NewMockUserRepository(ctrl), guard, application.NewBcryptHasher(4), application.PasswordPolicy{}, auditor, logger.NewTestLogger())
+
+			err := svc.Unlock(context.Background(), tt.params)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogin_Rehash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldHash, err := application.HashPasswordString("validPass123", 4)
+	require.NoError(t, err)
+
+	hasher, err := application.NewMultiHasher(
+		application.HashAlgoArgon2id,
+		application.NewBcryptHasher(4),
+		application.NewArgon2idHasher(application.Argon2Params{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		updateErr error
+	}{
+		{
+			name: "rehashed",
+		},
+		{
+			name:      "update failed, login still succeeds",
+			updateErr: auth_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &auth_domain.User{
+				ID:       uuid.NewString(),
+				Email:    "test@example.com",
+				Password: oldHash,
+				Role:     auth_domain.RoleEmployee,
+			}
+
+			mockRepo := mocks.NewMockUserRepository(ctrl)
+			mockRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(user, nil)
+			mockRepo.EXPECT().
+				UpdatePassword(gomock.Any(), user.ID, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, hash string) error {
+					assert.NoError(t, hasher.Compare(hash, "validPass123"))
+					assert.False(t, hasher.NeedsRehash(hash))
+					return tt.updateErr
+				})
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			svc := application.NewAuthService(mockRepo, newLoginGuard(ctrl, auditor), hasher, application.PasswordPolicy{}, auditor, logger.NewTestLogger())
+
+			got, err := svc.Login(context.Background(), application.LoginParams{Email: "test@example.com", Password: "validPass123"})
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, got.ID)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+// ssoPasswordHash is stored for users provisioned by sso.
+// It is not valid hash of any algo, so password login is impossible for them.
+const ssoPasswordHash = "!sso"
+
+// RoleMapping maps identity provider groups to roles.
+// Moderator wins if user is in several mapped groups.
+type RoleMapping struct {
+	Groups map[string]auth_domain.Role
+	// Default is role for users without mapped groups, empty means deny.
+	Default auth_domain.Role
+}
+
+// ParseRoleMapping parses "group:role,group:role".
+func ParseRoleMapping(s string) (map[string]auth_domain.Role, error) {
+	mapping := make(map[string]auth_domain.Role)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		// group names may contain ':' (e.g. urn), role is after last one
+		i := strings.LastIndex(pair, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid role mapping %q, want group:role", pair)
+		}
+
+		role := auth_domain.Role(pair[i+1:])
+		if err := role.Validate(); err != nil {
+			return nil, err
+		}
+
+		mapping[pair[:i]] = role
+	}
+
+	return mapping, nil
+}
+
+func (m RoleMapping) Resolve(groups []string) (auth_domain.Role, bool) {
+	var role auth_domain.Role
+	for _, g := range groups {
+		switch m.Groups[g] {
+		case auth_domain.RoleModerator:
+			return auth_domain.RoleModerator, true
+		case auth_domain.RoleEmployee:
+			role = auth_domain.RoleEmployee
+		}
+	}
+
+	if role != "" {
+		return role, true
+	}
+
+	return m.Default, m.Default != ""
+}
+
+type SSOService struct {
+	repo     auth_domain.UserRepository
+	provider auth_domain.IdentityProvider
+	mapping  RoleMapping
+	auditor  audit_domain.Auditor
+
+	log *logger.ZerologLogger
+}
+
+func NewSSOService(
+	repo auth_domain.UserRepository,
+	provider auth_domain.IdentityProvider,
+	mapping RoleMapping,
+	auditor audit_domain.Auditor,
+	l *logger.ZerologLogger,
+) *SSOService {
+	return &SSOService{
+		repo:     repo,
+		provider: provider,
+		mapping:  mapping,
+		auditor:  auditor,
+		log:      l,
+	}
+}
+
+func (s *SSOService) AuthCodeURL(state, nonce, verifier string) string {
+	return s.provider.AuthCodeURL(state, nonce, verifier)
+}
+
+// Login exchanges code for identity, provisions user on first login
+// and syncs role with groups on every login.
+func (s *SSOService) Login(ctx context.Context, params SSOLoginParams) (_ *auth_domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "SSOService.Login")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Err(err).Msg("SSO Login")
+		return nil, err
+	}
+
+	identity, err := s.provider.Exchange(ctx, params.Code, params.Verifier, params.Nonce)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Err(err).Msg("Error exchanging sso code")
+		return nil, err
+	}
+
+	if err := identity.Email.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Str("subject", identity.Subject).Err(err).Msg("SSO identity without valid email")
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInvalidEmail, err)
+	}
+
+	if !identity.EmailVerified {
+		s.log.Warn().Ctx(ctx).Str("email", identity.Email.String()).Msg("SSO email is not verified")
+		return nil, auth_domain.ErrSSOEmailNotVerified
+	}
+
+	role, ok := s.mapping.Resolve(identity.Groups)
+	if !ok {
+		s.recordLoginFailed(ctx, identity.Email.String())
+		s.log.Warn().Ctx(ctx).Str("email", identity.Email.String()).Strs("groups", identity.Groups).Msg("No role mapped for sso user")
+		return nil, auth_domain.ErrSSONoRole
+	}
+
+	user, err := s.repo.FindByEmail(ctx, identity.Email.String())
+	switch {
+	case errors.Is(err, auth_domain.ErrUserNotFound):
+		user, err = s.provision(ctx, identity.Email, role)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		s.log.Error().Ctx(ctx).Str("email", identity.Email.String()).Err(err).Msg("Error finding user by email")
+		return nil, err
+	case user.Role != role:
+		before := newUserState(user)
+		user, err = s.repo.UpdateRole(ctx, user.Email.String(), role)
+		if err != nil {
+			s.log.Error().Ctx(ctx).Str("email", identity.Email.String()).Err(err).Msg("Error syncing sso user role")
+			return nil, err
+		}
+		s.auditor.Record(ctx, audit_domain.Event{
+			Action:     audit_domain.ActionUserRoleChange,
+			EntityType: audit_domain.EntityUser,
+			EntityID:   user.ID,
+			Before:     before,
+			After:      newUserState(user),
+			ActorEmail: user.Email.String(),
+			ActorRole:  "sso",
+		})
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserLogin,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   user.ID,
+		After:      map[string]string{"method": "sso", "subject": identity.Subject},
+		ActorEmail: user.Email.String(),
+		ActorRole:  user.Role.String(),
+	})
+
+	s.log.Info().Ctx(ctx).Str("email", user.Email.String()).Str("role", user.Role.String()).Msg("SSO login successful")
+
+	return user, nil
+}
+
+func (s *SSOService) provision(ctx context.Context, email auth_domain.Email, role auth_domain.Role) (*auth_domain.User, error) {
+	created, err := s.repo.Create(ctx, &auth_domain.User{
+		ID:       uuid.NewString(),
+		Email:    email,
+		Password: ssoPasswordHash,
+		Role:     role,
+	})
+	if err != nil {
+		s.log.Error().Ctx(ctx).Str("email", email.String()).Err(err).Msg("Error provisioning sso user")
+		return nil, err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserRegister,
+		EntityType: audit_domain.EntityUser,
+		EntityID:   created.ID,
+		After:      newUserState(created),
+		ActorEmail: created.Email.String(),
+		ActorRole:  created.Role.String(),
+	})
+
+	s.log.Info().Ctx(ctx).Str("email", email.String()).Str("role", role.String()).Msg("SSO user provisioned")
+
+	return created, nil
+}
+
+func (s *SSOService) recordLoginFailed(ctx context.Context, email string) {
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionUserLoginFailed,
+		EntityType: audit_domain.EntityUser,
+		ActorEmail: email,
+	})
+}
@@ -0,0 +1,264 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/auth/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseRoleMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  map[string]auth_domain.Role
+		expectErr bool
+	}{
+		{
+			name:  "several groups",
+			input: "pvz-moderators:moderator, pvz-staff:employee",
+			expected: map[string]auth_domain.Role{
+				"pvz-moderators": auth_domain.RoleModerator,
+				"pvz-staff":      auth_domain.RoleEmployee,
+			},
+		},
+		{
+			name:  "group with colon",
+			input: "urn:corp:pvz:moderator",
+			expected: map[string]auth_domain.Role{
+				"urn:corp:pvz": auth_domain.RoleModerator,
+			},
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: map[string]auth_domain.Role{},
+		},
+		{
+			name:      "unknown role",
+			input:     "admins:admin",
+			expectErr: true,
+		},
+		{
+			name:      "no role",
+			input:     "admins",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := application.ParseRoleMapping(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mapping)
+		})
+	}
+}
+
+func TestRoleMapping_Resolve(t *testing.T) {
+	mapping := application.RoleMapping{
+		Groups: map[string]auth_domain.Role{
+			"pvz-moderators": auth_domain.RoleModerator,
+			"pvz-staff":      auth_domain.RoleEmployee,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		mapping    application.RoleMapping
+		groups     []string
+		expectRole auth_domain.Role
+		expectOK   bool
+	}{
+		{
+			name:       "employee",
+			mapping:    mapping,
+			groups:     []string{"sales", "pvz-staff"},
+			expectRole: auth_domain.RoleEmployee,
+			expectOK:   true,
+		},
+		{
+			name:       "moderator wins",
+			mapping:    mapping,
+			groups:     []string{"pvz-staff", "pvz-moderators"},
+			expectRole: auth_domain.RoleModerator,
+			expectOK:   true,
+		},
+		{
+			name:     "no mapped groups",
+			mapping:  mapping,
+			groups:   []string{"sales"},
+			expectOK: false,
+		},
+		{
+			name:       "default role",
+			mapping:    application.RoleMapping{Groups: mapping.Groups, Default: auth_domain.RoleEmployee},
+			groups:     nil,
+			expectRole: auth_domain.RoleEmployee,
+			expectOK:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, ok := tt.mapping.Resolve(tt.groups)
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expectRole, role)
+		})
+	}
+}
+
+func TestSSOLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := application.SSOLoginParams{
+		Code:     "code",
+		Verifier: "verifier",
+		Nonce:    "nonce",
+	}
+
+	identity := func(groups ...string) *auth_domain.Identity {
+		return &auth_domain.Identity{
+			Subject:       "sub-1",
+			Email:         "sso@example.com",
+			EmailVerified: true,
+			Groups:        groups,
+		}
+	}
+
+	mapping := application.RoleMapping{
+		Groups: map[string]auth_domain.Role{
+			"pvz-moderators": auth_domain.RoleModerator,
+			"pvz-staff":      auth_domain.RoleEmployee,
+		},
+	}
+
+	existing := &auth_domain.User{
+		ID:    uuid.NewString(),
+		Email: "sso@example.com",
+		Role:  auth_domain.RoleEmployee,
+	}
+
+	tests := []struct {
+		name       string
+		params     application.SSOLoginParams
+		mockSetup  func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository)
+		expectRole auth_domain.Role
+		expectErr  error
+	}{
+		{
+			name:   "first login provisions user",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), "code", "verifier", "nonce").Return(identity("pvz-staff"), nil)
+				r.EXPECT().FindByEmail(gomock.Any(), "sso@example.com").Return(nil, auth_domain.ErrUserNotFound)
+				r.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *auth_domain.User) (*auth_domain.User, error) {
+						return u, nil
+					})
+			},
+			expectRole: auth_domain.RoleEmployee,
+		},
+		{
+			name:   "existing user with same role",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(identity("pvz-staff"), nil)
+				r.EXPECT().FindByEmail(gomock.Any(), "sso@example.com").Return(existing, nil)
+			},
+			expectRole: auth_domain.RoleEmployee,
+		},
+		{
+			name:   "role is synced with groups",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(identity("pvz-moderators"), nil)
+				r.EXPECT().FindByEmail(gomock.Any(), "sso@example.com").Return(existing, nil)
+				r.EXPECT().
+					UpdateRole(gomock.Any(), "sso@example.com", auth_domain.RoleModerator).
+					Return(&auth_domain.User{ID: existing.ID, Email: existing.Email, Role: auth_domain.RoleModerator}, nil)
+			},
+			expectRole: auth_domain.RoleModerator,
+		},
+		{
+			name:   "no mapped group",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(identity("sales"), nil)
+			},
+			expectErr: auth_domain.ErrSSONoRole,
+		},
+		{
+			name:   "email not verified",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				id := identity("pvz-staff")
+				id.EmailVerified = false
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(id, nil)
+			},
+			expectErr: auth_domain.ErrSSOEmailNotVerified,
+		},
+		{
+			name:   "exchange failed",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrSSOExchange)
+			},
+			expectErr: auth_domain.ErrSSOExchange,
+		},
+		{
+			name:      "missing code",
+			params:    application.SSOLoginParams{Verifier: "verifier", Nonce: "nonce"},
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {},
+			expectErr: auth_domain.ErrSSOExchange,
+		},
+		{
+			name:   "db error",
+			params: params,
+			mockSetup: func(p *mocks.MockIdentityProvider, r *mocks.MockUserRepository) {
+				p.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(identity("pvz-staff"), nil)
+				r.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrInternalDatabase)
+			},
+			expectErr: auth_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := mocks.NewMockIdentityProvider(ctrl)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockSetup(provider, repo)
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			svc := application.NewSSOService(repo, provider, mapping, auditor, logger.NewTestLogger())
+			user, err := svc.Login(context.Background(), tt.params)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectRole, user.Role)
+			assert.Equal(t, auth_domain.Email("sso@example.com"), user.Email)
+		})
+	}
+}
@@ -4,28 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/0x0FACED/pvz-avito/internal/auth/application"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 )
 
 type AuthService interface {
 	Register(ctx context.Context, params application.RegisterParams) (*auth_domain.User, error)
 	Login(ctx context.Context, params application.LoginParams) (*auth_domain.User, error)
+	ChangeRole(ctx context.Context, params application.ChangeRoleParams) (*auth_domain.User, error)
+	Unlock(ctx context.Context, params application.UnlockParams) error
 }
 
 type Handler struct {
 	svc AuthService
 
 	jwtManager *httpcommon.JWTManager
+
+	dummyLoginEnabled bool
 }
 
-func NewHandler(svc AuthService, jwt *httpcommon.JWTManager) *Handler {
+func NewHandler(svc AuthService, jwt *httpcommon.JWTManager, dummyLoginEnabled bool) *Handler {
 	return &Handler{
-		svc:        svc,
-		jwtManager: jwt,
+		svc:               svc,
+		jwtManager:        jwt,
+		dummyLoginEnabled: dummyLoginEnabled,
 	}
 }
 
@@ -33,12 +41,18 @@ func (h Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /dummyLogin", h.DummyLogin)
 	mux.HandleFunc("POST /register", h.Register)
 	mux.HandleFunc("POST /login", h.Login)
+	mux.HandleFunc("GET /.well-known/jwks.json", h.JWKS)
+}
+
+func (h Handler) RegisterPrivateRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /users/role", h.ChangeRole)
+	mux.HandleFunc("POST /users/unlock", h.Unlock)
 }
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
 		return
 	}
 
@@ -50,16 +64,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.svc.Register(r.Context(), params)
 	if err != nil {
-		switch {
-		case errors.Is(err, auth_domain.ErrUserAlreadyExists):
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("user already exists"))
-		case errors.Is(err, auth_domain.ErrHashPassword),
-			errors.Is(err, auth_domain.ErrInvalidPassword),
-			errors.Is(err, auth_domain.ErrInvalidEmail):
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid login or password"))
-		default:
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
-		}
+		apperr.WriteHTTP(w, r, err)
 		return
 	}
 
@@ -75,7 +80,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
 		return
 	}
 
@@ -86,23 +91,23 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.svc.Login(r.Context(), params)
 	if err != nil {
+		var attemptsErr *auth_domain.AttemptsError
 		switch {
-		case errors.Is(err, auth_domain.ErrUserAlreadyExists):
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("user already exists"))
+		case errors.As(err, &attemptsErr):
+			retryAfter := int(math.Ceil(attemptsErr.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
 		case errors.Is(err, auth_domain.ErrUserNotFound),
-			errors.Is(err, auth_domain.ErrHashPassword),
-			errors.Is(err, auth_domain.ErrInvalidPassword),
 			errors.Is(err, auth_domain.ErrInvalidEmail):
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid login or password"))
-		default:
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
+			// login does not tell whether user exists
+			err = apperr.ErrInvalidCredentials
 		}
+		apperr.WriteHTTP(w, r, err)
 		return
 	}
 
 	token, err := h.jwtManager.Generate(user.Email.String(), user.Role.String())
 	if err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
+		apperr.WriteHTTP(w, r, err)
 		return
 	}
 
@@ -110,24 +115,96 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) DummyLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.dummyLoginEnabled {
+		apperr.WriteHTTP(w, r, apperr.ErrDummyLoginDisabled)
+		return
+	}
+
 	var req DummyLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
 		return
 	}
 
 	role := auth_domain.Role(req.Role)
 
 	if err := role.Validate(); err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
+		apperr.WriteHTTP(w, r, err)
 		return
 	}
 
 	token, err := h.jwtManager.GenerateDummy(req.Role)
 	if err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
+		apperr.WriteHTTP(w, r, err)
 		return
 	}
 
 	httpcommon.DefaultResponse(w, http.StatusOK, []byte(token))
 }
+
+func (h *Handler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	var req ChangeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
+		return
+	}
+
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	params := application.ChangeRoleParams{
+		Email:    auth_domain.Email(req.Email),
+		Role:     auth_domain.Role(req.Role),
+		UserRole: auth_domain.Role(claims.Role),
+	}
+
+	user, err := h.svc.ChangeRole(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	resp := RegisterResponse{
+		ID:    user.ID,
+		Email: user.Email.String(),
+		Role:  user.Role.String(),
+	}
+
+	httpcommon.JSONResponse(w, http.StatusOK, resp)
+}
+
+func (h *Handler) Unlock(w http.ResponseWriter, r *http.Request) {
+	var req UnlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
+		return
+	}
+
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	params := application.UnlockParams{
+		Email:    auth_domain.Email(req.Email),
+		IP:       req.IP,
+		UserRole: auth_domain.Role(claims.Role),
+	}
+
+	if err := h.svc.Unlock(r.Context(), params); err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	httpcommon.EmptyResponse(w, http.StatusOK)
+}
+
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	// keys are rotated rarely, allow other services to cache them for a while
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httpcommon.JSONResponse(w, http.StatusOK, h.jwtManager.JWKS())
+}
@@ -2,10 +2,15 @@ package http_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	nethttp "net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/0x0FACED/pvz-avito/internal/auth/application"
 	auth_http "github.com/0x0FACED/pvz-avito/internal/auth/delivery/http"
@@ -14,6 +19,7 @@ import (
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -63,7 +69,7 @@ func TestAuthHandler_Register(t *testing.T) {
 					Return(nil, auth_domain.ErrInvalidEmail)
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "invalid login or password",
+			expectErr:      "invalid email",
 		},
 		{
 			name: "user already exists",
@@ -91,7 +97,21 @@ func TestAuthHandler_Register(t *testing.T) {
 					Return(nil, auth_domain.ErrInvalidRole)
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "invalid request",
+			expectErr:      "invalid role",
+		},
+		{
+			name: "weak password",
+			request: auth_http.RegisterRequest{
+				Email:    "user@example.com",
+				Password: "123",
+				Role:     "employee",
+			},
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: must be at least 8 characters", auth_domain.ErrWeakPassword))
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      auth_domain.ErrWeakPassword.Error() + ": must be at least 8 characters",
 		},
 	}
 
@@ -103,7 +123,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			}
 
 			jwtManager := httpcommon.NewManager("test-secret", 3600)
-			handler := auth_http.NewHandler(authSvcMock, jwtManager)
+			handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest("POST", "/register", bytes.NewReader(body))
@@ -139,6 +159,8 @@ func TestAuthHandler_Login(t *testing.T) {
 		expectedStatus int
 		expectToken    bool
 		expectErr      string
+
+		expectRetryAfter string
 	}{
 		{
 			name: "successful login",
@@ -163,6 +185,20 @@ func TestAuthHandler_Login(t *testing.T) {
 			expectedStatus: nethttp.StatusOK,
 			expectToken:    true,
 		},
+		{
+			name: "too many attempts",
+			request: auth_http.LoginRequest{
+				Email:    "test@example.com",
+				Password: "wrongpassword",
+			},
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().Login(gomock.Any(), gomock.Any()).
+					Return(nil, &auth_domain.AttemptsError{RetryAfter: 1500 * time.Millisecond})
+			},
+			expectedStatus:   nethttp.StatusTooManyRequests,
+			expectErr:        "too many login attempts",
+			expectRetryAfter: "2",
+		},
 		{
 			name: "invalid creds",
 			request: auth_http.LoginRequest{
@@ -186,7 +222,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			}
 
 			jwtManager := httpcommon.NewManager("test-secret", 3600)
-			handler := auth_http.NewHandler(authSvcMock, jwtManager)
+			handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
@@ -195,6 +231,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			handler.Login(rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectRetryAfter, rec.Header().Get("Retry-After"))
 
 			if tt.expectErr != "" {
 				var errResp httpcommon.ErrorResponse
@@ -208,6 +245,177 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_ChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		request        auth_http.ChangeRoleRequest
+		userRole       string
+		mockSetup      func(*mocks.MockAuthService)
+		expectedStatus int
+		expectErr      string
+	}{
+		{
+			name: "successful role change",
+			request: auth_http.ChangeRoleRequest{
+				Email: "test@example.com",
+				Role:  "moderator",
+			},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().ChangeRole(
+					gomock.Any(),
+					application.ChangeRoleParams{
+						Email:    auth_domain.Email("test@example.com"),
+						Role:     auth_domain.RoleModerator,
+						UserRole: auth_domain.RoleModerator,
+					},
+				).Return(&auth_domain.User{
+					ID:    uuid.NewString(),
+					Email: "test@example.com",
+					Role:  auth_domain.RoleModerator,
+				}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+		},
+		{
+			name: "access denied for employee",
+			request: auth_http.ChangeRoleRequest{
+				Email: "test@example.com",
+				Role:  "moderator",
+			},
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().ChangeRole(gomock.Any(), gomock.Any()).
+					Return(nil, auth_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectErr:      "access denied",
+		},
+		{
+			name: "user not found",
+			request: auth_http.ChangeRoleRequest{
+				Email: "missing@example.com",
+				Role:  "employee",
+			},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().ChangeRole(gomock.Any(), gomock.Any()).
+					Return(nil, auth_domain.ErrUserNotFound)
+			},
+			expectedStatus: nethttp.StatusNotFound,
+			expectErr:      "user not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authSvcMock := mocks.NewMockAuthService(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(authSvcMock)
+			}
+
+			jwtManager := httpcommon.NewManager("test-secret", 3600)
+			handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/users/role", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Role: tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.ChangeRole(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectErr != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Contains(t, errResp.Error(), tt.expectErr)
+			} else {
+				var resp auth_http.RegisterResponse
+				_ = json.NewDecoder(rec.Body).Decode(&resp)
+				assert.Equal(t, tt.request.Role, resp.Role)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		request        auth_http.UnlockRequest
+		userRole       string
+		mockSetup      func(*mocks.MockAuthService)
+		expectedStatus int
+		expectErr      string
+	}{
+		{
+			name:     "successful unlock",
+			request:  auth_http.UnlockRequest{Email: "test@example.com", IP: "10.0.0.1"},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().Unlock(
+					gomock.Any(),
+					application.UnlockParams{
+						Email:    auth_domain.Email("test@example.com"),
+						IP:       "10.0.0.1",
+						UserRole: auth_domain.RoleModerator,
+					},
+				).Return(nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+		},
+		{
+			name:     "access denied for employee",
+			request:  auth_http.UnlockRequest{Email: "test@example.com"},
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockAuthService) {
+				m.EXPECT().Unlock(gomock.Any(), gomock.Any()).Return(auth_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectErr:      "access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authSvcMock := mocks.NewMockAuthService(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(authSvcMock)
+			}
+
+			jwtManager := httpcommon.NewManager("test-secret", 3600)
+			handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/users/unlock", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Role: tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.Unlock(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectErr != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Contains(t, errResp.Error(), tt.expectErr)
+			}
+		})
+	}
+}
+
 func TestAuthHandler_DummyLogin(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -215,6 +423,7 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 	tests := []struct {
 		name           string
 		request        auth_http.DummyLoginRequest
+		disabled       bool
 		expectedStatus int
 		expectToken    bool
 		expectErr      string
@@ -233,15 +442,24 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 				Role: "notavalidrole",
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "invalid request",
+			expectErr:      "invalid role",
+		},
+		{
+			name: "dummy login disabled",
+			request: auth_http.DummyLoginRequest{
+				Role: "moderator",
+			},
+			disabled:       true,
+			expectedStatus: nethttp.StatusNotFound,
+			expectErr:      "dummy login is disabled",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			authSvcMock := mocks.NewMockAuthService(ctrl)
-			jwtManager := httpcommon.NewManager("test-secret", 3600)
-			handler := auth_http.NewHandler(authSvcMock, jwtManager)
+			jwtManager := httpcommon.NewManager("test-secret", time.Hour)
+			handler := auth_http.NewHandler(authSvcMock, jwtManager, !tt.disabled)
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest("POST", "/dummyLogin", bytes.NewReader(body))
@@ -258,6 +476,11 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			} else if tt.expectToken {
 				token := rec.Body.String()
 				assert.NotEmpty(t, token)
+
+				claims, err := jwtManager.Verify(token)
+				require.NoError(t, err)
+				assert.True(t, claims.Dummy())
+				assert.Equal(t, []string{httpcommon.AudienceDummy}, []string(claims.Audience))
 			}
 		})
 	}
@@ -270,7 +493,7 @@ func TestAuthHandler_InvalidRequest(t *testing.T) {
 	t.Run("invalid request body (register)", func(t *testing.T) {
 		authSvcMock := mocks.NewMockAuthService(ctrl)
 		jwtManager := httpcommon.NewManager("test-secret", 3600)
-		handler := auth_http.NewHandler(authSvcMock, jwtManager)
+		handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
 
 		body, _ := json.Marshal([]byte("{invalid}"))
 
@@ -288,7 +511,7 @@ func TestAuthHandler_InvalidRequest(t *testing.T) {
 	t.Run("invalid request body (login)", func(t *testing.T) {
 		authSvcMock := mocks.NewMockAuthService(ctrl)
 		jwtManager := httpcommon.NewManager("test-secret", 3600)
-		handler := auth_http.NewHandler(authSvcMock, jwtManager)
+		handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
 
 		body, _ := json.Marshal([]byte("{invalid}"))
 
@@ -306,7 +529,7 @@ func TestAuthHandler_InvalidRequest(t *testing.T) {
 	t.Run("invalid request body (dummyLogin)", func(t *testing.T) {
 		authSvcMock := mocks.NewMockAuthService(ctrl)
 		jwtManager := httpcommon.NewManager("test-secret", 3600)
-		handler := auth_http.NewHandler(authSvcMock, jwtManager)
+		handler := auth_http.NewHandler(authSvcMock, jwtManager, true)
 
 		body, _ := json.Marshal([]byte("{invalid}"))
 
@@ -321,3 +544,46 @@ func TestAuthHandler_InvalidRequest(t *testing.T) {
 		assert.Equal(t, "invalid request body", errResp.Error())
 	})
 }
+
+func TestAuthHandler_JWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := httpcommon.NewSigningKey(priv, priv.Public())
+	require.NoError(t, err)
+	keys, err := httpcommon.NewKeySet(key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		jwtManager *httpcommon.JWTManager
+		expectKids []string
+	}{
+		{"hs256 secret is not published", httpcommon.NewManager("test-secret", time.Hour), []string{}},
+		{"asymmetric keys", httpcommon.NewKeySetManager(keys, time.Hour), []string{key.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := auth_http.NewHandler(mocks.NewMockAuthService(ctrl), tt.jwtManager, true)
+
+			req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+			rec := httptest.NewRecorder()
+
+			handler.JWKS(rec, req)
+
+			assert.Equal(t, nethttp.StatusOK, rec.Code)
+
+			var resp httpcommon.JWKS
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+			kids := []string{}
+			for _, k := range resp.Keys {
+				kids = append(kids, k.Kid)
+			}
+			assert.Equal(t, tt.expectKids, kids)
+		})
+	}
+}
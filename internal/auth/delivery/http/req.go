@@ -14,3 +14,13 @@ type RegisterRequest struct {
 	Password string `json:"password"`
 	Role     string `json:"role"`
 }
+
+type ChangeRoleRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type UnlockRequest struct {
+	Email string `json:"email,omitempty"`
+	IP    string `json:"ip,omitempty"`
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+)
+
+type SSOService interface {
+	AuthCodeURL(state, nonce, verifier string) string
+	Login(ctx context.Context, params application.SSOLoginParams) (*auth_domain.User, error)
+}
+
+const (
+	ssoCookieName = "pvz_oidc"
+	ssoCookiePath = "/auth/oidc"
+	ssoCookieTTL  = 10 * time.Minute
+)
+
+// SSOHandler handles OIDC authorization code flow with PKCE.
+// State, nonce and code verifier are kept in short-lived HttpOnly cookie.
+type SSOHandler struct {
+	svc SSOService
+
+	jwtManager *httpcommon.JWTManager
+
+	// secureCookie sets Secure flag, disabled in debug mode for plain http
+	secureCookie bool
+}
+
+func NewSSOHandler(svc SSOService, jwt *httpcommon.JWTManager, secureCookie bool) *SSOHandler {
+	return &SSOHandler{
+		svc:          svc,
+		jwtManager:   jwt,
+		secureCookie: secureCookie,
+	}
+}
+
+func (h SSOHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /auth/oidc/login", h.Login)
+	mux.HandleFunc("GET /auth/oidc/callback", h.Callback)
+}
+
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	state, err1 := randomString()
+	nonce, err2 := randomString()
+	verifier, err3 := randomString()
+	if err := errors.Join(err1, err2, err3); err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookieName,
+		Value:    strings.Join([]string{state, nonce, verifier}, "."),
+		Path:     ssoCookiePath,
+		MaxAge:   int(ssoCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		// Lax is needed, callback is top-level redirect from provider
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, h.svc.AuthCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		apperr.WriteHTTP(w, r, fmt.Errorf("%w: %s", apperr.ErrSSOProvider, providerErr))
+		return
+	}
+
+	cookie, err := r.Cookie(ssoCookieName)
+	if err != nil {
+		apperr.WriteHTTP(w, r, apperr.ErrSSOSessionExpired)
+		return
+	}
+
+	// cookie is one-time
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookieName,
+		Path:     ssoCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 || query.Get("state") == "" || query.Get("state") != parts[0] {
+		apperr.WriteHTTP(w, r, apperr.ErrSSOInvalidState)
+		return
+	}
+
+	params := application.SSOLoginParams{
+		Code:     query.Get("code"),
+		Nonce:    parts[1],
+		Verifier: parts[2],
+	}
+
+	user, err := h.svc.Login(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, auth_domain.ErrInvalidEmail) {
+			// email claim of provider is not usable, it is not client mistake
+			err = apperr.ErrSSOFailed
+		}
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	token, err := h.jwtManager.Generate(user.Email.String(), user.Role.String())
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	httpcommon.DefaultResponse(w, http.StatusOK, []byte(token))
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,167 @@
+package http_test
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/auth/application"
+	auth_http "github.com/0x0FACED/pvz-avito/internal/auth/delivery/http"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/auth/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSSOHandler_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mocks.NewMockSSOService(ctrl)
+	svc.EXPECT().
+		AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(state, nonce, verifier string) string {
+			return "https://idp.example.com/authorize?state=" + url.QueryEscape(state)
+		})
+
+	handler := auth_http.NewSSOHandler(svc, httpcommon.NewManager("test-secret", time.Hour), true)
+
+	req := httptest.NewRequest(nethttp.MethodGet, "/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	handler.Login(w, req)
+
+	require.Equal(t, nethttp.StatusFound, w.Code)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	assert.Equal(t, "pvz_oidc", cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, nethttp.SameSiteLaxMode, cookie.SameSite)
+
+	parts := strings.Split(cookie.Value, ".")
+	require.Len(t, parts, 3)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, parts[0], location.Query().Get("state"))
+}
+
+func TestSSOHandler_Callback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cookie := &nethttp.Cookie{Name: "pvz_oidc", Value: "state.nonce.verifier"}
+
+	tests := []struct {
+		name           string
+		query          string
+		cookie         *nethttp.Cookie
+		mockSetup      func(*mocks.MockSSOService)
+		expectedStatus int
+		expectErr      string
+	}{
+		{
+			name:   "successful login",
+			query:  "?code=code&state=state",
+			cookie: cookie,
+			mockSetup: func(m *mocks.MockSSOService) {
+				m.EXPECT().
+					Login(gomock.Any(), application.SSOLoginParams{Code: "code", Nonce: "nonce", Verifier: "verifier"}).
+					Return(&auth_domain.User{Email: "sso@example.com", Role: auth_domain.RoleEmployee}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+		},
+		{
+			name:           "missing cookie",
+			query:          "?code=code&state=state",
+			mockSetup:      func(m *mocks.MockSSOService) {},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "sso session expired",
+		},
+		{
+			name:           "state mismatch",
+			query:          "?code=code&state=other",
+			cookie:         cookie,
+			mockSetup:      func(m *mocks.MockSSOService) {},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "invalid state",
+		},
+		{
+			name:           "provider error",
+			query:          "?error=access_denied&state=state",
+			cookie:         cookie,
+			mockSetup:      func(m *mocks.MockSSOService) {},
+			expectedStatus: nethttp.StatusUnauthorized,
+			expectErr:      "sso login failed: access_denied",
+		},
+		{
+			name:   "no role mapped",
+			query:  "?code=code&state=state",
+			cookie: cookie,
+			mockSetup: func(m *mocks.MockSSOService) {
+				m.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrSSONoRole)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectErr:      "access denied",
+		},
+		{
+			name:   "exchange failed",
+			query:  "?code=code&state=state",
+			cookie: cookie,
+			mockSetup: func(m *mocks.MockSSOService) {
+				m.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrSSOExchange)
+			},
+			expectedStatus: nethttp.StatusUnauthorized,
+			expectErr:      "sso login failed",
+		},
+		{
+			name:   "internal error",
+			query:  "?code=code&state=state",
+			cookie: cookie,
+			mockSetup: func(m *mocks.MockSSOService) {
+				m.EXPECT().Login(gomock.Any(), gomock.Any()).Return(nil, auth_domain.ErrInternalDatabase)
+			},
+			expectedStatus: nethttp.StatusInternalServerError,
+			expectErr:      "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockSSOService(ctrl)
+			tt.mockSetup(svc)
+
+			jwt := httpcommon.NewManager("test-secret", time.Hour)
+			handler := auth_http.NewSSOHandler(svc, jwt, false)
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/auth/oidc/callback"+tt.query, nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Callback(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectErr != "" {
+				var errResp httpcommon.ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&errResp))
+				assert.Contains(t, errResp.Error(), tt.expectErr)
+				return
+			}
+
+			claims, err := jwt.Verify(w.Body.String())
+			require.NoError(t, err)
+			assert.Equal(t, "sso@example.com", claims.Email)
+			assert.Equal(t, auth_domain.RoleEmployee.String(), claims.Role)
+		})
+	}
+}
@@ -4,6 +4,7 @@ package domain
 import (
 	"fmt"
 	"net/mail"
+	"time"
 )
 
 type Role string
@@ -44,3 +45,16 @@ type User struct {
 	Password string // password hash
 	Role     Role
 }
+
+// LoginAttempts is login attempts counter for email or ip. Failures are
+// failed attempts and attempts in progress, successful ones are released.
+type LoginAttempts struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+	LockedUntil *time.Time
+}
+
+func (a *LoginAttempts) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrUserAlreadyExists = errors.New("auth: user already exists")
@@ -11,9 +15,38 @@ var (
 var (
 	ErrHashPassword    = errors.New("auth: invalid password format")
 	ErrInvalidPassword = errors.New("auth: invalid password")
+	ErrWeakPassword    = errors.New("auth: password does not satisfy policy")
 )
 
 var (
 	ErrInvalidEmail = errors.New("auth: invalid email")
 	ErrInvalidRole  = errors.New("auth: invalid role")
 )
+
+var (
+	ErrAccessDenied = errors.New("auth: only moderators can manage users")
+)
+
+var (
+	ErrTooManyAttempts = errors.New("auth: too many login attempts")
+)
+
+var (
+	ErrSSOExchange         = errors.New("auth: sso code exchange failed")
+	ErrSSOEmailNotVerified = errors.New("auth: sso email is not verified")
+	ErrSSONoRole           = errors.New("auth: no role mapped for sso user groups")
+)
+
+// AttemptsError is returned when login is throttled or locked.
+// It matches ErrTooManyAttempts with errors.Is.
+type AttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AttemptsError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *AttemptsError) Unwrap() error {
+	return ErrTooManyAttempts
+}
@@ -2,9 +2,28 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+// UserRepository contract is checked by repotest.Run for every storage backend.
+// Emails are unique, Create fails with ErrUserAlreadyExists for taken email.
 type UserRepository interface {
 	Create(ctx context.Context, user *User) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
+	UpdateRole(ctx context.Context, email string, role Role) (*User, error)
+	UpdatePassword(ctx context.Context, id string, hash string) error
+}
+
+// LoginAttemptRepository stores login attempts by key (email or ip).
+type LoginAttemptRepository interface {
+	// Get returns attempts for key, zero LoginAttempts if there are none.
+	Get(ctx context.Context, key string) (*LoginAttempts, error)
+	// RegisterAttempt increments counter and compares it with limit in one atomic step:
+	// attempt which makes counter exceed limit locks key until lockUntil.
+	// Counter starts from 1 if last attempt is older than window or lockout is over.
+	// Lockout is disabled if limit <= 0.
+	RegisterAttempt(ctx context.Context, key string, at time.Time, window time.Duration, limit int, lockUntil time.Time) (*LoginAttempts, error)
+	// Release takes back registered attempt, counter does not go below zero.
+	Release(ctx context.Context, key string) error
+	Reset(ctx context.Context, key string) error
 }
@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// Identity is user identity confirmed by external identity provider.
+type Identity struct {
+	Subject       string
+	Email         Email
+	EmailVerified bool
+	Groups        []string
+}
+
+// IdentityProvider is OIDC provider with authorization code flow and PKCE.
+type IdentityProvider interface {
+	// AuthCodeURL returns provider login url with S256 challenge of verifier.
+	AuthCodeURL(state, nonce, verifier string) string
+	// Exchange exchanges code for tokens and returns identity from verified id token.
+	Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error)
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+)
+
+// LoginAttemptMemoryRepository keeps login attempts in process memory.
+// Suitable for single instance deployments only.
+type LoginAttemptMemoryRepository struct {
+	mu       sync.Mutex
+	attempts map[string]auth_domain.LoginAttempts
+	writes   int
+}
+
+// sweepEvery is number of registered attempts between sweeps of expired keys.
+const sweepEvery = 1024
+
+func NewLoginAttemptMemoryRepository() *LoginAttemptMemoryRepository {
+	return &LoginAttemptMemoryRepository{
+		attempts: make(map[string]auth_domain.LoginAttempts),
+	}
+}
+
+func (r *LoginAttemptMemoryRepository) Get(_ context.Context, key string) (*auth_domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok {
+		return &auth_domain.LoginAttempts{Key: key}, nil
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptMemoryRepository) RegisterAttempt(_ context.Context, key string, at time.Time, window time.Duration, limit int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok || attempts.LastFailure.Before(at.Add(-window)) ||
+		(attempts.LockedUntil != nil && !at.Before(*attempts.LockedUntil)) {
+		attempts = auth_domain.LoginAttempts{Key: key}
+	}
+
+	attempts.Failures++
+	attempts.LastFailure = at
+	if attempts.LockedUntil == nil && limit > 0 && attempts.Failures > limit {
+		attempts.LockedUntil = &lockUntil
+	}
+	r.attempts[key] = attempts
+
+	r.writes++
+	if r.writes%sweepEvery == 0 {
+		r.sweep(at, window)
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptMemoryRepository) Release(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok || attempts.Failures == 0 {
+		return nil
+	}
+
+	attempts.Failures--
+	r.attempts[key] = attempts
+
+	return nil
+}
+
+func (r *LoginAttemptMemoryRepository) Reset(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
+
+// sweep removes keys without recent failures and active lockout.
+func (r *LoginAttemptMemoryRepository) sweep(now time.Time, window time.Duration) {
+	for key, attempts := range r.attempts {
+		if attempts.LastFailure.Before(now.Add(-window)) && !attempts.IsLocked(now) {
+			delete(r.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	"github.com/google/uuid"
+)
+
+// UserMemoryRepository keeps users in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type UserMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewUserMemoryRepository(db *memdb.DB) *UserMemoryRepository {
+	return &UserMemoryRepository{db: db}
+}
+
+func (r *UserMemoryRepository) Create(_ context.Context, user *auth_domain.User) (*auth_domain.User, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	if r.findByEmail(user.Email.String()) != nil {
+		return nil, fmt.Errorf("%w: email %s", auth_domain.ErrUserAlreadyExists, user.Email)
+	}
+
+	// id is generated by storage, same as in postgres
+	row := &auth_domain.User{
+		ID:       uuid.NewString(),
+		Email:    user.Email,
+		Password: user.Password,
+		Role:     user.Role,
+	}
+	r.db.Users = append(r.db.Users, row)
+
+	created := *row
+	return &created, nil
+}
+
+func (r *UserMemoryRepository) FindByEmail(_ context.Context, email string) (*auth_domain.User, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	row := r.findByEmail(email)
+	if row == nil {
+		return nil, fmt.Errorf("%w: email %s", auth_domain.ErrUserNotFound, email)
+	}
+
+	user := *row
+	return &user, nil
+}
+
+func (r *UserMemoryRepository) UpdateRole(_ context.Context, email string, role auth_domain.Role) (*auth_domain.User, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := r.findByEmail(email)
+	if row == nil {
+		return nil, fmt.Errorf("%w: email %s", auth_domain.ErrUserNotFound, email)
+	}
+
+	row.Role = role
+
+	// password hash is not returned, same as in postgres
+	return &auth_domain.User{ID: row.ID, Email: row.Email, Role: row.Role}, nil
+}
+
+func (r *UserMemoryRepository) UpdatePassword(_ context.Context, id string, hash string) error {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	for _, row := range r.db.Users {
+		if row.ID == id {
+			row.Password = hash
+			return nil
+		}
+	}
+
+	return auth_domain.ErrUserNotFound
+}
+
+func (r *UserMemoryRepository) findByEmail(email string) *auth_domain.User {
+	for _, row := range r.db.Users {
+		if row.Email.String() == email {
+			return row
+		}
+	}
+
+	return nil
+}
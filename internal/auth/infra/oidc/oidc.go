@@ -0,0 +1,109 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim is id token claim with user groups.
+	GroupsClaim string
+}
+
+// Provider is OIDC identity provider, endpoints and keys are taken from issuer discovery.
+type Provider struct {
+	oauth       oauth2.Config
+	verifier    *gooidc.IDTokenVerifier
+	groupsClaim string
+}
+
+// NewProvider makes discovery request to issuer.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	provider, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+	}
+
+	return &Provider{
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier:    provider.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+		groupsClaim: cfg.GroupsClaim,
+	}, nil
+}
+
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*auth_domain.Identity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrSSOExchange, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no id_token in token response", auth_domain.ErrSSOExchange)
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrSSOExchange, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", auth_domain.ErrSSOExchange)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrSSOExchange, err)
+	}
+
+	// only explicit email_verified=true is trusted, missing claim means
+	// provider does not verify emails and account could be taken over by email
+	identity := &auth_domain.Identity{
+		Subject: idToken.Subject,
+	}
+
+	if email, ok := claims["email"].(string); ok {
+		identity.Email = auth_domain.Email(email)
+	}
+	if verified, ok := claims["email_verified"].(bool); ok {
+		identity.EmailVerified = verified
+	}
+
+	// groups claim is array of strings in most providers, single string in some
+	switch groups := claims[p.groupsClaim].(type) {
+	case []any:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				identity.Groups = append(identity.Groups, s)
+			}
+		}
+	case string:
+		identity.Groups = []string{groups}
+	}
+
+	return identity, nil
+}
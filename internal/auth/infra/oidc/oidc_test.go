@@ -0,0 +1,120 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/auth/infra/oidc"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID     = "pvz"
+	clientSecret = "pvz-secret"
+	redirectURL  = "http://localhost:8080/auth/oidc/callback"
+)
+
+// authorize follows provider authorization redirect and returns code and state.
+func authorize(t *testing.T, authURL string) (code, state string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	mock := oidctest.New(clientID, clientSecret)
+	defer mock.Close()
+
+	verified, notVerified := true, false
+
+	ctx := context.Background()
+	provider, err := oidc.NewProvider(ctx, oidc.Config{
+		Issuer:       mock.Issuer(),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		GroupsClaim:  "groups",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		user           oidctest.User
+		verifier       string
+		nonce          string
+		expectErr      error
+		expectVerified bool
+	}{
+		{
+			name:           "successful exchange",
+			user:           oidctest.User{Subject: "sub-1", Email: "emp@example.com", EmailVerified: &verified, Groups: []string{"pvz-staff"}},
+			expectVerified: true,
+		},
+		{
+			name:     "email not verified",
+			user:     oidctest.User{Subject: "sub-2", Email: "emp@example.com", EmailVerified: &notVerified},
+			verifier: "",
+		},
+		{
+			name: "email_verified claim missing",
+			user: oidctest.User{Subject: "sub-5", Email: "emp@example.com"},
+		},
+		{
+			name:      "wrong verifier",
+			user:      oidctest.User{Subject: "sub-3", Email: "emp@example.com"},
+			verifier:  "wrong-verifier-wrong-verifier-wrong-verifier",
+			expectErr: auth_domain.ErrSSOExchange,
+		},
+		{
+			name:      "wrong nonce",
+			user:      oidctest.User{Subject: "sub-4", Email: "emp@example.com"},
+			nonce:     "other-nonce",
+			expectErr: auth_domain.ErrSSOExchange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.SetUser(tt.user)
+
+			verifier := "verifier-verifier-verifier-verifier-verifier"
+			code, state := authorize(t, provider.AuthCodeURL("state-1", "nonce-1", verifier))
+			assert.Equal(t, "state-1", state)
+
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			identity, err := provider.Exchange(ctx, code, verifier, nonce)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.user.Subject, identity.Subject)
+			assert.Equal(t, auth_domain.Email(tt.user.Email), identity.Email)
+			assert.Equal(t, tt.user.Groups, identity.Groups)
+			assert.Equal(t, tt.expectVerified, identity.EmailVerified)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAttemptPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptPostgresRepository(pgx *pgxpool.Pool) *LoginAttemptPostgresRepository {
+	return &LoginAttemptPostgresRepository{
+		pool: pgx,
+	}
+}
+
+func (r *LoginAttemptPostgresRepository) Get(ctx context.Context, key string) (*auth_domain.LoginAttempts, error) {
+	query := `
+		SELECT key, failures, last_failure, locked_until
+		FROM avito.login_attempts
+		WHERE key = @key
+	`
+
+	args := pgx.NamedArgs{
+		"key": key,
+	}
+
+	attempts := auth_domain.LoginAttempts{}
+	err := r.pool.QueryRow(ctx, query, args).Scan(
+		&attempts.Key, &attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &auth_domain.LoginAttempts{Key: key}, nil
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptPostgresRepository) RegisterAttempt(ctx context.Context, key string, at time.Time, window time.Duration, limit int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+	// row is locked by upsert, so counter and lock of concurrent attempts
+	// are computed one after another
+	query := `
+		INSERT INTO avito.login_attempts (key, failures, last_failure)
+		VALUES (@key, 1, @at)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN avito.login_attempts.last_failure < @window_start
+				  OR avito.login_attempts.locked_until <= @at THEN 1
+				ELSE avito.login_attempts.failures + 1
+			END,
+			locked_until = CASE
+				WHEN avito.login_attempts.last_failure < @window_start
+				  OR avito.login_attempts.locked_until <= @at THEN NULL
+				WHEN avito.login_attempts.locked_until IS NULL
+				  AND @limit::int > 0
+				  AND avito.login_attempts.failures + 1 > @limit::int THEN @lock_until::timestamp
+				ELSE avito.login_attempts.locked_until
+			END,
+			last_failure = @at
+		RETURNING key, failures, last_failure, locked_until
+	`
+
+	args := pgx.NamedArgs{
+		"key":          key,
+		"at":           at,
+		"window_start": at.Add(-window),
+		"limit":        limit,
+		"lock_until":   lockUntil,
+	}
+
+	attempts := auth_domain.LoginAttempts{}
+	err := r.pool.QueryRow(ctx, query, args).Scan(
+		&attempts.Key, &attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptPostgresRepository) Release(ctx context.Context, key string) error {
+	query := `
+		UPDATE avito.login_attempts
+		SET failures = GREATEST(failures - 1, 0)
+		WHERE key = @key
+	`
+
+	args := pgx.NamedArgs{
+		"key": key,
+	}
+
+	if _, err := r.pool.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptPostgresRepository) Reset(ctx context.Context, key string) error {
+	query := `
+		DELETE FROM avito.login_attempts
+		WHERE key = @key
+	`
+
+	args := pgx.NamedArgs{
+		"key": key,
+	}
+
+	if _, err := r.pool.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
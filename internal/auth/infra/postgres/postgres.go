@@ -43,11 +43,10 @@ func (r *AuthPostgresRepository) Create(ctx context.Context, user *auth_domain.U
 
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr); pgErr.Code == "23505" {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return nil, fmt.Errorf("%w: %w", auth_domain.ErrUserAlreadyExists, err)
 		}
-		// in openapi wrote that 201 and 400 codes only.
-		// so using domain.ErrInternalDatabase as invalid request (400)
+		// other failures are not caused by request, they are reported as internal (500)
 		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
 	}
 
@@ -77,3 +76,53 @@ func (r *AuthPostgresRepository) FindByEmail(ctx context.Context, email string)
 
 	return &user, nil
 }
+
+func (r *AuthPostgresRepository) UpdateRole(ctx context.Context, email string, role auth_domain.Role) (*auth_domain.User, error) {
+	query := `
+		UPDATE avito.users
+		SET role = @role
+		WHERE email = @email
+		RETURNING id, email, role
+	`
+
+	args := pgx.NamedArgs{
+		"email": email,
+		"role":  role,
+	}
+
+	user := auth_domain.User{}
+
+	err := r.pool.QueryRow(ctx, query, args).Scan(&user.ID, &user.Email, &user.Role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", auth_domain.ErrUserNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &user, nil
+}
+
+func (r *AuthPostgresRepository) UpdatePassword(ctx context.Context, id string, hash string) error {
+	query := `
+		UPDATE avito.users
+		SET password_hash = @password_hash
+		WHERE id = @id
+	`
+
+	args := pgx.NamedArgs{
+		"id":            id,
+		"password_hash": hash,
+	}
+
+	tag, err := r.pool.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return auth_domain.ErrUserNotFound
+	}
+
+	return nil
+}
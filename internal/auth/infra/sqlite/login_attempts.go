@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+)
+
+type LoginAttemptSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptSQLiteRepository(db *sql.DB) *LoginAttemptSQLiteRepository {
+	return &LoginAttemptSQLiteRepository{db: db}
+}
+
+func (r *LoginAttemptSQLiteRepository) Get(ctx context.Context, key string) (*auth_domain.LoginAttempts, error) {
+	query := `
+		SELECT key, failures, last_failure, locked_until
+		FROM login_attempts
+		WHERE key = @key
+	`
+
+	attempts := auth_domain.LoginAttempts{}
+	err := r.db.QueryRowContext(ctx, query, sql.Named("key", key)).Scan(
+		&attempts.Key, &attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &auth_domain.LoginAttempts{Key: key}, nil
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptSQLiteRepository) RegisterAttempt(ctx context.Context, key string, at time.Time, window time.Duration, limit int, lockUntil time.Time) (*auth_domain.LoginAttempts, error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure)
+		VALUES (@key, 1, @at)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure < @window_start
+				  OR login_attempts.locked_until <= @at THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_attempts.last_failure < @window_start
+				  OR login_attempts.locked_until <= @at THEN NULL
+				WHEN login_attempts.locked_until IS NULL
+				  AND @limit > 0
+				  AND login_attempts.failures + 1 > @limit THEN @lock_until
+				ELSE login_attempts.locked_until
+			END,
+			last_failure = @at
+		RETURNING key, failures, last_failure, locked_until
+	`
+
+	attempts := auth_domain.LoginAttempts{}
+	err := r.db.QueryRowContext(ctx, query,
+		sql.Named("key", key),
+		sql.Named("at", database.SQLiteTime(at)),
+		sql.Named("window_start", database.SQLiteTime(at.Add(-window))),
+		sql.Named("limit", limit),
+		sql.Named("lock_until", database.SQLiteTime(lockUntil)),
+	).Scan(&attempts.Key, &attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &attempts, nil
+}
+
+func (r *LoginAttemptSQLiteRepository) Release(ctx context.Context, key string) error {
+	query := `
+		UPDATE login_attempts
+		SET failures = MAX(failures - 1, 0)
+		WHERE key = @key
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sql.Named("key", key)); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptSQLiteRepository) Reset(ctx context.Context, key string) error {
+	query := `
+		DELETE FROM login_attempts
+		WHERE key = @key
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sql.Named("key", key)); err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/google/uuid"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type AuthSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewAuthSQLiteRepository(db *sql.DB) *AuthSQLiteRepository {
+	return &AuthSQLiteRepository{db: db}
+}
+
+func (r *AuthSQLiteRepository) Create(ctx context.Context, user *auth_domain.User) (*auth_domain.User, error) {
+	query := `
+		INSERT INTO users (id, email, password_hash, role)
+		VALUES (@id, @email, @password_hash, @role)
+		RETURNING id, email, role
+	`
+
+	// there is no gen_random_uuid in sqlite
+	id := user.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	var created auth_domain.User
+	created.Password = user.Password
+
+	err := r.db.QueryRowContext(ctx, query,
+		sql.Named("id", id),
+		sql.Named("email", user.Email.String()),
+		sql.Named("password_hash", user.Password),
+		sql.Named("role", user.Role.String()),
+	).Scan(&created.ID, &created.Email, &created.Role)
+	if err != nil {
+		if database.SQLiteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+			return nil, fmt.Errorf("%w: %w", auth_domain.ErrUserAlreadyExists, err)
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &created, nil
+}
+
+func (r *AuthSQLiteRepository) FindByEmail(ctx context.Context, email string) (*auth_domain.User, error) {
+	query := `
+		SELECT id, email, password_hash, role
+		FROM users
+		WHERE email = @email
+	`
+
+	user := auth_domain.User{}
+	err := r.db.QueryRowContext(ctx, query, sql.Named("email", email)).Scan(&user.ID, &user.Email, &user.Password, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", auth_domain.ErrUserNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &user, nil
+}
+
+func (r *AuthSQLiteRepository) UpdateRole(ctx context.Context, email string, role auth_domain.Role) (*auth_domain.User, error) {
+	query := `
+		UPDATE users
+		SET role = @role
+		WHERE email = @email
+		RETURNING id, email, role
+	`
+
+	user := auth_domain.User{}
+	err := r.db.QueryRowContext(ctx, query,
+		sql.Named("email", email),
+		sql.Named("role", role.String()),
+	).Scan(&user.ID, &user.Email, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", auth_domain.ErrUserNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	return &user, nil
+}
+
+func (r *AuthSQLiteRepository) UpdatePassword(ctx context.Context, id string, hash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = @password_hash
+		WHERE id = @id
+	`
+
+	res, err := r.db.ExecContext(ctx, query,
+		sql.Named("id", id),
+		sql.Named("password_hash", hash),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: %w", auth_domain.ErrInternalDatabase, err)
+	}
+
+	if affected == 0 {
+		return auth_domain.ErrUserNotFound
+	}
+
+	return nil
+}
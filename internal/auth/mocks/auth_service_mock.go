@@ -42,6 +42,21 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangeRole mocks base method.
+func (m *MockAuthService) ChangeRole(ctx context.Context, params application.ChangeRoleParams) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", ctx, params)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockAuthServiceMockRecorder) ChangeRole(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockAuthService)(nil).ChangeRole), ctx, params)
+}
+
 // Login mocks base method.
 func (m *MockAuthService) Login(ctx context.Context, params application.LoginParams) (*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -71,3 +86,17 @@ func (mr *MockAuthServiceMockRecorder) Register(ctx, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), ctx, params)
 }
+
+// Unlock mocks base method.
+func (m *MockAuthService) Unlock(ctx context.Context, params application.UnlockParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockAuthServiceMockRecorder) Unlock(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockAuthService)(nil).Unlock), ctx, params)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auth/delivery/http/sso.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/auth/delivery/http/sso.go -destination=internal/auth/mocks/sso_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	application "github.com/0x0FACED/pvz-avito/internal/auth/application"
+	domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSSOService is a mock of SSOService interface.
+type MockSSOService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSOServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSSOServiceMockRecorder is the mock recorder for MockSSOService.
+type MockSSOServiceMockRecorder struct {
+	mock *MockSSOService
+}
+
+// NewMockSSOService creates a new mock instance.
+func NewMockSSOService(ctrl *gomock.Controller) *MockSSOService {
+	mock := &MockSSOService{ctrl: ctrl}
+	mock.recorder = &MockSSOServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSOService) EXPECT() *MockSSOServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockSSOService) AuthCodeURL(state, nonce, verifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, verifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockSSOServiceMockRecorder) AuthCodeURL(state, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockSSOService)(nil).AuthCodeURL), state, nonce, verifier)
+}
+
+// Login mocks base method.
+func (m *MockSSOService) Login(ctx context.Context, params application.SSOLoginParams) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, params)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockSSOServiceMockRecorder) Login(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockSSOService)(nil).Login), ctx, params)
+}
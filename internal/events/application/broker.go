@@ -0,0 +1,181 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/google/uuid"
+)
+
+// Broker fans out events to subscribers and keeps last events for backfill.
+// Broker lives in process, so subscribers get events of writes
+// handled by this instance only.
+type Broker struct {
+	mu sync.Mutex
+	// epoch changes on every start, cursors of previous run are expired
+	epoch string
+	seq   uint64
+	// history keeps last backfillSize events, oldest first
+	history      []events_domain.Event
+	backfillSize int
+	subBuffer    int
+	subs         map[*Subscription]struct{}
+	closed       bool
+}
+
+func NewBroker(backfillSize, subscriberBuffer int) *Broker {
+	return &Broker{
+		epoch:        strings.ReplaceAll(uuid.NewString(), "-", "")[:8],
+		history:      make([]events_domain.Event, 0, backfillSize),
+		backfillSize: backfillSize,
+		subBuffer:    subscriberBuffer,
+		subs:         make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events matching its filter. Events channel is closed
+// when subscription is closed, broker is closed or subscriber does not read
+// events fast enough. Subscriber resubscribes with cursor of last event then.
+type Subscription struct {
+	// Backfill contains events published after cursor passed to Subscribe.
+	Backfill []events_domain.Event
+
+	events chan events_domain.Event
+	filter events_domain.Filter
+	broker *Broker
+}
+
+func (s *Subscription) Events() <-chan events_domain.Event {
+	return s.events
+}
+
+// Close stops subscription, it is safe to call it more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+// Subscribe registers subscriber. If cursor is not empty, events published
+// after it are returned in Backfill, so no event is lost between backfill and live events.
+func (b *Broker) Subscribe(filter events_domain.Filter, cursor string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, events_domain.ErrBrokerClosed
+	}
+
+	sub := &Subscription{
+		events: make(chan events_domain.Event, b.subBuffer),
+		filter: filter,
+		broker: b,
+	}
+
+	if cursor != "" {
+		after, err := b.parseCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		// events between cursor and the oldest kept event are lost
+		if len(b.history) == 0 && after < b.seq ||
+			len(b.history) > 0 && after+1 < b.history[0].Seq {
+			return nil, fmt.Errorf("%w: %s", events_domain.ErrCursorExpired, cursor)
+		}
+
+		for _, e := range b.history {
+			if e.Seq > after && filter.Match(e) {
+				sub.Backfill = append(sub.Backfill, e)
+			}
+		}
+	}
+
+	b.subs[sub] = struct{}{}
+	metrics.EventSubscribers.Inc()
+
+	return sub, nil
+}
+
+// Publish assigns cursor to event and sends it to matching subscribers.
+// Publish never blocks, subscribers with full buffer are dropped.
+func (b *Broker) Publish(event events_domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	event.Seq = b.seq
+	event.Cursor = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+
+	if b.backfillSize > 0 {
+		if len(b.history) == b.backfillSize {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, event)
+	}
+
+	metrics.EventsPublishedTotal.WithLabelValues(event.Type.String()).Inc()
+
+	for sub := range b.subs {
+		if !sub.filter.Match(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+			metrics.EventSubscribersDroppedTotal.Inc()
+		}
+	}
+}
+
+// Close ends all subscriptions, new subscriptions are rejected.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+	b.closed = true
+}
+
+// remove must be called under lock.
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+
+	delete(b.subs, sub)
+	close(sub.events)
+	metrics.EventSubscribers.Dec()
+}
+
+// parseCursor returns seq of cursor "<epoch>-<seq>".
+func (b *Broker) parseCursor(cursor string) (uint64, error) {
+	epoch, rawSeq, ok := strings.Cut(cursor, "-")
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", events_domain.ErrInvalidCursor, cursor)
+	}
+
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", events_domain.ErrInvalidCursor, cursor)
+	}
+
+	if epoch != b.epoch || seq > b.seq {
+		return 0, fmt.Errorf("%w: %s", events_domain.ErrCursorExpired, cursor)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,144 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, sub *application.Subscription) events_domain.Event {
+	t.Helper()
+
+	select {
+	case e, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed")
+		return e
+	default:
+		require.FailNow(t, "no event")
+		return events_domain.Event{}
+	}
+}
+
+func TestBroker_PublishToMatchingSubscribers(t *testing.T) {
+	broker := application.NewBroker(10, 10)
+
+	all, err := broker.Subscribe(events_domain.Filter{}, "")
+	require.NoError(t, err)
+	byPVZ, err := broker.Subscribe(events_domain.Filter{PVZID: "pvz-1"}, "")
+	require.NoError(t, err)
+	byCity, err := broker.Subscribe(events_domain.Filter{City: pvz_domain.Kazan}, "")
+	require.NoError(t, err)
+
+	broker.Publish(events_domain.Event{Type: events_domain.ReceptionOpened, PVZID: "pvz-1", City: pvz_domain.Moscow})
+	broker.Publish(events_domain.Event{Type: events_domain.ReceptionOpened, PVZID: "pvz-2", City: pvz_domain.Kazan})
+
+	first := receive(t, all)
+	second := receive(t, all)
+	assert.Equal(t, "pvz-1", first.PVZID)
+	assert.Equal(t, "pvz-2", second.PVZID)
+	assert.NotEqual(t, first.Cursor, second.Cursor)
+	assert.Less(t, first.Seq, second.Seq)
+
+	assert.Equal(t, "pvz-1", receive(t, byPVZ).PVZID)
+	assert.Empty(t, byPVZ.Events())
+	assert.Equal(t, "pvz-2", receive(t, byCity).PVZID)
+	assert.Empty(t, byCity.Events())
+}
+
+func TestBroker_Backfill(t *testing.T) {
+	broker := application.NewBroker(3, 10)
+
+	sub, err := broker.Subscribe(events_domain.Filter{}, "")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	for _, pvzID := range []string{"pvz-1", "pvz-2", "pvz-2", "pvz-1", "pvz-1"} {
+		broker.Publish(events_domain.Event{Type: events_domain.ProductAdded, PVZID: pvzID})
+	}
+
+	var cursors []string
+	for range 5 {
+		cursors = append(cursors, receive(t, sub).Cursor)
+	}
+
+	t.Run("events after cursor", func(t *testing.T) {
+		resumed, err := broker.Subscribe(events_domain.Filter{PVZID: "pvz-1"}, cursors[2])
+		require.NoError(t, err)
+		defer resumed.Close()
+
+		require.Len(t, resumed.Backfill, 2)
+		assert.Equal(t, cursors[3], resumed.Backfill[0].Cursor)
+		assert.Equal(t, cursors[4], resumed.Backfill[1].Cursor)
+	})
+
+	t.Run("cursor right before oldest kept event", func(t *testing.T) {
+		resumed, err := broker.Subscribe(events_domain.Filter{}, cursors[1])
+		require.NoError(t, err)
+		defer resumed.Close()
+
+		assert.Len(t, resumed.Backfill, 3)
+	})
+
+	t.Run("up to date cursor", func(t *testing.T) {
+		resumed, err := broker.Subscribe(events_domain.Filter{}, cursors[4])
+		require.NoError(t, err)
+		defer resumed.Close()
+
+		assert.Empty(t, resumed.Backfill)
+	})
+
+	t.Run("evicted cursor", func(t *testing.T) {
+		_, err := broker.Subscribe(events_domain.Filter{}, cursors[0])
+		assert.ErrorIs(t, err, events_domain.ErrCursorExpired)
+	})
+
+	t.Run("cursor of other broker", func(t *testing.T) {
+		_, err := application.NewBroker(3, 10).Subscribe(events_domain.Filter{}, cursors[4])
+		assert.ErrorIs(t, err, events_domain.ErrCursorExpired)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := broker.Subscribe(events_domain.Filter{}, "garbage")
+		assert.ErrorIs(t, err, events_domain.ErrInvalidCursor)
+	})
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := application.NewBroker(10, 1)
+
+	slow, err := broker.Subscribe(events_domain.Filter{}, "")
+	require.NoError(t, err)
+
+	broker.Publish(events_domain.Event{Type: events_domain.ProductAdded})
+	broker.Publish(events_domain.Event{Type: events_domain.ProductAdded})
+
+	// buffered event is delivered, then channel is closed
+	receive(t, slow)
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+
+	slow.Close()
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := application.NewBroker(10, 10)
+
+	sub, err := broker.Subscribe(events_domain.Filter{}, "")
+	require.NoError(t, err)
+
+	broker.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+
+	_, err = broker.Subscribe(events_domain.Filter{}, "")
+	assert.ErrorIs(t, err, events_domain.ErrBrokerClosed)
+
+	// publishing after close is no-op
+	broker.Publish(events_domain.Event{Type: events_domain.ProductAdded})
+	sub.Close()
+}
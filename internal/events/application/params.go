@@ -0,0 +1,39 @@
+package application
+
+import (
+	"fmt"
+
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/google/uuid"
+)
+
+type SubscribeParams struct {
+	// PVZID and City are optional, subscriber gets events of all pvz if both are empty.
+	PVZID string
+	City  pvz_domain.City
+	// Cursor of last received event, events after it are backfilled.
+	Cursor string
+	// AllowedPVZID is set for api keys restricted to one pvz.
+	AllowedPVZID string
+}
+
+func (p SubscribeParams) Validate() error {
+	if p.PVZID != "" {
+		if err := uuid.Validate(p.PVZID); err != nil {
+			return fmt.Errorf("%w: %w", events_domain.ErrInvalidIDFormat, err)
+		}
+	}
+
+	if p.City != "" {
+		if err := p.City.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", events_domain.ErrUnsupportedCity, err)
+		}
+	}
+
+	if p.AllowedPVZID != "" && p.AllowedPVZID != p.PVZID {
+		return events_domain.ErrAccessDenied
+	}
+
+	return nil
+}
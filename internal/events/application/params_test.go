@@ -0,0 +1,35 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SubscribeParams_Validate(t *testing.T) {
+	validID := uuid.NewString()
+
+	tests := []struct {
+		name    string
+		params  application.SubscribeParams
+		wantErr bool
+	}{
+		{"all events", application.SubscribeParams{}, false},
+		{"pvz", application.SubscribeParams{PVZID: validID}, false},
+		{"city", application.SubscribeParams{City: pvz_domain.Kazan}, false},
+		{"invalid UUID", application.SubscribeParams{PVZID: "notanuuid"}, true},
+		{"unsupported city", application.SubscribeParams{City: "Тверь"}, true},
+		{"allowed pvz", application.SubscribeParams{PVZID: validID, AllowedPVZID: validID}, false},
+		{"other pvz not allowed", application.SubscribeParams{PVZID: validID, AllowedPVZID: uuid.NewString()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// Publisher is auditor which also publishes reception and product changes to broker.
+// Every mutating operation is already recorded to audit, so services
+// do not need to know about events.
+type Publisher struct {
+	next          audit_domain.Auditor
+	broker        *Broker
+	receptionRepo reception_domain.ReceptionRepository
+	pvzRepo       pvz_domain.PVZRepository
+
+	// city of pvz never changes, so it is cached forever
+	citiesMu sync.RWMutex
+	cities   map[string]pvz_domain.City
+
+	log *logger.ZerologLogger
+}
+
+func NewPublisher(
+	next audit_domain.Auditor,
+	broker *Broker,
+	receptionRepo reception_domain.ReceptionRepository,
+	pvzRepo pvz_domain.PVZRepository,
+	l *logger.ZerologLogger,
+) *Publisher {
+	return &Publisher{
+		next:          next,
+		broker:        broker,
+		receptionRepo: receptionRepo,
+		pvzRepo:       pvzRepo,
+		cities:        make(map[string]pvz_domain.City),
+		log:           l,
+	}
+}
+
+func (p *Publisher) Record(ctx context.Context, event audit_domain.Event) {
+	p.next.Record(ctx, event)
+
+	// operation is already done, lookups must not fail because request is finished
+	ctx = context.WithoutCancel(ctx)
+
+	var (
+		e   events_domain.Event
+		err error
+	)
+	switch after := event.After.(type) {
+	case *reception_domain.Reception:
+		e, err = p.receptionEvent(ctx, event.Action, after)
+	case *product_domain.Product:
+		e, err = p.productEvent(ctx, event.Action, after)
+	default:
+		return
+	}
+	if err != nil {
+		p.log.Error().Ctx(ctx).Any("event", event).Err(err).Msg("Error building event")
+		return
+	}
+	if e.Type == "" {
+		return
+	}
+
+	// subscribers must not see changes of transaction which is rolled back
+	database.AfterCommit(ctx, func() {
+		p.broker.Publish(e)
+	})
+}
+
+func (p *Publisher) receptionEvent(ctx context.Context, action audit_domain.Action, reception *reception_domain.Reception) (events_domain.Event, error) {
+	e := events_domain.Event{
+		At:          reception.DateTime,
+		PVZID:       reception.PVZID,
+		ReceptionID: reception.ID,
+	}
+
+	switch action {
+	case audit_domain.ActionReceptionOpen:
+		e.Type = events_domain.ReceptionOpened
+	case audit_domain.ActionReceptionClose:
+		e.Type = events_domain.ReceptionClosed
+		if reception.ClosedAt != nil {
+			e.At = *reception.ClosedAt
+		}
+	default:
+		return events_domain.Event{}, nil
+	}
+
+	city, err := p.city(ctx, reception.PVZID)
+	if err != nil {
+		return events_domain.Event{}, err
+	}
+	e.City = city
+
+	return e, nil
+}
+
+func (p *Publisher) productEvent(ctx context.Context, action audit_domain.Action, product *product_domain.Product) (events_domain.Event, error) {
+	e := events_domain.Event{
+		At:          product.DateTime,
+		ReceptionID: product.ReceptionID,
+		ProductID:   product.ID,
+		ProductType: product.Type,
+	}
+
+	switch action {
+	case audit_domain.ActionProductAdd:
+		e.Type = events_domain.ProductAdded
+	case audit_domain.ActionProductDelete:
+		e.Type = events_domain.ProductRemoved
+		if product.DeletedAt != nil {
+			e.At = *product.DeletedAt
+		}
+	case audit_domain.ActionProductRestore:
+		e.Type = events_domain.ProductRestored
+		e.At = time.Now()
+	default:
+		return events_domain.Event{}, nil
+	}
+
+	reception, err := p.receptionRepo.FindByID(ctx, product.ReceptionID)
+	if err != nil {
+		return events_domain.Event{}, err
+	}
+	e.PVZID = reception.PVZID
+
+	city, err := p.city(ctx, reception.PVZID)
+	if err != nil {
+		return events_domain.Event{}, err
+	}
+	e.City = city
+
+	return e, nil
+}
+
+func (p *Publisher) city(ctx context.Context, pvzID string) (pvz_domain.City, error) {
+	p.citiesMu.RLock()
+	city, ok := p.cities[pvzID]
+	p.citiesMu.RUnlock()
+	if ok {
+		return city, nil
+	}
+
+	pvz, err := p.pvzRepo.GetByID(ctx, pvzID)
+	if err != nil {
+		return "", err
+	}
+
+	p.citiesMu.Lock()
+	p.cities[pvzID] = pvz.City
+	p.citiesMu.Unlock()
+
+	return pvz.City, nil
+}
@@ -0,0 +1,132 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	pvz_mocks "github.com/0x0FACED/pvz-avito/internal/pvz/mocks"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_mocks "github.com/0x0FACED/pvz-avito/internal/reception/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type publisherMocks struct {
+	auditor   *audit_mocks.MockAuditor
+	reception *reception_mocks.MockReceptionRepository
+	pvz       *pvz_mocks.MockPVZRepository
+}
+
+func newPublisher(ctrl *gomock.Controller) (*application.Publisher, *application.Subscription, publisherMocks) {
+	m := publisherMocks{
+		auditor:   audit_mocks.NewMockAuditor(ctrl),
+		reception: reception_mocks.NewMockReceptionRepository(ctrl),
+		pvz:       pvz_mocks.NewMockPVZRepository(ctrl),
+	}
+
+	broker := application.NewBroker(10, 10)
+	sub, err := broker.Subscribe(events_domain.Filter{}, "")
+	if err != nil {
+		panic(err)
+	}
+
+	return application.NewPublisher(m.auditor, broker, m.reception, m.pvz, logger.NewTestLogger()), sub, m
+}
+
+func TestPublisher_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	now := time.Now()
+	pvzID := "pvz-1"
+	pvz := &pvz_domain.PVZ{ID: &pvzID, City: pvz_domain.Kazan}
+	reception := &reception_domain.Reception{ID: "rec-1", DateTime: now, PVZID: pvzID, Status: reception_domain.InProgress}
+	product := &product_domain.Product{ID: "prod-1", DateTime: now, Type: product_domain.Shoes, ReceptionID: reception.ID}
+
+	publisher, sub, m := newPublisher(ctrl)
+
+	t.Run("reception opened", func(t *testing.T) {
+		event := audit_domain.Event{Action: audit_domain.ActionReceptionOpen, After: reception}
+		m.auditor.EXPECT().Record(ctx, event)
+		// city is looked up once and cached
+		m.pvz.EXPECT().GetByID(gomock.Any(), pvzID).Return(pvz, nil).Times(1)
+
+		publisher.Record(ctx, event)
+
+		e := receive(t, sub)
+		assert.Equal(t, events_domain.ReceptionOpened, e.Type)
+		assert.Equal(t, pvzID, e.PVZID)
+		assert.Equal(t, pvz_domain.Kazan, e.City)
+		assert.Equal(t, reception.ID, e.ReceptionID)
+		assert.Equal(t, now, e.At)
+	})
+
+	t.Run("product removed", func(t *testing.T) {
+		deletedAt := now.Add(time.Minute)
+		deleted := *product
+		deleted.DeletedAt = &deletedAt
+
+		event := audit_domain.Event{Action: audit_domain.ActionProductDelete, Before: product, After: &deleted}
+		m.auditor.EXPECT().Record(ctx, event)
+		m.reception.EXPECT().FindByID(gomock.Any(), reception.ID).Return(reception, nil)
+
+		publisher.Record(ctx, event)
+
+		e := receive(t, sub)
+		assert.Equal(t, events_domain.ProductRemoved, e.Type)
+		assert.Equal(t, pvzID, e.PVZID)
+		assert.Equal(t, pvz_domain.Kazan, e.City)
+		assert.Equal(t, product.ID, e.ProductID)
+		assert.Equal(t, product_domain.Shoes, e.ProductType)
+		assert.Equal(t, deletedAt, e.At)
+	})
+
+	t.Run("not published for other entities", func(t *testing.T) {
+		event := audit_domain.Event{Action: audit_domain.ActionPVZCreate, After: pvz}
+		m.auditor.EXPECT().Record(ctx, event)
+
+		publisher.Record(ctx, event)
+
+		assert.Empty(t, sub.Events())
+	})
+
+	t.Run("not published if lookup failed", func(t *testing.T) {
+		event := audit_domain.Event{Action: audit_domain.ActionProductAdd, After: product}
+		m.auditor.EXPECT().Record(ctx, event)
+		m.reception.EXPECT().FindByID(gomock.Any(), reception.ID).Return(nil, errors.New("db is down"))
+
+		publisher.Record(ctx, event)
+
+		assert.Empty(t, sub.Events())
+	})
+}
+
+func TestPublisher_AuditIsRecordedFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	publisher, _, m := newPublisher(ctrl)
+
+	pvzID := "pvz-1"
+	reception := &reception_domain.Reception{ID: "rec-1", PVZID: pvzID}
+	event := audit_domain.Event{Action: audit_domain.ActionReceptionClose, After: reception}
+
+	gomock.InOrder(
+		m.auditor.EXPECT().Record(gomock.Any(), event),
+		m.pvz.EXPECT().GetByID(gomock.Any(), pvzID).Return(&pvz_domain.PVZ{ID: &pvzID, City: pvz_domain.Moscow}, nil),
+	)
+
+	publisher.Record(context.Background(), event)
+	require.True(t, ctrl.Satisfied())
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+)
+
+type EventsService struct {
+	broker *Broker
+
+	log *logger.ZerologLogger
+}
+
+func NewEventsService(broker *Broker, l *logger.ZerologLogger) *EventsService {
+	return &EventsService{
+		broker: broker,
+		log:    l,
+	}
+}
+
+// Subscribe returns subscription to events of pvz or city.
+// Caller must close subscription when it is done.
+func (s *EventsService) Subscribe(ctx context.Context, params SubscribeParams) (_ *Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "EventsService.Subscribe")
+	defer func() { tracing.End(span, err) }()
+
+	// restricted api key subscribes to its pvz by default
+	if params.PVZID == "" {
+		params.PVZID = params.AllowedPVZID
+	}
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Subscribe")
+		return nil, err
+	}
+
+	sub, err := s.broker.Subscribe(events_domain.Filter{PVZID: params.PVZID, City: params.City}, params.Cursor)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error subscribing to events")
+		return nil, err
+	}
+
+	s.log.Info().Ctx(ctx).Any("params", params).Int("backfill", len(sub.Backfill)).Msg("Subscribe successful")
+	return sub, nil
+}
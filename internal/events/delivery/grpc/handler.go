@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	pb "github.com/0x0FACED/pvz-avito/internal/events/delivery/grpc/v1"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type EventsService interface {
+	Subscribe(ctx context.Context, params application.SubscribeParams) (*application.Subscription, error)
+}
+
+type GRPCHandler struct {
+	pb.UnimplementedEventsServiceServer
+	svc EventsService
+}
+
+func NewGRPCHandler(svc EventsService) *GRPCHandler {
+	return &GRPCHandler{
+		svc: svc,
+	}
+}
+
+func (h *GRPCHandler) Subscribe(req *pb.SubscribeRequest, stream grpc.ServerStreamingServer[pb.Event]) error {
+	ctx := stream.Context()
+
+	// claims are put by GRPCStreamAuth interceptor
+	claims, ok := httpcommon.ClaimsFromContext(ctx)
+	if !ok {
+		return apperr.GRPCError(ctx, apperr.ErrNoAuth)
+	}
+
+	sub, err := h.svc.Subscribe(ctx, application.SubscribeParams{
+		PVZID:        req.GetPvzId(),
+		City:         pvz_domain.City(req.GetCity()),
+		Cursor:       req.GetCursor(),
+		AllowedPVZID: claims.PVZID,
+	})
+	if err != nil {
+		return apperr.GRPCError(ctx, err)
+	}
+	defer sub.Close()
+
+	for _, e := range sub.Backfill {
+		if err := stream.Send(newEvent(e)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.Events():
+			// subscription is dropped, client resubscribes with cursor of last event
+			if !ok {
+				return apperr.GRPCError(ctx, apperr.ErrUnavailable)
+			}
+			if err := stream.Send(newEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+var eventTypes = map[events_domain.Type]pb.EventType{
+	events_domain.ReceptionOpened: pb.EventType_EVENT_TYPE_RECEPTION_OPENED,
+	events_domain.ReceptionClosed: pb.EventType_EVENT_TYPE_RECEPTION_CLOSED,
+	events_domain.ProductAdded:    pb.EventType_EVENT_TYPE_PRODUCT_ADDED,
+	events_domain.ProductRemoved:  pb.EventType_EVENT_TYPE_PRODUCT_REMOVED,
+	events_domain.ProductRestored: pb.EventType_EVENT_TYPE_PRODUCT_RESTORED,
+}
+
+func newEvent(e events_domain.Event) *pb.Event {
+	return &pb.Event{
+		Cursor:      e.Cursor,
+		Type:        eventTypes[e.Type],
+		At:          timestamppb.New(e.At),
+		PvzId:       e.PVZID,
+		City:        e.City.String(),
+		ReceptionId: e.ReceptionID,
+		ProductId:   e.ProductID,
+		ProductType: e.ProductType.String(),
+	}
+}
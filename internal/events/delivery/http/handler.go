@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+)
+
+type EventsService interface {
+	Subscribe(ctx context.Context, params application.SubscribeParams) (*application.Subscription, error)
+}
+
+type Handler struct {
+	svc EventsService
+	// heartbeat is interval of keepalive comments, so proxies do not close idle stream
+	heartbeat time.Duration
+}
+
+func NewHandler(svc EventsService, heartbeat time.Duration) *Handler {
+	return &Handler{
+		svc:       svc,
+		heartbeat: heartbeat,
+	}
+}
+
+func (h Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /events", h.Stream)
+}
+
+// Stream sends events as server-sent events. Event id is its cursor,
+// so EventSource resumes from Last-Event-ID after reconnect.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		cursor = lastID
+	}
+
+	params := application.SubscribeParams{
+		PVZID:        r.URL.Query().Get("pvzId"),
+		City:         pvz_domain.City(r.URL.Query().Get("city")),
+		Cursor:       cursor,
+		AllowedPVZID: claims.PVZID,
+	}
+
+	sub, err := h.svc.Subscribe(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	// stream lives longer than server write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range sub.Backfill {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events():
+			// subscription is dropped, client reconnects with Last-Event-ID
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e events_domain.Event) error {
+	data, err := json.Marshal(newEventResponse(e))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Cursor, e.Type, data)
+	return err
+}
@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_http "github.com/0x0FACED/pvz-avito/internal/events/delivery/http"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/events/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func withClaims(req *nethttp.Request, claims *httpcommon.Claims) *nethttp.Request {
+	return req.WithContext(context.WithValue(req.Context(), httpcommon.DefaultUserKey, claims))
+}
+
+func TestEventsHandler_Stream_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectError    string
+	}{
+		{"invalid params", events_domain.ErrInvalidIDFormat, nethttp.StatusBadRequest, "invalid id format"},
+		{"other pvz", events_domain.ErrAccessDenied, nethttp.StatusForbidden, "access denied"},
+		{"cursor expired", events_domain.ErrCursorExpired, nethttp.StatusGone, "cursor expired"},
+		{"shutting down", events_domain.ErrBrokerClosed, nethttp.StatusServiceUnavailable, "service unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockEventsService(ctrl)
+			svc.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			handler := events_http.NewHandler(svc, time.Minute)
+
+			req := withClaims(httptest.NewRequest(nethttp.MethodGet, "/events", nil), &httpcommon.Claims{Role: "moderator"})
+			rec := httptest.NewRecorder()
+
+			handler.Stream(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var errResp httpcommon.ErrorResponse
+			_ = json.NewDecoder(rec.Body).Decode(&errResp)
+			assert.Equal(t, tt.expectError, errResp.Error())
+		})
+	}
+
+	t.Run("missing claims in context", func(t *testing.T) {
+		handler := events_http.NewHandler(mocks.NewMockEventsService(ctrl), time.Minute)
+
+		rec := httptest.NewRecorder()
+		handler.Stream(rec, httptest.NewRequest(nethttp.MethodGet, "/events", nil))
+
+		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
+	})
+}
+
+func TestEventsHandler_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	broker := application.NewBroker(10, 10)
+	backfilled := events_domain.Event{Cursor: "epoch-1", Type: events_domain.ReceptionOpened, PVZID: "pvz-1", City: pvz_domain.Moscow, ReceptionID: "rec-1"}
+
+	svc := mocks.NewMockEventsService(ctrl)
+	svc.EXPECT().Subscribe(gomock.Any(), application.SubscribeParams{
+		PVZID:        "pvz-1",
+		City:         pvz_domain.Moscow,
+		Cursor:       "from-header",
+		AllowedPVZID: "pvz-1",
+	}).DoAndReturn(func(context.Context, application.SubscribeParams) (*application.Subscription, error) {
+		sub, err := broker.Subscribe(events_domain.Filter{}, "")
+		if err != nil {
+			return nil, err
+		}
+		sub.Backfill = []events_domain.Event{backfilled}
+		return sub, nil
+	})
+
+	handler := events_http.NewHandler(svc, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(nethttp.MethodGet, "/events?pvzId=pvz-1&city=Москва&cursor=from-query", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "from-header")
+	req = withClaims(req, &httpcommon.Claims{Role: "employee", PVZID: "pvz-1"})
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.Stream(rec, req)
+		close(done)
+	}()
+
+	// wait until handler subscribed, then publish live event
+	require.Eventually(t, ctrl.Satisfied, time.Second, 10*time.Millisecond)
+	broker.Publish(events_domain.Event{Type: events_domain.ProductAdded, PVZID: "pvz-1", City: pvz_domain.Moscow, ReceptionID: "rec-1", ProductID: "prod-1"})
+	broker.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.FailNow(t, "stream is not finished after broker close")
+	}
+	cancel()
+
+	assert.Equal(t, nethttp.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+	blocks := strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n")
+	require.Len(t, blocks, 2)
+
+	lines := strings.Split(blocks[0], "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "id: epoch-1", lines[0])
+	assert.Equal(t, "event: reception.opened", lines[1])
+
+	var resp events_http.EventResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &resp))
+	assert.Equal(t, "rec-1", resp.ReceptionID)
+	assert.Empty(t, resp.ProductID)
+
+	assert.Contains(t, blocks[1], "event: product.added")
+	assert.Contains(t, blocks[1], `"productId":"prod-1"`)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"time"
+
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+)
+
+type EventResponse struct {
+	Cursor      string    `json:"cursor"`
+	Type        string    `json:"type"`
+	At          time.Time `json:"at"`
+	PVZID       string    `json:"pvzId"`
+	City        string    `json:"city"`
+	ReceptionID string    `json:"receptionId"`
+	ProductID   string    `json:"productId,omitempty"`
+	ProductType string    `json:"productType,omitempty"`
+}
+
+func newEventResponse(e events_domain.Event) EventResponse {
+	return EventResponse{
+		Cursor:      e.Cursor,
+		Type:        e.Type.String(),
+		At:          e.At,
+		PVZID:       e.PVZID,
+		City:        e.City.String(),
+		ReceptionID: e.ReceptionID,
+		ProductID:   e.ProductID,
+		ProductType: e.ProductType.String(),
+	}
+}
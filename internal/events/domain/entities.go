@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+)
+
+type Type string
+
+const (
+	ReceptionOpened Type = "reception.opened"
+	ReceptionClosed Type = "reception.closed"
+	ProductAdded    Type = "product.added"
+	ProductRemoved  Type = "product.removed"
+	ProductRestored Type = "product.restored"
+)
+
+func (t Type) String() string {
+	return string(t)
+}
+
+// Event is change of reception or product in pvz.
+type Event struct {
+	// Seq grows by one with every published event.
+	Seq uint64
+	// Cursor identifies event for backfill, subscriber passes cursor
+	// of last received event to get events it missed.
+	Cursor      string
+	Type        Type
+	At          time.Time
+	PVZID       string
+	City        pvz_domain.City
+	ReceptionID string
+
+	// product events only
+	ProductID   string
+	ProductType product_domain.ProductType
+}
+
+// Filter of subscription. Empty fields match any event.
+type Filter struct {
+	PVZID string
+	City  pvz_domain.City
+}
+
+func (f Filter) Match(e Event) bool {
+	if f.PVZID != "" && f.PVZID != e.PVZID {
+		return false
+	}
+
+	if f.City != "" && f.City != e.City {
+		return false
+	}
+
+	return true
+}
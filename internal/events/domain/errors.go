@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidCursor = errors.New("events: invalid cursor")
+	// ErrCursorExpired means events after cursor are no longer kept
+	// (or were published before restart), subscriber must reload state.
+	ErrCursorExpired = errors.New("events: cursor expired")
+	ErrBrokerClosed  = errors.New("events: broker is closed")
+)
+
+var (
+	ErrAccessDenied    = errors.New("events: access denied")
+	ErrInvalidIDFormat = errors.New("events: invalid id format")
+	ErrUnsupportedCity = errors.New("events: unsupported city")
+)
@@ -0,0 +1,228 @@
+// Package apperr is catalogue of errors returned to clients. Every error has
+// stable code, http status, gRPC code and localized messages, domain sentinel
+// errors are mapped to catalogue entries, so HTTP and gRPC report them the same way.
+package apperr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is domain of google.rpc.ErrorInfo sent with gRPC errors.
+const Domain = "pvz-avito"
+
+const (
+	LangEN = "en"
+	LangRU = "ru"
+
+	DefaultLanguage = LangEN
+)
+
+// languages are supported languages, every entry has message in each of them.
+var languages = []string{LangEN, LangRU}
+
+// Error is catalogue entry.
+type Error struct {
+	// Code is stable machine readable code, clients should rely on it, not on message.
+	Code       string
+	HTTPStatus int
+	GRPCCode   codes.Code
+	// Messages are keyed by language.
+	Messages map[string]string
+	// Detailed entries expose text of wrapped error as details,
+	// it must not contain internals (e.g. which password rule failed).
+	Detailed bool
+}
+
+func (e *Error) Error() string {
+	return e.Messages[DefaultLanguage]
+}
+
+// Message returns message in lang, or in DefaultLanguage if there is no translation.
+func (e *Error) Message(lang string) string {
+	if msg, ok := e.Messages[lang]; ok {
+		return msg
+	}
+	return e.Messages[DefaultLanguage]
+}
+
+type rule struct {
+	sentinel error
+	entry    *Error
+}
+
+var (
+	rules  []rule
+	byCode = make(map[string]*Error)
+)
+
+// register adds entry to catalogue, errors matching any of sentinels are reported as entry.
+func register(e *Error, sentinels ...error) *Error {
+	for _, lang := range languages {
+		if _, ok := e.Messages[lang]; !ok {
+			panic("apperr: no " + lang + " message for " + e.Code)
+		}
+	}
+	if _, ok := byCode[e.Code]; ok {
+		panic("apperr: duplicate code " + e.Code)
+	}
+
+	byCode[e.Code] = e
+	for _, s := range sentinels {
+		rules = append(rules, rule{sentinel: s, entry: e})
+	}
+
+	return e
+}
+
+// Lookup returns catalogue entry of err and details of it. Catalogue errors
+// wrapped into err are taken first, then domain sentinels, unknown errors are ErrInternal.
+// Details are empty if entry is not Detailed.
+func Lookup(err error) (*Error, string) {
+	var entry *Error
+	if errors.As(err, &entry) {
+		return entry, details(entry, err, entry)
+	}
+
+	for _, r := range rules {
+		if errors.Is(err, r.sentinel) {
+			return r.entry, details(r.entry, err, r.sentinel)
+		}
+	}
+
+	return ErrInternal, ""
+}
+
+// ByCode returns catalogue entry with code.
+func ByCode(code string) (*Error, bool) {
+	e, ok := byCode[code]
+	return e, ok
+}
+
+// details is text added to matched error by wrapping ("%w: text").
+func details(entry *Error, err, matched error) string {
+	if !entry.Detailed {
+		return ""
+	}
+
+	d, ok := strings.CutPrefix(err.Error(), matched.Error()+": ")
+	if !ok {
+		return ""
+	}
+
+	return d
+}
+
+// Language picks supported language from Accept-Language value,
+// quality values are ignored, first supported wins.
+func Language(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if slices.Contains(languages, base) {
+			return base
+		}
+	}
+
+	return DefaultLanguage
+}
+
+// WriteHTTP writes err as httpcommon.ErrorResponse with status of catalogue entry,
+// message is localized by Accept-Language of r.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	entry, d := Lookup(err)
+
+	httpcommon.JSONResponse(w, entry.HTTPStatus, httpcommon.ErrorResponse{
+		Code:      entry.Code,
+		Message:   entry.Message(Language(r.Header.Get("Accept-Language"))),
+		Details:   d,
+		RequestID: w.Header().Get(httpcommon.RequestIDHeader),
+	})
+}
+
+// GRPCError converts err to gRPC status of catalogue entry, message is localized
+// by "accept-language" metadata. Code and details are sent as google.rpc.ErrorInfo.
+func GRPCError(ctx context.Context, err error) error {
+	entry, d := Lookup(err)
+
+	lang := DefaultLanguage
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("accept-language"); len(values) > 0 {
+			lang = Language(values[0])
+		}
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: entry.Code,
+		Domain: Domain,
+	}
+	if d != "" {
+		info.Metadata = map[string]string{"details": d}
+	}
+
+	st, detailsErr := status.New(entry.GRPCCode, entry.Message(lang)).WithDetails(info)
+	if detailsErr != nil {
+		return status.Error(entry.GRPCCode, entry.Message(lang))
+	}
+
+	return st.Err()
+}
+
+// FromGRPC restores catalogue error from gRPC error made by GRPCError,
+// statuses without ErrorInfo are mapped by gRPC code.
+func FromGRPC(err error) error {
+	st := status.Convert(err)
+
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+		entry, ok := byCode[info.GetReason()]
+		if !ok {
+			break
+		}
+		if d := info.GetMetadata()["details"]; d != "" {
+			return &detailedError{entry: entry, details: d}
+		}
+		return entry
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return ErrInvalidRequest
+	case codes.Unauthenticated:
+		return ErrInvalidToken
+	case codes.PermissionDenied:
+		return ErrAccessDenied
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.Unavailable:
+		return ErrUnavailable
+	default:
+		return ErrInternal
+	}
+}
+
+// detailedError keeps details of catalogue error received over gRPC.
+type detailedError struct {
+	entry   *Error
+	details string
+}
+
+func (e *detailedError) Error() string {
+	return e.entry.Error() + ": " + e.details
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.entry
+}
@@ -0,0 +1,185 @@
+package apperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectCode    string
+		expectDetails string
+	}{
+		{
+			name:       "domain sentinel",
+			err:        reception_domain.ErrNoOpenReception,
+			expectCode: "no_open_reception",
+		},
+		{
+			name:       "wrapped domain sentinel",
+			err:        fmt.Errorf("%w: %w", pvz_domain.ErrAccessDenied, errors.New("role employee")),
+			expectCode: "access_denied",
+		},
+		{
+			name:       "sentinels of different features share entry",
+			err:        product_domain.ErrReceptionNotFound,
+			expectCode: "no_open_reception",
+		},
+		{
+			name:          "details of detailed entry",
+			err:           fmt.Errorf("%w: must contain digit", auth_domain.ErrWeakPassword),
+			expectCode:    "weak_password",
+			expectDetails: "must contain digit",
+		},
+		{
+			name:       "no details of plain entry",
+			err:        fmt.Errorf("%w: duplicate key", auth_domain.ErrUserAlreadyExists),
+			expectCode: "user_already_exists",
+		},
+		{
+			name:          "catalogue error",
+			err:           fmt.Errorf("%w: invalid page", apperr.ErrInvalidRequest),
+			expectCode:    "invalid_request",
+			expectDetails: "invalid page",
+		},
+		{
+			name:       "database error is internal",
+			err:        fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, errors.New("connection refused")),
+			expectCode: "internal",
+		},
+		{
+			name:       "unknown error is internal",
+			err:        errors.New("boom"),
+			expectCode: "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, details := apperr.Lookup(tt.err)
+
+			assert.Equal(t, tt.expectCode, entry.Code)
+			assert.Equal(t, tt.expectDetails, details)
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		expect string
+	}{
+		{"", apperr.LangEN},
+		{"ru", apperr.LangRU},
+		{"ru-RU,ru;q=0.9,en;q=0.8", apperr.LangRU},
+		{"de-DE, en-US;q=0.7", apperr.LangEN},
+		{"fr", apperr.LangEN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expect, apperr.Language(tt.header))
+		})
+	}
+}
+
+func TestWriteHTTP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/register", nil)
+	req.Header.Set("Accept-Language", "ru-RU")
+	rec := httptest.NewRecorder()
+	rec.Header().Set(httpcommon.RequestIDHeader, "req-1")
+
+	apperr.WriteHTTP(rec, req, fmt.Errorf("%w: must contain digit", auth_domain.ErrWeakPassword))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp httpcommon.ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, httpcommon.ErrorResponse{
+		Code:      "weak_password",
+		Message:   "пароль не соответствует политике",
+		Details:   "must contain digit",
+		RequestID: "req-1",
+	}, resp)
+}
+
+func TestGRPCError(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "ru"))
+
+	err := apperr.GRPCError(ctx, fmt.Errorf("%w: invalid limit", apperr.ErrInvalidRequest))
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "некорректный запрос", st.Message())
+	require.Len(t, st.Details(), 1)
+
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "invalid_request", info.GetReason())
+	assert.Equal(t, apperr.Domain, info.GetDomain())
+	assert.Equal(t, "invalid limit", info.GetMetadata()["details"])
+}
+
+func TestFromGRPC(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectCode    string
+		expectDetails string
+	}{
+		{
+			name:       "error info",
+			err:        apperr.GRPCError(context.Background(), product_domain.ErrProductNotDeleted),
+			expectCode: "product_not_deleted",
+		},
+		{
+			name:          "error info with details",
+			err:           apperr.GRPCError(context.Background(), fmt.Errorf("%w: must contain digit", auth_domain.ErrWeakPassword)),
+			expectCode:    "weak_password",
+			expectDetails: "must contain digit",
+		},
+		{
+			name:       "plain status",
+			err:        status.Error(codes.InvalidArgument, "bad path"),
+			expectCode: "invalid_request",
+		},
+		{
+			name:       "not status",
+			err:        errors.New("boom"),
+			expectCode: "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, details := apperr.Lookup(apperr.FromGRPC(tt.err))
+
+			assert.Equal(t, tt.expectCode, entry.Code)
+			assert.Equal(t, tt.expectDetails, details)
+
+			byCode, ok := apperr.ByCode(tt.expectCode)
+			require.True(t, ok)
+			assert.Same(t, byCode, entry)
+		})
+	}
+}
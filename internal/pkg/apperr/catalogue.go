@@ -0,0 +1,357 @@
+package apperr
+
+import (
+	"net/http"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"google.golang.org/grpc/codes"
+)
+
+// Codes are part of API, never change or reuse them.
+
+// Common errors.
+var (
+	ErrInternal = register(&Error{
+		Code: "internal", HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal,
+		Messages: map[string]string{LangEN: "internal error", LangRU: "внутренняя ошибка"},
+	},
+		apikey_domain.ErrInternalDatabase,
+		audit_domain.ErrInternalDatabase,
+		auth_domain.ErrInternalDatabase,
+		product_domain.ErrInternalDatabase,
+		pvz_domain.ErrInternalDatabase,
+		reception_domain.ErrInternalDatabase,
+		sync_domain.ErrInternalDatabase,
+	)
+	ErrUnavailable = register(&Error{
+		Code: "unavailable", HTTPStatus: http.StatusServiceUnavailable, GRPCCode: codes.Unavailable,
+		Messages: map[string]string{LangEN: "service unavailable", LangRU: "сервис недоступен"},
+	},
+		events_domain.ErrBrokerClosed,
+	)
+	ErrNotFound = register(&Error{
+		Code: "not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "not found", LangRU: "не найдено"},
+	})
+	// ErrInvalidBody is request body which is not valid json.
+	ErrInvalidBody = register(&Error{
+		Code: "invalid_body", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid request body", LangRU: "некорректное тело запроса"},
+	})
+	// ErrInvalidRequest is malformed parameter, handlers wrap it with name of parameter.
+	ErrInvalidRequest = register(&Error{
+		Code: "invalid_request", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid request", LangRU: "некорректный запрос"},
+		Detailed: true,
+	})
+	ErrInvalidID = register(&Error{
+		Code: "invalid_id", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid id format", LangRU: "некорректный формат идентификатора"},
+	},
+		apikey_domain.ErrInvalidIDFormat,
+		events_domain.ErrInvalidIDFormat,
+		product_domain.ErrInvalidIDFormat,
+		pvz_domain.ErrInvalidIDFormat,
+		reception_domain.ErrInvalidIDFormat,
+		sync_domain.ErrInvalidIDFormat,
+	)
+	ErrRateLimited = register(&Error{
+		Code: "rate_limited", HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted,
+		Messages: map[string]string{LangEN: "rate limit exceeded", LangRU: "превышен лимит запросов"},
+	})
+)
+
+// Authentication and authorization errors.
+var (
+	ErrNoAuth = register(&Error{
+		Code: "no_auth", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "no auth", LangRU: "требуется авторизация"},
+	})
+	ErrNoBearer = register(&Error{
+		Code: "no_bearer", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "no bearer", LangRU: "ожидается токен Bearer"},
+	})
+	ErrInvalidToken = register(&Error{
+		Code: "invalid_token", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "invalid token", LangRU: "недействительный токен"},
+	})
+	ErrAPIKeysDisabled = register(&Error{
+		Code: "api_keys_disabled", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "api keys are not supported", LangRU: "api-ключи не поддерживаются"},
+	})
+	ErrInvalidAPIKey = register(&Error{
+		Code: "invalid_api_key", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "invalid api key", LangRU: "недействительный api-ключ"},
+	},
+		apikey_domain.ErrInvalidAPIKey,
+		apikey_domain.ErrAPIKeyRevoked,
+	)
+	ErrAPIKeyScopeDenied = register(&Error{
+		Code: "api_key_scope_denied", HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied,
+		Messages: map[string]string{LangEN: "api key scope denied", LangRU: "недостаточно прав api-ключа"},
+	})
+	ErrDummyReadOnly = register(&Error{
+		Code: "dummy_read_only", HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied,
+		Messages: map[string]string{LangEN: "dummy token is read only", LangRU: "тестовый токен только для чтения"},
+	})
+	ErrAccessDenied = register(&Error{
+		Code: "access_denied", HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied,
+		Messages: map[string]string{LangEN: "access denied", LangRU: "доступ запрещен"},
+	},
+		apikey_domain.ErrAccessDenied,
+		audit_domain.ErrAccessDenied,
+		auth_domain.ErrAccessDenied,
+		auth_domain.ErrSSONoRole,
+		auth_domain.ErrSSOEmailNotVerified,
+		events_domain.ErrAccessDenied,
+		product_domain.ErrAccessDenied,
+		pvz_domain.ErrAccessDenied,
+		reception_domain.ErrAccessDenied,
+		sync_domain.ErrAccessDenied,
+	)
+)
+
+// Users and login errors.
+var (
+	ErrDummyLoginDisabled = register(&Error{
+		Code: "dummy_login_disabled", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "dummy login is disabled", LangRU: "тестовый вход отключен"},
+	})
+	// ErrInvalidCredentials does not tell whether login or password is wrong.
+	ErrInvalidCredentials = register(&Error{
+		Code: "invalid_credentials", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid login or password", LangRU: "неверный логин или пароль"},
+	},
+		auth_domain.ErrHashPassword,
+		auth_domain.ErrInvalidPassword,
+	)
+	ErrTooManyAttempts = register(&Error{
+		Code: "too_many_attempts", HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted,
+		Messages: map[string]string{LangEN: "too many login attempts", LangRU: "слишком много попыток входа"},
+	},
+		auth_domain.ErrTooManyAttempts,
+	)
+	ErrUserAlreadyExists = register(&Error{
+		Code: "user_already_exists", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.AlreadyExists,
+		Messages: map[string]string{LangEN: "user already exists", LangRU: "пользователь уже существует"},
+	},
+		auth_domain.ErrUserAlreadyExists,
+	)
+	ErrUserNotFound = register(&Error{
+		Code: "user_not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "user not found", LangRU: "пользователь не найден"},
+	},
+		auth_domain.ErrUserNotFound,
+	)
+	// ErrWeakPassword details tell which policy rule failed.
+	ErrWeakPassword = register(&Error{
+		Code: "weak_password", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "password does not satisfy policy", LangRU: "пароль не соответствует политике"},
+		Detailed: true,
+	},
+		auth_domain.ErrWeakPassword,
+	)
+	ErrInvalidEmail = register(&Error{
+		Code: "invalid_email", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid email", LangRU: "некорректный email"},
+	},
+		auth_domain.ErrInvalidEmail,
+	)
+	ErrInvalidRole = register(&Error{
+		Code: "invalid_role", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid role", LangRU: "некорректная роль"},
+	},
+		auth_domain.ErrInvalidRole,
+	)
+	// ErrSSOFailed is not Detailed, exchange errors may contain provider internals.
+	ErrSSOFailed = register(&Error{
+		Code: "sso_failed", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "sso login failed", LangRU: "ошибка входа через SSO"},
+	},
+		auth_domain.ErrSSOExchange,
+	)
+	// ErrSSOProvider details are error code returned by provider to callback.
+	ErrSSOProvider = register(&Error{
+		Code: "sso_provider_error", HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated,
+		Messages: map[string]string{LangEN: "sso login failed", LangRU: "ошибка входа через SSO"},
+		Detailed: true,
+	})
+	ErrSSOSessionExpired = register(&Error{
+		Code: "sso_session_expired", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "sso session expired", LangRU: "сессия SSO истекла"},
+	})
+	ErrSSOInvalidState = register(&Error{
+		Code: "sso_invalid_state", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid state", LangRU: "некорректный параметр state"},
+	})
+)
+
+// PVZ, reception and product errors.
+var (
+	ErrPVZAlreadyExists = register(&Error{
+		Code: "pvz_already_exists", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.AlreadyExists,
+		Messages: map[string]string{LangEN: "pvz already exists", LangRU: "ПВЗ уже существует"},
+	},
+		pvz_domain.ErrPVZAlreadyExists,
+	)
+	ErrPVZNotFound = register(&Error{
+		Code: "pvz_not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "pvz not found", LangRU: "ПВЗ не найден"},
+	},
+		pvz_domain.ErrPVZNotFound,
+		reception_domain.ErrPVZNotFound,
+	)
+	ErrUnsupportedCity = register(&Error{
+		Code: "unsupported_city", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "unsupported city", LangRU: "город не поддерживается"},
+	},
+		pvz_domain.ErrUnsupportedCity,
+		events_domain.ErrUnsupportedCity,
+	)
+	ErrInvalidPageSize = register(&Error{
+		Code: "invalid_page_size", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid page size", LangRU: "некорректный размер страницы"},
+	},
+		pvz_domain.ErrInvalidPageSize,
+	)
+	ErrReceptionNotFound = register(&Error{
+		Code: "reception_not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "reception not found", LangRU: "приемка не найдена"},
+	},
+		reception_domain.ErrReceptionNotFound,
+	)
+	ErrReceptionAlreadyOpen = register(&Error{
+		Code: "reception_already_open", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition,
+		Messages: map[string]string{LangEN: "reception already exists", LangRU: "открытая приемка уже существует"},
+	},
+		reception_domain.ErrFoundOpenedReception,
+	)
+	// ErrNoOpenReception includes product ErrReceptionNotFound, product repositories
+	// return it when pvz has no reception in progress.
+	ErrNoOpenReception = register(&Error{
+		Code: "no_open_reception", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition,
+		Messages: map[string]string{LangEN: "no open reception found", LangRU: "нет открытой приемки"},
+	},
+		reception_domain.ErrNoOpenReception,
+		product_domain.ErrReceptionNotFound,
+	)
+	ErrReceptionClosed = register(&Error{
+		Code: "reception_closed", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition,
+		Messages: map[string]string{LangEN: "reception already closed", LangRU: "приемка уже закрыта"},
+	},
+		product_domain.ErrReceptionClosed,
+	)
+	ErrProductAlreadyExists = register(&Error{
+		Code: "product_already_exists", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.AlreadyExists,
+		Messages: map[string]string{LangEN: "product already exists", LangRU: "товар уже существует"},
+	},
+		product_domain.ErrProductAlreadyExists,
+	)
+	ErrProductNotFound = register(&Error{
+		Code: "product_not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "product not found", LangRU: "товар не найден"},
+	},
+		product_domain.ErrProductNotFound,
+	)
+	ErrNoProductsToDelete = register(&Error{
+		Code: "no_products_to_delete", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition,
+		Messages: map[string]string{LangEN: "no products to delete", LangRU: "нет товаров для удаления"},
+	},
+		product_domain.ErrNoProductsToDelete,
+	)
+	ErrProductNotDeleted = register(&Error{
+		Code: "product_not_deleted", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition,
+		Messages: map[string]string{LangEN: "product is not deleted", LangRU: "товар не удален"},
+	},
+		product_domain.ErrProductNotDeleted,
+	)
+	ErrInvalidProductType = register(&Error{
+		Code: "invalid_product_type", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid product type", LangRU: "некорректный тип товара"},
+	},
+		product_domain.ErrInvalidProductType,
+	)
+)
+
+// API keys, audit, events and sync errors.
+var (
+	ErrAPIKeyNotFound = register(&Error{
+		Code: "api_key_not_found", HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound,
+		Messages: map[string]string{LangEN: "api key not found", LangRU: "api-ключ не найден"},
+	},
+		apikey_domain.ErrAPIKeyNotFound,
+	)
+	ErrInvalidScope = register(&Error{
+		Code: "invalid_scope", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid scope", LangRU: "некорректная область доступа"},
+		Detailed: true,
+	},
+		apikey_domain.ErrInvalidScope,
+	)
+	ErrInvalidName = register(&Error{
+		Code: "invalid_name", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid name", LangRU: "некорректное имя"},
+		Detailed: true,
+	},
+		apikey_domain.ErrInvalidName,
+	)
+	ErrInvalidAction = register(&Error{
+		Code: "invalid_action", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid action", LangRU: "некорректное действие"},
+		Detailed: true,
+	},
+		audit_domain.ErrInvalidAction,
+	)
+	ErrInvalidPaging = register(&Error{
+		Code: "invalid_paging", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid page or limit", LangRU: "некорректные page или limit"},
+	},
+		audit_domain.ErrInvalidPaging,
+	)
+	ErrInvalidCursor = register(&Error{
+		Code: "invalid_cursor", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid cursor", LangRU: "некорректный курсор"},
+	},
+		events_domain.ErrInvalidCursor,
+	)
+	// ErrCursorExpired means events after cursor are dropped, client has to resync.
+	ErrCursorExpired = register(&Error{
+		Code: "cursor_expired", HTTPStatus: http.StatusGone, GRPCCode: codes.OutOfRange,
+		Messages: map[string]string{LangEN: "cursor expired", LangRU: "курсор устарел"},
+	},
+		events_domain.ErrCursorExpired,
+	)
+	ErrEmptyJournal = register(&Error{
+		Code: "empty_journal", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "no operations to sync", LangRU: "нет операций для синхронизации"},
+	},
+		sync_domain.ErrEmptyJournal,
+	)
+	ErrJournalTooLarge = register(&Error{
+		Code: "journal_too_large", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "too many operations in one sync", LangRU: "слишком много операций в одной синхронизации"},
+		Detailed: true,
+	},
+		sync_domain.ErrJournalTooLarge,
+	)
+	ErrInvalidOperationType = register(&Error{
+		Code: "invalid_operation_type", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid operation type", LangRU: "некорректный тип операции"},
+		Detailed: true,
+	},
+		sync_domain.ErrInvalidOperationType,
+	)
+	ErrInvalidClientTime = register(&Error{
+		Code: "invalid_client_time", HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument,
+		Messages: map[string]string{LangEN: "invalid client time", LangRU: "некорректное время клиента"},
+	},
+		sync_domain.ErrInvalidClientTime,
+	)
+)
@@ -0,0 +1,18 @@
+// Package cache is key-value cache with pluggable storage:
+// in-process LRU or external cache shared between replicas.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores encoded values with ttl. External caches (redis, memcached)
+// implement it to share cached values and invalidations between replicas.
+type Cache interface {
+	// Get returns false if key is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value, zero ttl means no expiration.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
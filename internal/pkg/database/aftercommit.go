@@ -0,0 +1,56 @@
+package database
+
+import "context"
+
+type afterCommitKey struct{}
+
+// afterCommit collects callbacks registered in one transaction or savepoint.
+type afterCommit struct {
+	parent *afterCommit
+	fns    []func()
+}
+
+// AfterCommit runs fn after outermost transaction of ctx is committed.
+// fn is dropped if transaction or savepoint it is registered in is rolled back.
+// Without transaction in ctx fn runs immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommit); ok {
+		hooks.fns = append(hooks.fns, fn)
+		return
+	}
+
+	fn()
+}
+
+// committed passes callbacks of savepoint to its transaction,
+// callbacks of outermost transaction are run.
+func (h *afterCommit) committed() {
+	if h.parent != nil {
+		h.parent.fns = append(h.parent.fns, h.fns...)
+		return
+	}
+
+	for _, fn := range h.fns {
+		fn()
+	}
+}
+
+// RunInTx collects callbacks registered by fn and runs them after commit,
+// if fn or commit fails they are dropped. Every transactor calls it,
+// commit of nested call only releases savepoint.
+func RunInTx(ctx context.Context, fn func(ctx context.Context) error, commit func() error) error {
+	parent, _ := ctx.Value(afterCommitKey{}).(*afterCommit)
+	hooks := &afterCommit{parent: parent}
+
+	if err := fn(context.WithValue(ctx, afterCommitKey{}, hooks)); err != nil {
+		return err
+	}
+
+	if err := commit(); err != nil {
+		return err
+	}
+
+	hooks.committed()
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -47,5 +48,8 @@ func pgxpoolConfig(cfg config.DatabaseConfig) (*pgxpool.Config, error) {
 
 	config.ConnConfig.ConnectTimeout = cfg.ConnectionTimeout
 
+	// spans are noop if tracing is disabled
+	config.ConnConfig.Tracer = tracing.NewPgxTracer()
+
 	return config, nil
 }
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	migrate_pgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	migrate_sqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Migrator applies embedded migrations. It uses same schema_migrations table
+// as migrate CLI, so databases migrated by CLI are picked up as is.
+// Concurrent migrators (several replicas with auto migrate) are serialized by advisory lock.
+type Migrator struct {
+	m             *migrate.Migrate
+	latestVersion func() (uint, error)
+}
+
+// MigrationStatus is applied and expected schema versions.
+type MigrationStatus struct {
+	Version  uint
+	Dirty    bool
+	Expected uint
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	driver, err := migrate_pgx.WithInstance(stdlib.OpenDBFromPool(pool), &migrate_pgx.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return &Migrator{m: m, latestVersion: migrations.LatestVersion}, nil
+}
+
+// NewSQLiteMigrator applies sqlite migrations. It opens own connection
+// to database file, which is closed by Close.
+func NewSQLiteMigrator(ctx context.Context, cfg config.SQLiteConfig) (*Migrator, error) {
+	source, err := iofs.New(migrations.SQLiteFS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	db, err := OpenSQLite(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := migrate_sqlite.WithInstance(db, &migrate_sqlite.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return &Migrator{m: m, latestVersion: migrations.SQLiteLatestVersion}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate up: %w", err)
+	}
+
+	return nil
+}
+
+// Down rolls back steps migrations.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	if err := m.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate down: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	expected, err := m.latestVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return &MigrationStatus{
+		Version:  version,
+		Dirty:    dirty,
+		Expected: expected,
+	}, nil
+}
+
+// Close releases db connection, pool stays open.
+// Sqlite migrator closes its own connection.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNoSchemaVersion is returned if migrations were never applied.
+var ErrNoSchemaVersion = errors.New("no schema version")
+
+// SchemaVersion returns applied migration version from golang-migrate
+// schema_migrations table. dirty is true if last migration failed.
+func SchemaVersion(ctx context.Context, pool *pgxpool.Pool) (version uint, dirty bool, err error) {
+	query := `
+		SELECT version, dirty
+		FROM schema_migrations
+		LIMIT 1
+	`
+
+	var v int64
+	if err := pool.QueryRow(ctx, query).Scan(&v, &dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, ErrNoSchemaVersion
+		}
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return uint(v), dirty, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"modernc.org/sqlite"
+)
+
+// sqliteTimeLayout is fixed width, so timestamps stored as TEXT
+// are ordered and compared correctly as strings.
+// Driver parses it back into time.Time for TIMESTAMP columns.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000-07:00"
+
+// OpenSQLite opens sqlite database file with foreign keys enabled.
+// Only one connection is used: sqlite serializes writers anyway,
+// and transactions never fail with SQLITE_BUSY on lock upgrade.
+func OpenSQLite(ctx context.Context, cfg config.SQLiteConfig) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// SQLiteTime formats t for TIMESTAMP column.
+func SQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+// SQLiteNullTime formats t for nullable TIMESTAMP column.
+func SQLiteNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return SQLiteTime(*t)
+}
+
+// SQLiteErrorCode returns extended result code of sqlite error
+// (sqlite3.SQLITE_CONSTRAINT_UNIQUE etc.), 0 if err is not sqlite error.
+func SQLiteErrorCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+
+	return 0
+}
+
+// SQLiteSchemaVersion is SchemaVersion for sqlite database.
+func SQLiteSchemaVersion(ctx context.Context, db *sql.DB) (version uint, dirty bool, err error) {
+	query := `
+		SELECT version, dirty
+		FROM schema_migrations
+		LIMIT 1
+	`
+
+	var v int64
+	if err := db.QueryRowContext(ctx, query).Scan(&v, &dirty); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, ErrNoSchemaVersion
+		}
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return uint(v), dirty, nil
+}
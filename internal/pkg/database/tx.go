@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Transactor runs fn in one transaction, repositories called with ctx passed
+// to fn take part in it. Nested calls run in savepoint, so error of nested fn
+// rolls back only its changes. Transaction is committed if fn returns nil.
+// Side effects which must not be seen before commit are registered with AfterCommit.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type pgxTxKey struct{}
+
+// PgxQuerier is implemented by *pgxpool.Pool and pgx.Tx.
+type PgxQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Pgx returns transaction started by PgxTransactor for ctx, or pool if there is none.
+func Pgx(ctx context.Context, pool *pgxpool.Pool) PgxQuerier {
+	if tx, ok := ctx.Value(pgxTxKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pool
+}
+
+type PgxTransactor struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgxTransactor(pool *pgxpool.Pool) *PgxTransactor {
+	return &PgxTransactor{pool: pool}
+}
+
+func (t *PgxTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Begin of pgx.Tx makes savepoint
+	tx, err := Pgx(ctx, t.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	return RunInTx(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, pgxTxKey{}, tx))
+	}, func() error {
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+type sqliteTxKey struct{}
+
+// SQLiteQuerier is implemented by *sql.DB and *sql.Tx.
+type SQLiteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SQLite returns transaction started by SQLiteTransactor for ctx, or db if there is none.
+// Database has one connection (see OpenSQLite), so repositories called in
+// transaction must use it, otherwise they wait for the connection forever.
+func SQLite(ctx context.Context, db *sql.DB) SQLiteQuerier {
+	if tx, ok := ctx.Value(sqliteTxKey{}).(*sqliteTx); ok {
+		return tx.tx
+	}
+
+	return db
+}
+
+type sqliteTx struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+type SQLiteTransactor struct {
+	db *sql.DB
+}
+
+func NewSQLiteTransactor(db *sql.DB) *SQLiteTransactor {
+	return &SQLiteTransactor{db: db}
+}
+
+func (t *SQLiteTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(sqliteTxKey{}).(*sqliteTx); ok {
+		return tx.savepoint(ctx, fn)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	return RunInTx(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, sqliteTxKey{}, &sqliteTx{tx: tx}))
+	}, func() error {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+func (t *sqliteTx) savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.savepoints++
+	name := fmt.Sprintf("sp_%d", t.savepoints)
+
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	err := RunInTx(ctx, fn, func() error {
+		if _, err := t.tx.ExecContext(ctx, "RELEASE "+name); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, rbErr := t.tx.ExecContext(ctx, "ROLLBACK TO "+name); rbErr != nil {
+			return fmt.Errorf("failed to rollback to savepoint: %w", rbErr)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package gateway serves gRPC services over REST with grpc-gateway,
+// keeping json of REST API, errors are written by apperr.
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// httpCodeHeader carries http status of successful response from server to gateway,
+// it is not sent to REST clients.
+const httpCodeHeader = "x-http-code"
+
+// NewServeMux creates gateway mux. Messages are lowerCamelCase json with all
+// fields (empty lists are [], not omitted), google.protobuf.Empty is written
+// as empty body, unknown fields are ignored, gRPC errors are written as catalogue errors.
+func NewServeMux() *runtime.ServeMux {
+	m := &marshaler{
+		JSONPb: &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: false, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		},
+	}
+
+	return runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, m),
+		runtime.WithErrorHandler(handleError),
+		runtime.WithForwardResponseOption(forwardStatus),
+		// gRPC metadata is not exposed as http headers
+		runtime.WithOutgoingHeaderMatcher(func(string) (string, bool) { return "", false }),
+	)
+}
+
+// SetStatus sets http status of successful response on REST, 200 by default.
+// It is sent as header metadata when called over gRPC.
+func SetStatus(ctx context.Context, code int) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(httpCodeHeader, strconv.Itoa(code)))
+}
+
+// Mount registers routes of service on mux. Routes are read from google.api.http
+// rules of service methods, only path templates with plain variables are supported.
+func Mount(mux *http.ServeMux, gw http.Handler, sd protoreflect.ServiceDescriptor) {
+	for _, pattern := range Patterns(sd) {
+		mux.Handle(pattern, gw)
+	}
+}
+
+// Patterns returns http.ServeMux patterns ("POST /pvz/{pvz_id}/...") of service methods.
+func Patterns(sd protoreflect.ServiceDescriptor) []string {
+	var patterns []string
+
+	methods := sd.Methods()
+	for i := range methods.Len() {
+		rule, ok := proto.GetExtension(methods.Get(i).Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+
+		var method, path string
+		switch p := rule.GetPattern().(type) {
+		case *annotations.HttpRule_Get:
+			method, path = http.MethodGet, p.Get
+		case *annotations.HttpRule_Post:
+			method, path = http.MethodPost, p.Post
+		case *annotations.HttpRule_Put:
+			method, path = http.MethodPut, p.Put
+		case *annotations.HttpRule_Patch:
+			method, path = http.MethodPatch, p.Patch
+		case *annotations.HttpRule_Delete:
+			method, path = http.MethodDelete, p.Delete
+		default:
+			continue
+		}
+
+		patterns = append(patterns, method+" "+path)
+	}
+
+	return patterns
+}
+
+func handleError(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	// body decode error is wrapped into status by gateway, ErrorInfo is lost
+	if st := status.Convert(err); st.Code() == codes.InvalidArgument && st.Message() == apperr.ErrInvalidBody.Error() {
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
+		return
+	}
+
+	apperr.WriteHTTP(w, r, apperr.FromGRPC(err))
+}
+
+func forwardStatus(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if _, ok := resp.(*emptypb.Empty); ok {
+		w.Header().Del("Content-Type")
+	}
+
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	values := md.HeaderMD.Get(httpCodeHeader)
+	if len(values) == 0 {
+		return nil
+	}
+
+	code, err := strconv.Atoi(values[0])
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(code)
+
+	return nil
+}
+
+// marshaler reports any body decode error (empty body too) as apperr.ErrInvalidBody,
+// so clients do not see protojson internals. Empty responses have no body.
+type marshaler struct {
+	*runtime.JSONPb
+}
+
+func (m *marshaler) Marshal(v any) ([]byte, error) {
+	if _, ok := v.(*emptypb.Empty); ok {
+		return nil, nil
+	}
+
+	return m.JSONPb.Marshal(v)
+}
+
+func (m *marshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	dec := m.JSONPb.NewDecoder(r)
+	return runtime.DecoderFunc(func(v any) error {
+		if err := dec.Decode(v); err != nil {
+			return apperr.ErrInvalidBody
+		}
+		return nil
+	})
+}
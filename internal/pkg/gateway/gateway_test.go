@@ -0,0 +1,25 @@
+package gateway_test
+
+import (
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/gateway"
+	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatterns(t *testing.T) {
+	services := pb.File_api_proto_pvz_v1_pvz_proto.Services()
+
+	assert.Equal(t, []string{
+		"POST /pvz",
+		"GET /pvz",
+		"POST /pvz/{pvz_id}/close_last_reception",
+		"POST /pvz/{pvz_id}/delete_last_product",
+	}, gateway.Patterns(services.ByName("PVZService")))
+
+	assert.Equal(t, []string{
+		"POST /products",
+		"POST /products/{product_id}/restore",
+	}, gateway.Patterns(services.ByName("ProductService")))
+}
@@ -0,0 +1,205 @@
+// Package health serves liveness and readiness probes over http
+// and grpc.health.v1 on grpc server.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ErrShuttingDown is readiness error after shutdown started.
+var ErrShuttingDown = errors.New("shutting down")
+
+// CheckFunc returns nil if dependency is ok.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Health runs readiness checks. Liveness only means process serves http.
+type Health struct {
+	checks   []check
+	timeout  time.Duration
+	interval time.Duration
+
+	shuttingDown atomic.Bool
+
+	// grpc health server, its status is updated by Run
+	grpc *health.Server
+
+	log *logger.ZerologLogger
+}
+
+// New creates Health, every check is run with timeout,
+// grpc status is refreshed every interval.
+func New(timeout, interval time.Duration, l *logger.ZerologLogger) *Health {
+	return &Health{
+		timeout:  timeout,
+		interval: interval,
+		grpc:     health.NewServer(),
+		log:      l,
+	}
+}
+
+// AddCheck adds named readiness check.
+func (h *Health) AddCheck(name string, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, fn: fn})
+}
+
+// Shutdown makes app not ready, so load balancer stops sending new requests
+// while in-flight ones drain.
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+	h.grpc.Shutdown()
+}
+
+// Check runs all checks concurrently and returns errors by check name.
+func (h *Health) Check(ctx context.Context) map[string]error {
+	results := make(map[string]error, len(h.checks)+1)
+	if h.shuttingDown.Load() {
+		results["shutdown"] = ErrShuttingDown
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			err := c.fn(ctx)
+
+			mu.Lock()
+			results[c.name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func ready(results map[string]error) bool {
+	for _, err := range results {
+		if err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GRPC returns grpc.health.v1 server to register on grpc server.
+func (h *Health) GRPC() healthpb.HealthServer {
+	return h.grpc
+}
+
+// Run updates grpc health status every interval, so grpc probes do not
+// run checks on every call. It implements app.Worker.
+func (h *Health) Run(ctx context.Context) error {
+	h.updateGRPC(ctx)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			h.updateGRPC(ctx)
+		}
+	}
+}
+
+func (h *Health) updateGRPC(ctx context.Context) {
+	if h.shuttingDown.Load() {
+		return
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if results := h.Check(ctx); !ready(results) {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+		h.log.Warn().Any("checks", errorStrings(results)).Msg("App is not ready")
+	}
+
+	// empty service name is overall server health
+	h.grpc.SetServingStatus("", status)
+}
+
+func (h *Health) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.Liveness)
+	mux.HandleFunc("GET /readyz", h.Readiness)
+}
+
+func (h *Health) Liveness(w http.ResponseWriter, _ *http.Request) {
+	httpcommon.JSONResponse(w, http.StatusOK, Response{Status: "ok"})
+}
+
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	results := h.Check(r.Context())
+
+	resp := Response{Status: "ok", Checks: errorStrings(results)}
+	status := http.StatusOK
+	if !ready(results) {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+
+	httpcommon.JSONResponse(w, status, resp)
+}
+
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func errorStrings(results map[string]error) map[string]string {
+	checks := make(map[string]string, len(results))
+	for name, err := range results {
+		checks[name] = "ok"
+		if err != nil {
+			checks[name] = err.Error()
+		}
+	}
+
+	return checks
+}
+
+// MigrationCheck fails if applied schema version is older than expected or dirty.
+// Newer version is ok, it is applied by newer replica during rolling update.
+func MigrationCheck(expected uint, version func(ctx context.Context) (uint, bool, error)) CheckFunc {
+	return func(ctx context.Context) error {
+		v, dirty, err := version(ctx)
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("schema version %d is dirty", v)
+		}
+
+		if v < expected {
+			return fmt.Errorf("schema version %d, expected %d", v, expected)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/health"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func ok(context.Context) error { return nil }
+
+func TestHealth_Readiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]health.CheckFunc
+		shutdown       bool
+		expectedStatus int
+		expectChecks   map[string]string
+	}{
+		{
+			name:           "ready",
+			checks:         map[string]health.CheckFunc{"database": ok},
+			expectedStatus: nethttp.StatusOK,
+			expectChecks:   map[string]string{"database": "ok"},
+		},
+		{
+			name: "database down",
+			checks: map[string]health.CheckFunc{
+				"database":   func(context.Context) error { return errors.New("connection refused") },
+				"migrations": ok,
+			},
+			expectedStatus: nethttp.StatusServiceUnavailable,
+			expectChecks:   map[string]string{"database": "connection refused", "migrations": "ok"},
+		},
+		{
+			name: "check timeout",
+			checks: map[string]health.CheckFunc{
+				"database": func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			expectedStatus: nethttp.StatusServiceUnavailable,
+			expectChecks:   map[string]string{"database": context.DeadlineExceeded.Error()},
+		},
+		{
+			name:           "shutting down",
+			checks:         map[string]health.CheckFunc{"database": ok},
+			shutdown:       true,
+			expectedStatus: nethttp.StatusServiceUnavailable,
+			expectChecks:   map[string]string{"database": "ok", "shutdown": health.ErrShuttingDown.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := health.New(50*time.Millisecond, time.Second, logger.NewTestLogger())
+			for name, fn := range tt.checks {
+				h.AddCheck(name, fn)
+			}
+			if tt.shutdown {
+				h.Shutdown()
+			}
+
+			mux := nethttp.NewServeMux()
+			h.RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var resp health.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.expectChecks, resp.Checks)
+
+			// liveness does not depend on checks
+			rec = httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/healthz", nil))
+			assert.Equal(t, nethttp.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestHealth_GRPC(t *testing.T) {
+	var dbErr error
+	h := health.New(time.Second, 10*time.Millisecond, logger.NewTestLogger())
+	h.AddCheck("database", func(context.Context) error { return dbErr })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := h.GRPC().Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		return resp.Status
+	}
+
+	dbErr = errors.New("connection refused")
+	go func() { _ = h.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return status() == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 5*time.Millisecond)
+
+	h.Shutdown()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status())
+}
+
+func TestMigrationCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   uint
+		dirty     bool
+		err       error
+		expectErr bool
+	}{
+		{"expected version", 8, false, nil, false},
+		{"newer version", 9, false, nil, false},
+		{"older version", 7, false, nil, true},
+		{"dirty", 8, true, nil, true},
+		{"no version", 0, false, errors.New("no schema version"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := health.MigrationCheck(8, func(context.Context) (uint, bool, error) {
+				return tt.version, tt.dirty, tt.err
+			})
+
+			err := check(context.Background())
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,23 +1,22 @@
 package httpcommon
 
-import (
-	"encoding/json"
-	"net/http"
-)
+// RequestIDHeader carries request id, it is set on response by request info middleware.
+const RequestIDHeader = "X-Request-ID"
 
+// ErrorResponse is body of error responses, it is written by apperr.WriteHTTP.
 type ErrorResponse struct {
+	// Code is stable code of error from apperr catalogue.
+	Code string `json:"code,omitempty"`
+	// Message is localized by Accept-Language.
 	Message string `json:"message"`
+	// Details are given for some errors, e.g. which password rule failed.
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func (e ErrorResponse) Error() string {
-	return e.Message
-}
-
-func JSONError(w http.ResponseWriter, statusCode int, err error) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	resp := ErrorResponse{
-		Message: err.Error(),
+	if e.Details != "" {
+		return e.Message + ": " + e.Details
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	return e.Message
 }
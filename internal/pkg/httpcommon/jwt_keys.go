@@ -0,0 +1,225 @@
+package httpcommon
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is asymmetric jwt key. Private is nil for verify only keys.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeySet holds active signing key and keys which are still accepted
+// on verify (previous keys during rotation).
+type KeySet struct {
+	signing *SigningKey
+	keys    map[string]*SigningKey
+	order   []string
+}
+
+// NewKeySet creates key set, signing key must have private part.
+func NewKeySet(signing *SigningKey, verify ...*SigningKey) (*KeySet, error) {
+	if signing == nil || signing.Private == nil {
+		return nil, errors.New("jwt: signing key must contain private key")
+	}
+
+	ks := &KeySet{
+		signing: signing,
+		keys:    make(map[string]*SigningKey, len(verify)+1),
+	}
+
+	for _, k := range append([]*SigningKey{signing}, verify...) {
+		if _, ok := ks.keys[k.ID]; ok {
+			continue
+		}
+		ks.keys[k.ID] = k
+		ks.order = append(ks.order, k.ID)
+	}
+
+	return ks, nil
+}
+
+// LoadKeySet reads PEM keys from files. First key signs tokens, others only verify.
+func LoadKeySet(signingPath string, verifyPaths ...string) (*KeySet, error) {
+	signing, err := LoadSigningKey(signingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	verify := make([]*SigningKey, 0, len(verifyPaths))
+	for _, path := range verifyPaths {
+		key, err := LoadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verify = append(verify, key)
+	}
+
+	return NewKeySet(signing, verify...)
+}
+
+// LoadSigningKey reads RSA or Ed25519 key from PEM file.
+// Both private (PKCS#8, PKCS#1) and public (PKIX) keys are supported.
+func LoadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read key %s: %w", path, err)
+	}
+
+	key, err := ParseSigningKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: parse key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		private crypto.Signer
+		public  crypto.PublicKey
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		private, public = signer, signer.Public()
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		private, public = key, key.Public()
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		public = key
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	return NewSigningKey(private, public)
+}
+
+// NewSigningKey detects signing method by key type and sets kid to
+// RFC 7638 thumbprint of public key, so kid does not depend on file names.
+func NewSigningKey(private crypto.Signer, public crypto.PublicKey) (*SigningKey, error) {
+	key := &SigningKey{
+		Private: private,
+		Public:  public,
+	}
+
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", public)
+	}
+
+	key.ID = thumbprint(key.JWK())
+
+	return key, nil
+}
+
+// JWK is public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// thumbprint is RFC 7638 JWK thumbprint: sha256 of required members in lexicographic order.
+func thumbprint(jwk JWK) string {
+	var canonical string
+	switch jwk.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKS returns public keys of all keys in set, signing key first.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, id := range ks.order {
+		set.Keys = append(set.Keys, ks.keys[id].JWK())
+	}
+
+	return set
+}
+
+func (ks *KeySet) Signing() *SigningKey {
+	return ks.signing
+}
+
+func (ks *KeySet) Key(kid string) (*SigningKey, bool) {
+	k, ok := ks.keys[kid]
+	return k, ok
+}
@@ -1,6 +1,8 @@
 package httpcommon
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
@@ -16,8 +18,11 @@ func (u User) String() string {
 	return string(u)
 }
 
+// JWTManager signs tokens with HS256 shared secret or,
+// if created with key set, with asymmetric key (RS256/EdDSA) and kid header.
 type JWTManager struct {
 	secretKey     string
+	keys          *KeySet
 	tokenDuration time.Duration
 }
 
@@ -28,13 +33,55 @@ func NewManager(secretKey string, duration time.Duration) *JWTManager {
 	}
 }
 
+func NewKeySetManager(keys *KeySet, duration time.Duration) *JWTManager {
+	return &JWTManager{
+		keys:          keys,
+		tokenDuration: duration,
+	}
+}
+
+// Audiences of issued tokens. Dummy tokens have own audience,
+// so other services which trust our JWKS can reject them.
+const (
+	AudienceAPI   = "pvz-api"
+	AudienceDummy = "pvz-dummy"
+)
+
 type Claims struct {
 	Email   string `json:"email"`
 	Role    string `json:"role"`
 	IsDummy bool   `json:"is_dummy,omitempty"`
+
+	// Set for service principal authenticated by api key, never in jwt.
+	APIKeyID string   `json:"-"`
+	Scopes   []string `json:"-"`
+	// PVZID restricts service principal to one pvz.
+	PVZID string `json:"-"`
+
 	jwt.RegisteredClaims
 }
 
+// Dummy reports if token was issued by dummy login.
+func (c *Claims) Dummy() bool {
+	if c.IsDummy {
+		return true
+	}
+
+	for _, aud := range c.Audience {
+		if aud == AudienceDummy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClaimsFromContext returns claims put into context by auth middleware.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(DefaultUserKey).(*Claims)
+	return claims, ok
+}
+
 func (m *JWTManager) Generate(email, role string) (string, error) {
 	claims := Claims{
 		Email:   email,
@@ -42,13 +89,13 @@ func (m *JWTManager) Generate(email, role string) (string, error) {
 		IsDummy: false,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   email,
+			Audience:  jwt.ClaimStrings{AudienceAPI},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	return m.sign(claims)
 }
 
 func (m *JWTManager) GenerateDummy(role string) (string, error) {
@@ -56,19 +103,62 @@ func (m *JWTManager) GenerateDummy(role string) (string, error) {
 		Role:    role,
 		IsDummy: true,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceDummy},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	return m.sign(claims)
 }
 
-func (m *JWTManager) Verify(accessToken string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &Claims{}, func(_ *jwt.Token) (any, error) {
+func (m *JWTManager) sign(claims Claims) (string, error) {
+	if m.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.secretKey))
+	}
+
+	key := m.keys.Signing()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+func (m *JWTManager) keyFunc(token *jwt.Token) (any, error) {
+	if m.keys == nil {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("%w: unexpected signing method %s", jwt.ErrTokenUnverifiable, token.Method.Alg())
+		}
 		return []byte(m.secretKey), nil
-	})
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kid %q", jwt.ErrTokenUnverifiable, kid)
+	}
+
+	// alg from header must match key, otherwise public key could be used as hmac secret
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("%w: unexpected signing method %s", jwt.ErrTokenUnverifiable, token.Method.Alg())
+	}
+
+	return key.Public, nil
+}
+
+// JWKS returns public keys for token verification by other services.
+// Key set is empty for HS256 manager, shared secret is never published.
+func (m *JWTManager) JWKS() JWKS {
+	if m.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+
+	return m.keys.JWKS()
+}
+
+func (m *JWTManager) Verify(accessToken string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &Claims{}, m.keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,154 @@
+package httpcommon_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRSAKey(t *testing.T) *httpcommon.SigningKey {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := httpcommon.NewSigningKey(priv, priv.Public())
+	require.NoError(t, err)
+
+	return key
+}
+
+func newEd25519Key(t *testing.T) *httpcommon.SigningKey {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key, err := httpcommon.NewSigningKey(priv, pub)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestJWTManager_KeySet(t *testing.T) {
+	tests := []struct {
+		name string
+		key  *httpcommon.SigningKey
+		alg  string
+	}{
+		{"rsa", newRSAKey(t), "RS256"},
+		{"ed25519", newEd25519Key(t), "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := httpcommon.NewKeySet(tt.key)
+			require.NoError(t, err)
+
+			m := httpcommon.NewKeySetManager(keys, time.Hour)
+
+			token, err := m.Generate("test@example.com", "employee")
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &httpcommon.Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, parsed.Method.Alg())
+			assert.Equal(t, tt.key.ID, parsed.Header["kid"])
+
+			claims, err := m.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "test@example.com", claims.Email)
+
+			jwks := m.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tt.key.ID, jwks.Keys[0].Kid)
+			assert.Equal(t, tt.alg, jwks.Keys[0].Alg)
+		})
+	}
+}
+
+func TestJWTManager_Rotation(t *testing.T) {
+	oldKey, newKey := newRSAKey(t), newEd25519Key(t)
+
+	oldKeys, err := httpcommon.NewKeySet(oldKey)
+	require.NoError(t, err)
+	oldToken, err := httpcommon.NewKeySetManager(oldKeys, time.Hour).Generate("test@example.com", "employee")
+	require.NoError(t, err)
+
+	// new key signs, old one is only verified
+	rotated, err := httpcommon.NewKeySet(newKey, oldKey)
+	require.NoError(t, err)
+	m := httpcommon.NewKeySetManager(rotated, time.Hour)
+
+	_, err = m.Verify(oldToken)
+	assert.NoError(t, err)
+	assert.Len(t, m.JWKS().Keys, 2)
+
+	// old key removed from set
+	onlyNew, err := httpcommon.NewKeySet(newKey)
+	require.NoError(t, err)
+	_, err = httpcommon.NewKeySetManager(onlyNew, time.Hour).Verify(oldToken)
+	assert.ErrorIs(t, err, jwt.ErrTokenUnverifiable)
+}
+
+func TestJWTManager_RejectsAlgConfusion(t *testing.T) {
+	key := newRSAKey(t)
+	keys, err := httpcommon.NewKeySet(key)
+	require.NoError(t, err)
+	m := httpcommon.NewKeySetManager(keys, time.Hour)
+
+	// hs256 token signed with public key as secret must not be accepted
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, httpcommon.Claims{Email: "evil@example.com", Role: "moderator"})
+	forged.Header["kid"] = key.ID
+	token, err := forged.SignedString(pubDER)
+	require.NoError(t, err)
+
+	_, err = m.Verify(token)
+	assert.Error(t, err)
+
+	// and hs256 manager does not accept asymmetric tokens
+	rsToken, err := m.Generate("test@example.com", "employee")
+	require.NoError(t, err)
+	_, err = httpcommon.NewManager("secret", time.Hour).Verify(rsToken)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privPath := filepath.Join(dir, "current.pem")
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(priv),
+	}), 0o600))
+
+	oldPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(oldPub)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "old.pub.pem")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600))
+
+	keys, err := httpcommon.LoadKeySet(privPath, pubPath)
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", keys.Signing().Method.Alg())
+	assert.Len(t, keys.JWKS().Keys, 2)
+
+	// public key can not sign
+	_, err = httpcommon.LoadKeySet(pubPath)
+	assert.Error(t, err)
+
+	_, err = httpcommon.LoadKeySet(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextHook adds request id and trace ids to events with request context (event.Ctx(ctx)),
+// so service logs can be linked to access log line and trace.
+type contextHook struct{}
+
+func (contextHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+
+	if id := requestinfo.RequestID(ctx); id != "" {
+		e.Str("request_id", id)
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		e.Str("trace_id", sc.TraceID().String()).Str("span_id", sc.SpanID().String())
+	}
+}
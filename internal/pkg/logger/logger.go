@@ -50,7 +50,8 @@ func NewZerologLogger(cfg config.LoggerConfig, opts ...ConsoleWriterOption) (*Ze
 		Level(level).
 		With().
 		Timestamp().
-		Logger()
+		Logger().
+		Hook(contextHook{})
 
 	return &ZerologLogger{
 		logger: logger,
@@ -58,12 +59,39 @@ func NewZerologLogger(cfg config.LoggerConfig, opts ...ConsoleWriterOption) (*Ze
 	}, nil
 }
 
+// NewConsoleLogger writes console logs only to w, without log file.
+// Used by cli tools, which print results to stdout and logs to stderr.
+func NewConsoleLogger(w io.Writer, level string) (*ZerologLogger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: w}).
+		Level(lvl).
+		With().
+		Timestamp().
+		Logger().
+		Hook(contextHook{})
+
+	return &ZerologLogger{
+		logger: logger,
+		cfg:    config.LoggerConfig{LogLevel: level},
+	}, nil
+}
+
 func NewTestLogger() *ZerologLogger {
-	logger := zerolog.New(io.Discard).
+	return NewTestLoggerWithOutput(io.Discard)
+}
+
+// NewTestLoggerWithOutput writes json logs to w, for tests which check log output.
+func NewTestLoggerWithOutput(w io.Writer) *ZerologLogger {
+	logger := zerolog.New(w).
 		Level(zerolog.DebugLevel).
 		With().
 		Timestamp().
-		Logger()
+		Logger().
+		Hook(contextHook{})
 
 	return &ZerologLogger{
 		logger: logger,
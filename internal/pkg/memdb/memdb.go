@@ -0,0 +1,133 @@
+// Package memdb is in-memory database for STORAGE_DRIVER=memory.
+// Tables are shared by memory repositories of all features, so they check
+// same invariants as postgres constraints (foreign keys, unique emails,
+// one open reception per pvz) and can join tables like postgres queries do.
+package memdb
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+)
+
+// DB keeps rows in insertion order. Tables must be accessed only under DB lock,
+// repositories hold it for whole operation, so every operation is atomic.
+// Rows are owned by DB, repositories return copies.
+type DB struct {
+	sync.RWMutex
+
+	// txMu is held by InTx, it is separate from table lock,
+	// because repositories lock tables inside transaction.
+	txMu sync.Mutex
+
+	Users      []*auth_domain.User
+	PVZs       []*pvz_domain.PVZ
+	Receptions []*reception_domain.Reception
+	Products   []*product_domain.Product
+	Audit      []*audit_domain.Entry
+	APIKeys    []*apikey_domain.APIKey
+
+	SyncJournal []*sync_domain.Result
+}
+
+func New() *DB {
+	return &DB{}
+}
+
+type txKey struct{}
+
+// InTx runs transactions one by one, nested calls run in transaction of ctx.
+// Changes made before error are not rolled back: every repository operation
+// is atomic by itself, so fn should check its conditions before changing rows.
+// Callbacks registered with database.AfterCommit are dropped on error as by other storages.
+func (db *DB) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(bool); ok {
+		return database.RunInTx(ctx, fn, commit)
+	}
+
+	db.txMu.Lock()
+	defer db.txMu.Unlock()
+
+	return database.RunInTx(context.WithValue(ctx, txKey{}, true), fn, commit)
+}
+
+// commit does nothing, changes are applied by repositories.
+func commit() error {
+	return nil
+}
+
+// PVZ returns pvz row by id or nil.
+func (db *DB) PVZ(id string) *pvz_domain.PVZ {
+	for _, p := range db.PVZs {
+		if p.ID != nil && *p.ID == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Reception returns reception row by id or nil.
+func (db *DB) Reception(id string) *reception_domain.Reception {
+	for _, r := range db.Receptions {
+		if r.ID == id {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// OpenReception returns latest in progress reception of pvz or nil.
+func (db *DB) OpenReception(pvzID string) *reception_domain.Reception {
+	var last *reception_domain.Reception
+	for _, r := range db.Receptions {
+		if r.PVZID == pvzID && r.Status == reception_domain.InProgress && (last == nil || !r.DateTime.Before(last.DateTime)) {
+			last = r
+		}
+	}
+
+	return last
+}
+
+// Product returns product row by id or nil, including soft deleted.
+func (db *DB) Product(id string) *product_domain.Product {
+	for _, p := range db.Products {
+		if p.ID == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// AliveProducts returns not deleted products of reception, newest first.
+func (db *DB) AliveProducts(receptionID string) []*product_domain.Product {
+	var products []*product_domain.Product
+	for _, p := range db.Products {
+		if p.ReceptionID == receptionID && !p.IsDeleted() {
+			products = append(products, p)
+		}
+	}
+
+	SortNewestFirst(products, func(p *product_domain.Product) time.Time { return p.DateTime })
+
+	return products
+}
+
+// SortNewestFirst sorts rows by time descending, rows with same time keep insertion order.
+func SortNewestFirst[T any](rows []T, at func(T) time.Time) {
+	slices.SortStableFunc(rows, func(a, b T) int {
+		return at(b).Compare(at(a))
+	})
+}
@@ -18,6 +18,31 @@ var (
 		Buckets: []float64{0.1, 0.5, 1, 2, 5},
 	}, []string{"method", "path"})
 
+	RateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_rate_limited_total",
+		Help: "Total number of HTTP requests rejected by rate limiter, path is rate limit route or unmatched",
+	}, []string{"method", "path"})
+
+	CacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Total number of cache lookups by result (hit, miss, error)",
+	}, []string{"cache", "result"})
+
+	EventSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "event_subscribers",
+		Help: "Number of active subscribers of reception and product events",
+	})
+
+	EventsPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "events_published_total",
+		Help: "Total number of published reception and product events by type",
+	}, []string{"type"})
+
+	EventSubscribersDroppedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "event_subscribers_dropped_total",
+		Help: "Total number of subscribers dropped because they did not keep up with events",
+	})
+
 	// ------business------
 	PvzCreatedTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "pvz_created_total",
@@ -33,4 +58,27 @@ var (
 		Name: "products_added_total",
 		Help: "Total number of addes products",
 	})
+
+	ReceptionAutoClosedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "reception_auto_closed_total",
+		Help: "Total number of receptions closed automatically after idle timeout",
+	})
+
+	SyncOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_operations_total",
+		Help: "Total number of offline operations uploaded by terminals by type and status",
+	}, []string{"type", "status"})
+
+	ReceptionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "reception_duration_seconds",
+		Help: "Time between opening and closing of reception",
+		// 5m, 15m, 30m, 1h, 2h, 4h, 8h, 12h, 24h
+		Buckets: []float64{300, 900, 1800, 3600, 7200, 14400, 28800, 43200, 86400},
+	}, []string{"city"})
+
+	ProductsPerReception = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "products_per_reception",
+		Help:    "Number of products in closed reception",
+		Buckets: []float64{0, 1, 5, 10, 25, 50, 100, 250, 500},
+	}, []string{"city"})
 )
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+)
+
+const redacted = "[REDACTED]"
+
+// AccessLogConfig configures access log written by Logger.
+type AccessLogConfig struct {
+	// LogBody enables logging of json and urlencoded form bodies.
+	LogBody bool
+	// MaxBodySize caps body bytes read for logging, bigger bodies are logged as truncated.
+	MaxBodySize int
+	// RedactFields are json keys (on any depth), form and query params
+	// which values are replaced in log, matched case insensitive.
+	RedactFields []string
+}
+
+// Logger writes one structured access log line per request.
+// It must be applied inside RequestInfo, so log line has request id and client ip.
+func (m *Middleware) Logger(cfg AccessLogConfig) func(http.Handler) http.Handler {
+	redact := make(map[string]struct{}, len(cfg.RedactFields))
+	for _, f := range cfg.RedactFields {
+		redact[strings.ToLower(strings.TrimSpace(f))] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			var (
+				body      any
+				truncated bool
+			)
+			if cfg.LogBody && r.Body != nil && r.Body != http.NoBody {
+				body, truncated = readBody(r, cfg.MaxBodySize, redact)
+			}
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			logEvent := m.log.Info()
+			if rw.status >= http.StatusInternalServerError {
+				logEvent = m.log.Error()
+			}
+
+			logEvent = logEvent.Ctx(r.Context()).
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Int("status", rw.status).
+				Int("bytes", rw.bytes).
+				Dur("duration_ms", time.Since(start)).
+				Str("client_ip", requestinfo.ClientIP(r.Context())).
+				Str("user_agent", r.UserAgent()).
+				Str("content_type", r.Header.Get("Content-Type"))
+
+			// actor is set by Auth under Logger, it is read from holder of RequestInfo
+			if actor, ok := requestinfo.ActorFrom(r.Context()); ok {
+				logEvent = logEvent.Str("actor_email", actor.Email).Str("actor_role", actor.Role)
+			}
+
+			if query := r.URL.Query(); len(query) > 0 {
+				logEvent = logEvent.Interface("query_params", redactValues(query, redact))
+			}
+
+			if truncated {
+				logEvent = logEvent.Bool("body_truncated", true)
+			} else if body != nil {
+				logEvent = logEvent.Interface("body", body)
+			}
+
+			logEvent.Msg("Request")
+		})
+	}
+}
+
+// readBody reads up to maxSize bytes of body for log and restores body for handlers.
+// Only json and urlencoded form bodies are parsed, others are not logged.
+func readBody(r *http.Request, maxSize int, redact map[string]struct{}) (any, bool) {
+	contentType := r.Header.Get("Content-Type")
+	isJSON := strings.Contains(contentType, "application/json")
+	isForm := strings.HasPrefix(contentType, "application/x-www-form-urlencoded")
+	if !isJSON && !isForm {
+		return nil, false
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(maxSize)+1))
+	// handler reads buffered part and then the rest of original body
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buf), r.Body), Closer: r.Body}
+	if err != nil {
+		return nil, false
+	}
+
+	if len(buf) > maxSize {
+		return nil, true
+	}
+
+	if isForm {
+		values, err := url.ParseQuery(string(buf))
+		if err != nil || len(values) == 0 {
+			return nil, false
+		}
+		return redactValues(values, redact), false
+	}
+
+	var jsonBody any
+	if err := json.Unmarshal(buf, &jsonBody); err != nil {
+		return nil, false
+	}
+
+	return redactJSON(jsonBody, redact), false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func redactJSON(v any, redact map[string]struct{}) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactJSON(value, redact)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactJSON(value, redact)
+		}
+	}
+
+	return v
+}
+
+func redactValues(values url.Values, redact map[string]struct{}) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, vals := range values {
+		switch _, ok := redact[strings.ToLower(key)]; {
+		case ok:
+			result[key] = redacted
+		case len(vals) == 1:
+			result[key] = vals[0]
+		default:
+			result[key] = vals
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,211 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_RequestInfo(t *testing.T) {
+	m := middleware.NewMiddlewareHandler(httpcommon.NewManager("test-secret", time.Hour), nil, true, false, logger.NewTestLogger())
+
+	tests := []struct {
+		name     string
+		incoming string
+		expectID func(t *testing.T, id string)
+	}{
+		{
+			name:     "generated",
+			incoming: "",
+			expectID: func(t *testing.T, id string) { assert.Len(t, id, 36) },
+		},
+		{
+			name:     "accepted from client",
+			incoming: "req-123",
+			expectID: func(t *testing.T, id string) { assert.Equal(t, "req-123", id) },
+		},
+		{
+			name:     "invalid replaced",
+			incoming: "bad id\ninjected",
+			expectID: func(t *testing.T, id string) { assert.Len(t, id, 36) },
+		},
+		{
+			name:     "too long replaced",
+			incoming: strings.Repeat("a", 129),
+			expectID: func(t *testing.T, id string) { assert.Len(t, id, 36) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				apperr.WriteHTTP(w, r, apperr.ErrInvalidRequest)
+			})
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/pvz", nil)
+			if tt.incoming != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+
+			m.RequestInfo(next).ServeHTTP(rec, req)
+
+			id := rec.Header().Get(middleware.RequestIDHeader)
+			tt.expectID(t, id)
+
+			var errResp httpcommon.ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+			assert.Equal(t, id, errResp.RequestID)
+		})
+	}
+}
+
+func TestMiddleware_Logger(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		path        string
+		body        string
+		maxBodySize int
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "json body is redacted",
+			contentType: "application/json",
+			path:        "/login",
+			body:        `{"email":"user@example.com","password":"s3cret-pass","nested":{"Token":"tok-value"}}`,
+			maxBodySize: 1024,
+			contains:    []string{`"email":"user@example.com"`, `"password":"[REDACTED]"`, `"Token":"[REDACTED]"`, `"request_id":"req-1"`, `"status":200`},
+			notContains: []string{"s3cret-pass", "tok-value"},
+		},
+		{
+			name:        "query and form are redacted",
+			contentType: "application/x-www-form-urlencoded",
+			path:        "/auth/oidc/callback?code=auth-code&page=2",
+			body:        "password=s3cret-pass&email=user@example.com",
+			maxBodySize: 1024,
+			contains:    []string{`"code":"[REDACTED]"`, `"page":"2"`, `"password":"[REDACTED]"`},
+			notContains: []string{"s3cret-pass", "auth-code"},
+		},
+		{
+			name:        "big body is not logged",
+			contentType: "application/json",
+			path:        "/register",
+			body:        `{"password":"s3cret-pass","padding":"` + strings.Repeat("x", 100) + `"}`,
+			maxBodySize: 32,
+			contains:    []string{`"body_truncated":true`},
+			notContains: []string{"s3cret-pass"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			m := middleware.NewMiddlewareHandler(httpcommon.NewManager("test-secret", time.Hour), nil, true, false, logger.NewTestLoggerWithOutput(&out))
+
+			var handlerBody string
+			next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				b, _ := io.ReadAll(r.Body)
+				handlerBody = string(b)
+				w.WriteHeader(nethttp.StatusOK)
+			})
+
+			handler := m.RequestInfo(m.Logger(middleware.AccessLogConfig{
+				LogBody:      true,
+				MaxBodySize:  tt.maxBodySize,
+				RedactFields: []string{"password", "token", "code"},
+			})(next))
+
+			req := httptest.NewRequest(nethttp.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			// handler gets whole body
+			assert.Equal(t, tt.body, handlerBody)
+
+			line := out.String()
+			for _, s := range tt.contains {
+				assert.Contains(t, line, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, line, s)
+			}
+		})
+	}
+}
+
+func TestMiddleware_Logger_Actor(t *testing.T) {
+	jwt := httpcommon.NewManager("test-secret", time.Hour)
+	token, err := jwt.Generate("moderator@example.com", "moderator")
+	require.NoError(t, err)
+
+	keys := fakeAPIKeys{
+		"carrier-key": {ID: "key-1", Name: "carrier", Scopes: []apikey_domain.Scope{apikey_domain.ScopeReceptionCreate}},
+	}
+
+	tests := []struct {
+		name        string
+		header      string
+		value       string
+		actorEmail  string
+		actorRole   string
+		expectActor bool
+	}{
+		{"bearer token", "Authorization", "Bearer " + token, "moderator@example.com", "moderator", true},
+		{"api key", middleware.APIKeyHeader, "carrier-key", "apikey:carrier", "service", true},
+		{"anonymous", "Authorization", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			m := middleware.NewMiddlewareHandler(jwt, keys, true, false, logger.NewTestLoggerWithOutput(&out))
+
+			next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				w.WriteHeader(nethttp.StatusCreated)
+			})
+
+			// same order as in main: access log wraps authentication
+			handler := m.RequestInfo(m.Logger(middleware.AccessLogConfig{})(m.Auth(next)))
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/receptions", nil)
+			req.Header.Set(tt.header, tt.value)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			var accessLog map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if entry["message"] == "Request" {
+					accessLog = entry
+				}
+			}
+			require.NotNil(t, accessLog)
+
+			if !tt.expectActor {
+				assert.NotContains(t, accessLog, "actor_email")
+				assert.NotContains(t, accessLog, "actor_role")
+				return
+			}
+			assert.Equal(t, tt.actorEmail, accessLog["actor_email"])
+			assert.Equal(t, tt.actorRole, accessLog["actor_role"])
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcPublicMethods are called without token, all other methods need JWT.
+var grpcPublicMethods = map[string]bool{
+	"/pvz.v1.PVZService/GetPVZList": true,
+	"/grpc.health.v1.Health/Check":  true,
+	"/grpc.health.v1.Health/Watch":  true,
+}
+
+// grpcReadOnlyMethods are allowed for dummy tokens when they are read only.
+var grpcReadOnlyMethods = map[string]bool{
+	"/pvz.v1.PVZService/ListPVZ":         true,
+	"/pvz.v1.PVZService/GetPVZList":      true,
+	"/pvz.v1.PVZService/StreamPVZs":      true,
+	"/events.v1.EventsService/Subscribe": true,
+}
+
+// GRPCAuth is unary interceptor, it puts claims of JWT from "authorization"
+// metadata into context as Auth does for http. Calls without token are
+// rejected unless method is public.
+func (m *Middleware) GRPCAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := m.authenticateGRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// GRPCStreamAuth is stream interceptor with same rules as GRPCAuth.
+func (m *Middleware) GRPCStreamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := m.authenticateGRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+// authStream is ServerStream with context carrying claims.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func (m *Middleware) authenticateGRPC(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if grpcPublicMethods[method] {
+			return ctx, nil
+		}
+		return nil, apperr.GRPCError(ctx, apperr.ErrNoAuth)
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrNoBearer)
+	}
+
+	claims, err := m.jwtManager.Verify(token)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, apperr.ErrInvalidToken)
+	}
+
+	if claims.Dummy() && !m.dummyLogin {
+		m.log.Warn().Ctx(ctx).Str("user_role", claims.Role).Str("method", method).Msg("Dummy token rejected, dummy login is disabled")
+		return nil, apperr.GRPCError(ctx, apperr.ErrInvalidToken)
+	}
+
+	if claims.Dummy() && m.rejectDummyMutating && !grpcReadOnlyMethods[method] {
+		m.log.Warn().Ctx(ctx).Str("user_role", claims.Role).Str("method", method).Msg("Dummy token rejected on mutating request")
+		return nil, apperr.GRPCError(ctx, apperr.ErrDummyReadOnly)
+	}
+
+	ctx = context.WithValue(ctx, httpcommon.DefaultUserKey, claims)
+	ctx = requestinfo.WithActor(ctx, requestinfo.Actor{Email: claims.Email, Role: claims.Role})
+
+	return ctx, nil
+}
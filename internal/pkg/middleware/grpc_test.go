@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddleware_GRPCAuth(t *testing.T) {
+	jwtManager := httpcommon.NewManager("test-secret", time.Hour)
+
+	dummyToken, err := jwtManager.GenerateDummy("moderator")
+	require.NoError(t, err)
+	userToken, err := jwtManager.Generate("test@example.com", "moderator")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		method       string
+		auth         string
+		expectedCode codes.Code
+		expectClaims bool
+	}{
+		{"anonymous public call", "/pvz.v1.PVZService/GetPVZList", "", codes.OK, false},
+		{"anonymous health check", "/grpc.health.v1.Health/Check", "", codes.OK, false},
+		{"anonymous private call rejected", "/pvz.v1.PVZService/ListPVZ", "", codes.Unauthenticated, false},
+		{"anonymous mutating call rejected", "/pvz.v1.PVZService/CreatePVZ", "", codes.Unauthenticated, false},
+		{"user token", "/pvz.v1.PVZService/CreatePVZ", "Bearer " + userToken, codes.OK, true},
+		{"no bearer", "/pvz.v1.PVZService/CreatePVZ", userToken, codes.Unauthenticated, false},
+		{"invalid token", "/pvz.v1.PVZService/CreatePVZ", "Bearer invalid", codes.Unauthenticated, false},
+		{"dummy read call", "/pvz.v1.PVZService/ListPVZ", "Bearer " + dummyToken, codes.OK, true},
+		{"dummy mutating call rejected", "/pvz.v1.PVZService/CreatePVZ", "Bearer " + dummyToken, codes.PermissionDenied, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.NewMiddlewareHandler(jwtManager, nil, true, true, logger.NewTestLogger())
+
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+
+			var gotClaims bool
+			handler := func(ctx context.Context, _ any) (any, error) {
+				_, gotClaims = ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+				return nil, nil
+			}
+
+			_, err := m.GRPCAuth(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectClaims, gotClaims)
+		})
+	}
+
+	t.Run("dummy rejected when dummy login disabled", func(t *testing.T) {
+		m := middleware.NewMiddlewareHandler(jwtManager, nil, false, false, logger.NewTestLogger())
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+dummyToken))
+
+		_, err := m.GRPCAuth(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pvz.v1.PVZService/ListPVZ"}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestMiddleware_GRPCStreamAuth(t *testing.T) {
+	jwtManager := httpcommon.NewManager("test-secret", time.Hour)
+
+	dummyToken, err := jwtManager.GenerateDummy("employee")
+	require.NoError(t, err)
+	userToken, err := jwtManager.Generate("test@example.com", "employee")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		method       string
+		auth         string
+		expectedCode codes.Code
+		expectClaims bool
+	}{
+		{"anonymous stream rejected", "/pvz.v1.PVZService/StreamPVZs", "", codes.Unauthenticated, false},
+		{"invalid token", "/pvz.v1.PVZService/StreamPVZs", "Bearer invalid", codes.Unauthenticated, false},
+		{"user token", "/pvz.v1.PVZService/StreamPVZs", "Bearer " + userToken, codes.OK, true},
+		{"dummy token", "/pvz.v1.PVZService/StreamPVZs", "Bearer " + dummyToken, codes.OK, true},
+		{"anonymous health watch", "/grpc.health.v1.Health/Watch", "", codes.OK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.NewMiddlewareHandler(jwtManager, nil, true, true, logger.NewTestLogger())
+
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+
+			var (
+				called    bool
+				gotClaims bool
+			)
+			handler := func(_ any, ss grpc.ServerStream) error {
+				called = true
+				_, gotClaims = httpcommon.ClaimsFromContext(ss.Context())
+				return nil
+			}
+
+			err := m.GRPCStreamAuth(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+			assert.Equal(t, tt.expectClaims, gotClaims)
+		})
+	}
+}
@@ -1,141 +1,268 @@
 package middleware
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
-	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// APIKeyAuthenticator checks api key and returns it.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*apikey_domain.APIKey, error)
+}
+
 type Middleware struct {
 	jwtManager *httpcommon.JWTManager
-	log        *logger.ZerologLogger
+	// apiKeys is optional, api keys are not accepted if nil
+	apiKeys APIKeyAuthenticator
+	// dummyLogin is false if POST /dummyLogin is disabled, dummy tokens are rejected then
+	dummyLogin bool
+	// rejectDummyMutating makes dummy tokens read only
+	rejectDummyMutating bool
+	log                 *logger.ZerologLogger
 }
 
-func NewMiddlewareHandler(jwt *httpcommon.JWTManager, l *logger.ZerologLogger) *Middleware {
+func NewMiddlewareHandler(
+	jwt *httpcommon.JWTManager,
+	apiKeys APIKeyAuthenticator,
+	dummyLogin bool,
+	rejectDummyMutating bool,
+	l *logger.ZerologLogger,
+) *Middleware {
 	return &Middleware{
-		jwtManager: jwt,
-		log:        l,
+		jwtManager:          jwt,
+		apiKeys:             apiKeys,
+		dummyLogin:          dummyLogin,
+		rejectDummyMutating: rejectDummyMutating,
+		log:                 l,
 	}
 }
 
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyRouteScopes lists routes available for api keys, others are forbidden.
+var apiKeyRouteScopes = map[string]apikey_domain.Scope{
+	"GET /pvz":         apikey_domain.ScopePVZRead,
+	"GET /events":      apikey_domain.ScopePVZRead,
+	"POST /receptions": apikey_domain.ScopeReceptionCreate,
+	"POST /products":   apikey_domain.ScopeProductAdd,
+}
+
 func (m *Middleware) Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenString := r.Header.Get("Authorization")
-		if tokenString == "" {
-			httpcommon.JSONError(w, http.StatusUnauthorized, errors.New("no auth"))
+		r, creds := m.credentials(r)
+		if creds.err != nil {
+			apperr.WriteHTTP(w, r, creds.err)
 			return
 		}
 
-		parts := strings.Split(tokenString, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			httpcommon.JSONError(w, http.StatusUnauthorized, errors.New("no bearer"))
+		if creds.apiKey != nil {
+			m.authAPIKey(w, r, next, creds)
 			return
 		}
 
-		claims, err := m.jwtManager.Verify(parts[1])
-		if err != nil {
-			httpcommon.JSONError(w, http.StatusUnauthorized, errors.New("invalid token"))
-			return
-		}
+		claims := creds.claims
 
 		// check if token is dummy
-		if claims.IsDummy {
-			m.log.Info().Str("user_role", claims.Role).Msg("Dummy user authenticated successfully")
+		if claims.Dummy() {
+			// token was issued before dummy login was disabled, or signed by other instance
+			if !m.dummyLogin {
+				m.log.Warn().Ctx(r.Context()).Str("user_role", claims.Role).Msg("Dummy token rejected, dummy login is disabled")
+				apperr.WriteHTTP(w, r, apperr.ErrInvalidToken)
+				return
+			}
+			if m.rejectDummyMutating && isMutating(r.Method) {
+				m.log.Warn().Ctx(r.Context()).Str("user_role", claims.Role).Str("method", r.Method).Str("path", r.URL.Path).Msg("Dummy token rejected on mutating request")
+				apperr.WriteHTTP(w, r, apperr.ErrDummyReadOnly)
+				return
+			}
+			m.log.Info().Ctx(r.Context()).Str("user_role", claims.Role).Msg("Dummy user authenticated successfully")
 		} else {
-			m.log.Info().Str("user_email", claims.Email).Str("user_role", claims.Role).Msg("User authenticated successfully")
+			m.log.Info().Ctx(r.Context()).Str("user_email", claims.Email).Str("user_role", claims.Role).Msg("User authenticated successfully")
 		}
 
 		ctx := context.WithValue(r.Context(), httpcommon.DefaultUserKey, claims)
+		ctx = requestinfo.WithActor(ctx, requestinfo.Actor{Email: claims.Email, Role: claims.Role})
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	})
 }
 
-func (m *Middleware) Logger(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
+// authAPIKey puts service principal into context. Principal acts as employee,
+// but only on routes allowed by key scopes.
+func (m *Middleware) authAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, creds *credentials) {
+	key := creds.apiKey
 
-		bodyBytes, _ := io.ReadAll(r.Body)
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-		// Get query params of req
-		queryParams := r.URL.Query()
-
-		// Get form data of req
-		var formData map[string]any
-		if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" || r.Header.Get("Content-Type") == "multipart/form-data" {
-			if err := r.ParseForm(); err == nil {
-				formData = make(map[string]any)
-				for key, values := range r.Form {
-					if len(values) == 1 {
-						formData[key] = values[0]
-					} else {
-						formData[key] = values
-					}
-				}
-			}
-		}
+	scope, ok := apiKeyRouteScopes[r.Method+" "+r.URL.Path]
+	if !ok || !key.HasScope(scope) {
+		m.log.Warn().Ctx(r.Context()).Str("api_key_id", key.ID).Str("method", r.Method).Str("path", r.URL.Path).Msg("API key scope denied")
+		apperr.WriteHTTP(w, r, apperr.ErrAPIKeyScopeDenied)
+		return
+	}
 
-		// Get JSON body of req
-		var jsonBody map[string]any
-		if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+	m.log.Info().Ctx(r.Context()).Str("api_key_id", key.ID).Str("api_key_name", key.Name).Msg("Service authenticated with api key")
 
-			// Restore req body for future using in handlers
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	ctx := context.WithValue(r.Context(), httpcommon.DefaultUserKey, creds.claims)
+	ctx = requestinfo.WithActor(ctx, requestinfo.Actor{Email: creds.claims.Email, Role: "service"})
 
-			// Parse JSON
-			if err := json.Unmarshal(bodyBytes, &jsonBody); err != nil {
-				jsonBody = nil // just ingore it of err != nil
-			}
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
 
-		}
+type credentialsKey struct{}
 
-		// Serve next handler
-		next.ServeHTTP(w, r)
+// credentials are token or api key of request after verification.
+// RateLimit needs them for bucket key before Auth runs, so they are
+// verified once and kept in request context.
+type credentials struct {
+	claims *httpcommon.Claims
+	// apiKey is set for api key principal
+	apiKey *apikey_domain.APIKey
+	// err is response for request without valid credentials
+	err error
+}
 
-		// Create log event
-		logEvent := m.log.Info().
-			Str("method", r.Method).
-			Str("addr", r.RemoteAddr).
-			Str("host", r.Host).
-			Str("request_uri", r.RequestURI).
-			TimeDiff("duration(ms)", time.Now(), start).
-			Str("content_type", r.Header.Get("Content-Type"))
-
-		// If there are query params - log it
-		if len(queryParams) > 0 {
-			logEvent = logEvent.Interface("query_params", queryParams)
-		}
+// credentials returns credentials of r, verified on first call.
+// Returned request carries them for next calls.
+func (m *Middleware) credentials(r *http.Request) (*http.Request, *credentials) {
+	if creds, ok := r.Context().Value(credentialsKey{}).(*credentials); ok {
+		return r, creds
+	}
+
+	creds := m.verifyCredentials(r)
+
+	return r.WithContext(context.WithValue(r.Context(), credentialsKey{}, creds)), creds
+}
+
+func (m *Middleware) verifyCredentials(r *http.Request) *credentials {
+	tokenString := r.Header.Get("Authorization")
 
-		// If there is form data - log it
-		if len(formData) > 0 {
-			logEvent = logEvent.Interface("form_data", formData)
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return m.verifyAPIKey(r, key)
+	}
+	if key, ok := strings.CutPrefix(tokenString, "ApiKey "); ok {
+		return m.verifyAPIKey(r, key)
+	}
+
+	if tokenString == "" {
+		return &credentials{err: apperr.ErrNoAuth}
+	}
+
+	parts := strings.Split(tokenString, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return &credentials{err: apperr.ErrNoBearer}
+	}
+
+	claims, err := m.jwtManager.Verify(parts[1])
+	if err != nil {
+		return &credentials{err: apperr.ErrInvalidToken}
+	}
+
+	return &credentials{claims: claims}
+}
+
+func (m *Middleware) verifyAPIKey(r *http.Request, rawKey string) *credentials {
+	if m.apiKeys == nil {
+		return &credentials{err: apperr.ErrAPIKeysDisabled}
+	}
+
+	key, err := m.apiKeys.Authenticate(r.Context(), rawKey)
+	if err != nil {
+		m.log.Warn().Ctx(r.Context()).Err(err).Str("path", r.URL.Path).Msg("API key authentication failed")
+		return &credentials{err: apperr.ErrInvalidAPIKey}
+	}
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, s.String())
+	}
+
+	return &credentials{
+		claims: &httpcommon.Claims{
+			Email:    "apikey:" + key.Name,
+			Role:     auth_domain.RoleEmployee.String(),
+			APIKeyID: key.ID,
+			Scopes:   scopes,
+			PVZID:    key.PVZID,
+		},
+		apiKey: key,
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	return true
+}
+
+const RequestIDHeader = httpcommon.RequestIDHeader
+
+// maxRequestIDLength limits accepted X-Request-ID, longer ids are replaced.
+const maxRequestIDLength = 128
+
+// RequestInfo puts request id, client ip and holder of actor into request context.
+// Actor is set by Auth deeper in chain, holder makes it visible to access log.
+// Request id is taken from X-Request-ID header or generated,
+// it is echoed in X-Request-ID response header.
+func (m *Middleware) RequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
 		}
+		w.Header().Set(RequestIDHeader, requestID)
 
-		// If there is json body - log it
-		if len(jsonBody) > 0 {
-			logEvent = logEvent.Interface("json_body", jsonBody)
+		clientIP := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			clientIP = host
 		}
 
-		// Write final log
-		logEvent.Msg("Request")
+		ctx := requestinfo.WithRequestID(r.Context(), requestID)
+		ctx = requestinfo.WithClientIP(ctx, clientIP)
+		ctx = requestinfo.WithActorHolder(ctx)
+
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", requestID))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// validRequestID accepts ids of printable ascii without spaces,
+// so client ids can not break log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (m *Middleware) Metrics(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		rw := &responseWriter{w, http.StatusOK}
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
 
 		next.ServeHTTP(rw, r)
 
@@ -157,9 +284,21 @@ func (m *Middleware) Metrics(next http.Handler) http.Handler {
 type responseWriter struct {
 	http.ResponseWriter
 	status int
+	bytes  int
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.status = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach underlying writer (Flush, deadlines).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_Auth_Dummy(t *testing.T) {
+	jwtManager := httpcommon.NewManager("test-secret", time.Hour)
+
+	dummyToken, err := jwtManager.GenerateDummy("moderator")
+	require.NoError(t, err)
+	userToken, err := jwtManager.Generate("test@example.com", "moderator")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		dummyLogin     bool
+		rejectMutating bool
+		method         string
+		token          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"dummy allowed by default", true, false, nethttp.MethodPost, dummyToken, nethttp.StatusOK, ""},
+		{"dummy read request", true, true, nethttp.MethodGet, dummyToken, nethttp.StatusOK, ""},
+		{"dummy mutating request rejected", true, true, nethttp.MethodPost, dummyToken, nethttp.StatusForbidden, "dummy_read_only"},
+		{"dummy delete rejected", true, true, nethttp.MethodDelete, dummyToken, nethttp.StatusForbidden, "dummy_read_only"},
+		{"dummy rejected when dummy login disabled", false, false, nethttp.MethodGet, dummyToken, nethttp.StatusUnauthorized, "invalid_token"},
+		{"real user when dummy login disabled", false, false, nethttp.MethodPost, userToken, nethttp.StatusOK, ""},
+		{"real user mutating request", true, true, nethttp.MethodPost, userToken, nethttp.StatusOK, ""},
+		{"invalid token", true, true, nethttp.MethodGet, "invalid", nethttp.StatusUnauthorized, "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.NewMiddlewareHandler(jwtManager, nil, tt.dummyLogin, tt.rejectMutating, logger.NewTestLogger())
+
+			next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+				w.WriteHeader(nethttp.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/pvz", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+
+			m.Auth(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var resp httpcommon.ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedCode, resp.Code)
+			}
+		})
+	}
+}
+
+type fakeAPIKeys map[string]*apikey_domain.APIKey
+
+func (f fakeAPIKeys) Authenticate(_ context.Context, key string) (*apikey_domain.APIKey, error) {
+	k, ok := f[key]
+	if !ok {
+		return nil, apikey_domain.ErrInvalidAPIKey
+	}
+	return k, nil
+}
+
+func TestMiddleware_Auth_APIKey(t *testing.T) {
+	keys := fakeAPIKeys{
+		"carrier-key": {
+			ID:     "key-1",
+			Name:   "carrier",
+			Scopes: []apikey_domain.Scope{apikey_domain.ScopeReceptionCreate, apikey_domain.ScopeProductAdd},
+			PVZID:  "pvz-1",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		apiKeys        middleware.APIKeyAuthenticator
+		method         string
+		path           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"x-api-key header", keys, nethttp.MethodPost, "/receptions", middleware.APIKeyHeader, "carrier-key", nethttp.StatusOK},
+		{"authorization header", keys, nethttp.MethodPost, "/products", "Authorization", "ApiKey carrier-key", nethttp.StatusOK},
+		{"scope missing", keys, nethttp.MethodGet, "/pvz", middleware.APIKeyHeader, "carrier-key", nethttp.StatusForbidden},
+		{"route not available for keys", keys, nethttp.MethodPost, "/api-keys", middleware.APIKeyHeader, "carrier-key", nethttp.StatusForbidden},
+		{"unknown key", keys, nethttp.MethodPost, "/receptions", middleware.APIKeyHeader, "other", nethttp.StatusUnauthorized},
+		{"api keys disabled", nil, nethttp.MethodPost, "/receptions", middleware.APIKeyHeader, "carrier-key", nethttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.NewMiddlewareHandler(httpcommon.NewManager("test-secret", time.Hour), tt.apiKeys, true, false, logger.NewTestLogger())
+
+			var claims *httpcommon.Claims
+			next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				claims, _ = r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+				w.WriteHeader(nethttp.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+
+			m.Auth(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == nethttp.StatusOK {
+				require.NotNil(t, claims)
+				assert.Equal(t, "key-1", claims.APIKeyID)
+				assert.Equal(t, "employee", claims.Role)
+				assert.Equal(t, "pvz-1", claims.PVZID)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/ratelimit"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/requestinfo"
+)
+
+// RateLimit limits requests per client with token buckets.
+// Client is api key or user of verified credentials, requests without them
+// (anonymous, invalid token or api key, dummy token) are limited by ip.
+// Every request is counted in one bucket only.
+//
+// It is applied once around the whole mux, before Auth. Credentials are verified
+// here and kept in request context, so Auth does not verify them again.
+// Nil limiter disables rate limiting.
+func (m *Middleware) RateLimit(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, key := m.rateLimitKey(r)
+
+			result, err := limiter.Allow(r.Context(), key, r)
+			if err != nil {
+				// fail open, storage outage must not take api down
+				m.log.Error().Ctx(r.Context()).Err(err).Str("key", key).Msg("Rate limit check failed")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w, result)
+
+			if !result.Allowed {
+				route := result.Route
+				if route == "" {
+					route = "unmatched"
+				}
+				metrics.RateLimitedTotal.WithLabelValues(r.Method, route).Inc()
+				m.log.Warn().Ctx(r.Context()).Str("key", key).Str("method", r.Method).Str("path", r.URL.Path).Msg("Rate limit exceeded")
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				apperr.WriteHTTP(w, r, apperr.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey returns bucket key for request and request with its verified credentials.
+func (m *Middleware) rateLimitKey(r *http.Request) (*http.Request, string) {
+	r, creds := m.credentials(r)
+	if creds.err == nil {
+		switch {
+		case creds.claims.APIKeyID != "":
+			return r, "apikey:" + creds.claims.APIKeyID
+		case creds.claims.Email != "":
+			return r, "user:" + creds.claims.Email
+		}
+	}
+
+	// dummy tokens have no subject, limited by ip
+	return r, "ip:" + requestinfo.ClientIP(r.Context())
+}
+
+// setRateLimitHeaders sets RateLimit-* headers (draft-ietf-httpapi-ratelimit-headers).
+func setRateLimitHeaders(w http.ResponseWriter, result *ratelimit.Result) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(result.Limit.Requests))
+	h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	h.Set("RateLimit-Policy", strconv.Itoa(result.Limit.Requests)+";w="+strconv.Itoa(ceilSeconds(result.Limit.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,165 @@
+package middleware_test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAPIKeys counts api key verifications.
+type countingAPIKeys struct {
+	fakeAPIKeys
+	calls atomic.Int32
+}
+
+func (c *countingAPIKeys) Authenticate(ctx context.Context, key string) (*apikey_domain.APIKey, error) {
+	c.calls.Add(1)
+	return c.fakeAPIKeys.Authenticate(ctx, key)
+}
+
+func TestMiddleware_RateLimit(t *testing.T) {
+	jwtManager := httpcommon.NewManager("test-secret", time.Hour)
+
+	tokenA, err := jwtManager.Generate("a@example.com", "employee")
+	require.NoError(t, err)
+	tokenB, err := jwtManager.Generate("b@example.com", "employee")
+	require.NoError(t, err)
+
+	policy, err := ratelimit.ParsePolicy("100/1m", "POST /products=2/1m")
+	require.NoError(t, err)
+
+	apiKeys := &countingAPIKeys{fakeAPIKeys: fakeAPIKeys{
+		"carrier-key": {ID: "key-1", Name: "carrier", Scopes: []apikey_domain.Scope{apikey_domain.ScopeProductAdd}},
+	}}
+
+	m := middleware.NewMiddlewareHandler(jwtManager, apiKeys, true, false, logger.NewTestLogger())
+	rateLimit := m.RateLimit(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policy))
+
+	next := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusOK)
+	})
+
+	// same chain as in app: limiter around whole mux, auth for private routes
+	mux := nethttp.NewServeMux()
+	mux.Handle("POST /login", next)
+	mux.Handle("/", m.Auth(next))
+	handler := m.RequestInfo(rateLimit(mux))
+
+	do := func(method, path, token, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("limited per user", func(t *testing.T) {
+		rec := do(nethttp.MethodPost, "/products", tokenA, "10.0.0.1")
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+
+		// same user from other ip shares bucket
+		rec = do(nethttp.MethodPost, "/products", tokenA, "10.0.0.2")
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+
+		rec = do(nethttp.MethodPost, "/products", tokenA, "10.0.0.1")
+		assert.Equal(t, nethttp.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+		// other user has own bucket
+		rec = do(nethttp.MethodPost, "/products", tokenB, "10.0.0.1")
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+	})
+
+	t.Run("anonymous limited per ip", func(t *testing.T) {
+		rec := do(nethttp.MethodPost, "/login", "", "10.0.0.3")
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.Equal(t, "99", rec.Header().Get("RateLimit-Remaining"))
+
+		rec = do(nethttp.MethodPost, "/login", "", "10.0.0.3")
+		assert.Equal(t, "98", rec.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("invalid token limited per ip", func(t *testing.T) {
+		rec := do(nethttp.MethodPost, "/products", "invalid", "10.0.0.4")
+		assert.Equal(t, nethttp.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+
+		rec = do(nethttp.MethodPost, "/products", "invalid", "10.0.0.4")
+		assert.Equal(t, nethttp.StatusUnauthorized, rec.Code)
+
+		rec = do(nethttp.MethodPost, "/products", "invalid", "10.0.0.4")
+		assert.Equal(t, nethttp.StatusTooManyRequests, rec.Code)
+	})
+
+	t.Run("unverified api key limited per ip", func(t *testing.T) {
+		req := httptest.NewRequest(nethttp.MethodGet, "/pvz", nil)
+		req.RemoteAddr = "10.0.0.5:1234"
+		req.Header.Set(middleware.APIKeyHeader, "forged")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, nethttp.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "99", rec.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("api key limited per key only", func(t *testing.T) {
+		apiKeys.calls.Store(0)
+
+		withKey := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(nethttp.MethodPost, "/products", nil)
+			req.RemoteAddr = "10.0.0.7:1234"
+			req.Header.Set(middleware.APIKeyHeader, "carrier-key")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		rec := withKey()
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		// key is verified once, auth reuses result of limiter
+		assert.Equal(t, int32(1), apiKeys.calls.Load())
+
+		assert.Equal(t, nethttp.StatusOK, withKey().Code)
+		assert.Equal(t, nethttp.StatusTooManyRequests, withKey().Code)
+
+		// ip bucket is not used by api key requests
+		rec = do(nethttp.MethodPost, "/products", "", "10.0.0.7")
+		assert.Equal(t, nethttp.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("valid token not counted per ip", func(t *testing.T) {
+		rec := do(nethttp.MethodGet, "/pvz", tokenB, "10.0.0.6")
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.Equal(t, "99", rec.Header().Get("RateLimit-Remaining"))
+
+		rec = do(nethttp.MethodPost, "/login", "", "10.0.0.6")
+		assert.Equal(t, "99", rec.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		m.RateLimit(nil)(next).ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/pvz", nil))
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	})
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts server span for request, trace is continued from
+// W3C traceparent header. It must be the outermost middleware.
+// Span is named by method until Route names it by matched pattern.
+func (m *Middleware) Tracing(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+}
+
+// Route names request span by matched mux pattern ("POST /pvz/{pvzId}/close_last_reception"),
+// so span names have low cardinality. Catch-all "/" pattern is skipped,
+// it mounts nested mux which is wrapped by Route too.
+func (m *Middleware) Route(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" && pattern != "/" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(pattern)
+			span.SetAttributes(semconv.HTTPRoute(pattern))
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
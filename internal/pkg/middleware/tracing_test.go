@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	m := middleware.NewMiddlewareHandler(httpcommon.NewManager("test-secret", time.Hour), nil, true, false, logger.NewTestLogger())
+
+	ok := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusOK)
+	})
+
+	privateMux := nethttp.NewServeMux()
+	privateMux.Handle("POST /pvz/{pvzId}/close_last_reception", ok)
+
+	mux := nethttp.NewServeMux()
+	mux.Handle("POST /login", ok)
+	mux.Handle("/", m.Route(privateMux))
+
+	handler := m.Tracing(m.RequestInfo(m.Route(mux)))
+
+	tests := []struct {
+		name       string
+		path       string
+		expectName string
+	}{
+		{"public route", "/login", "POST /login"},
+		{"nested mux route", "/pvz/123/close_last_reception", "POST /pvz/{pvzId}/close_last_reception"},
+		{"unknown route", "/unknown", "POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(nethttp.MethodPost, tt.path, nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+
+			assert.Equal(t, tt.expectName, span.Name())
+			// trace is continued from traceparent header
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Package oidctest is local OIDC provider for tests.
+// It supports discovery, authorization code flow with PKCE (S256) and JWKS.
+// Authorization endpoint does not show login page, it immediately
+// redirects back with code for current user.
+package oidctest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// User is identity returned in id token.
+type User struct {
+	Subject string
+	Email   string
+	// EmailVerified is put to id token if not nil.
+	EmailVerified *bool
+	Groups        []string
+}
+
+type authRequest struct {
+	user        User
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+type Provider struct {
+	server *httptest.Server
+
+	clientID     string
+	clientSecret string
+	key          *httpcommon.SigningKey
+	keys         *httpcommon.KeySet
+
+	mu    sync.Mutex
+	user  User
+	codes map[string]authRequest
+}
+
+// New starts provider, call Close when done.
+func New(clientID, clientSecret string) *Provider {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	// id tokens are signed with RS256 in most providers,
+	// but go-oidc accepts EdDSA too and key generation is much faster
+	key, err := httpcommon.NewSigningKey(priv, priv.Public())
+	if err != nil {
+		panic(err)
+	}
+
+	keys, err := httpcommon.NewKeySet(key)
+	if err != nil {
+		panic(err)
+	}
+
+	p := &Provider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		key:          key,
+		keys:         keys,
+		codes:        make(map[string]authRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /jwks", p.jwks)
+
+	p.server = httptest.NewServer(mux)
+
+	return p
+}
+
+func (p *Provider) Issuer() string {
+	return p.server.URL
+}
+
+func (p *Provider) Close() {
+	p.server.Close()
+}
+
+// SetUser sets user which is logged in on next authorization request.
+func (p *Provider) SetUser(u User) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.user = u
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"jwks_uri":                              p.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{p.key.Method.Alg()},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("client_id") != p.clientID || q.Get("response_type") != "code" {
+		http.Error(w, "invalid client or response type", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "pkce is required", http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("redirect_uri") == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+
+	p.mu.Lock()
+	p.codes[code] = authRequest{
+		user:        p.user,
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		redirectURI: q.Get("redirect_uri"),
+	}
+	p.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID || clientSecret != p.clientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	p.mu.Lock()
+	req, found := p.codes[code]
+	delete(p.codes, code) // codes are one-time
+	p.mu.Unlock()
+
+	if !found || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != req.redirectURI {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != req.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":    p.Issuer(),
+		"sub":    req.user.Subject,
+		"aud":    p.clientID,
+		"iat":    now.Unix(),
+		"exp":    now.Add(time.Hour).Unix(),
+		"nonce":  req.nonce,
+		"email":  req.user.Email,
+		"groups": req.user.Groups,
+	}
+	if req.user.EmailVerified != nil {
+		claims["email_verified"] = *req.user.EmailVerified
+	}
+
+	token := jwt.NewWithClaims(p.key.Method, claims)
+	token.Header["kid"] = p.key.ID
+
+	idToken, err := token.SignedString(p.key.Private)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, p.keys.JWKS())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore keeps buckets in avito.rate_limits table,
+// so limits are shared between replicas.
+// Bucket is refilled and taken in one upsert, so concurrent requests are not double counted.
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	takes atomic.Int64
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{
+		pool: pool,
+	}
+}
+
+// refilledTokens is bucket tokens after refill, SET expressions see old row values.
+const refilledTokens = `LEAST(
+	@capacity::float8,
+	avito.rate_limits.tokens + GREATEST(0, EXTRACT(EPOCH FROM (@now::timestamp - avito.rate_limits.updated_at))::float8) * @rate::float8
+)`
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (float64, bool, error) {
+	query := `
+		INSERT INTO avito.rate_limits (key, tokens, allowed, updated_at, expires_at)
+		VALUES (@key, @capacity::float8 - 1, TRUE, @now, @expires_at)
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = CASE
+				WHEN ` + refilledTokens + ` >= 1 THEN ` + refilledTokens + ` - 1
+				ELSE ` + refilledTokens + `
+			END,
+			allowed = ` + refilledTokens + ` >= 1,
+			updated_at = @now,
+			expires_at = @expires_at
+		RETURNING tokens, allowed
+	`
+
+	args := pgx.NamedArgs{
+		"key":        key,
+		"capacity":   float64(limit.Requests),
+		"rate":       limit.Rate(),
+		"now":        now,
+		"expires_at": now.Add(limit.Period),
+	}
+
+	var (
+		tokens  float64
+		allowed bool
+	)
+	if err := s.pool.QueryRow(ctx, query, args).Scan(&tokens, &allowed); err != nil {
+		return 0, false, fmt.Errorf("rate limit take: %w", err)
+	}
+
+	if s.takes.Add(1)%sweepEvery == 0 {
+		s.sweep(ctx, now)
+	}
+
+	return tokens, allowed, nil
+}
+
+// sweep removes buckets which are full again (expires_at is upper bound of refill time).
+// Errors are ignored, rows are removed on next sweep.
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	query := `
+		DELETE FROM avito.rate_limits
+		WHERE expires_at < @now
+	`
+
+	_, _ = s.pool.Exec(ctx, query, pgx.NamedArgs{"now": now})
+}
@@ -0,0 +1,197 @@
+// Package ratelimit implements token bucket rate limiting with pluggable
+// bucket storage (process memory or shared postgres table).
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is token bucket: up to Requests requests at once,
+// refilled with Requests tokens per Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses "requests/period", e.g. "10/1s" or "100/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, want requests/period", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: requests must be positive integer", s)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: period must be positive duration", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Rate is refill rate in tokens per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// Policy is set of limits. Route limits are matched with http.ServeMux
+// pattern rules ("POST /products", "POST /pvz/{pvzId}/close_last_reception"),
+// requests to other routes get Default.
+type Policy struct {
+	Default Limit
+	routes  *http.ServeMux
+	limits  map[string]Limit
+}
+
+// ParsePolicy parses default limit and route limits
+// in "METHOD /path=requests/period;METHOD /path=requests/period" format.
+func ParsePolicy(defaultLimit, routes string) (*Policy, error) {
+	def, err := ParseLimit(defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{
+		Default: def,
+		routes:  http.NewServeMux(),
+		limits:  make(map[string]Limit),
+	}
+
+	for _, route := range strings.Split(routes, ";") {
+		route = strings.TrimSpace(route)
+		if route == "" {
+			continue
+		}
+
+		pattern, limit, ok := strings.Cut(route, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route limit %q, want pattern=requests/period", route)
+		}
+
+		l, err := ParseLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := policy.addRoute(strings.TrimSpace(pattern), l); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) addRoute(pattern string, limit Limit) (err error) {
+	// ServeMux panics on invalid or duplicate pattern
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid route pattern %q: %v", pattern, r)
+		}
+	}()
+
+	p.routes.Handle(pattern, http.NotFoundHandler())
+	p.limits[pattern] = limit
+
+	return nil
+}
+
+// Match returns route pattern (empty for default) and limit for request.
+func (p *Policy) Match(r *http.Request) (string, Limit) {
+	if p.routes != nil {
+		if _, pattern := p.routes.Handler(r); pattern != "" {
+			return pattern, p.limits[pattern]
+		}
+	}
+
+	return "", p.Default
+}
+
+// Store keeps token buckets.
+type Store interface {
+	// Take refills bucket for key by time passed since last call and takes one token if any.
+	// It returns tokens left in bucket and whether token was taken.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (tokens float64, allowed bool, err error)
+}
+
+// Result is outcome of rate limit check, used for RateLimit-* headers.
+type Result struct {
+	Allowed bool
+	// Route is policy route pattern matched by request, empty for default limit.
+	Route     string
+	Limit     Limit
+	Remaining int
+	// Reset is time until bucket is full again.
+	Reset time.Duration
+	// RetryAfter is time until next token, zero if request is allowed.
+	RetryAfter time.Duration
+}
+
+type Limiter struct {
+	store  Store
+	policy *Policy
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, policy *Policy) *Limiter {
+	return &Limiter{
+		store:  store,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Allow takes token from bucket of key for request route.
+// Buckets are separate per route, so limit on one route does not affect others.
+func (l *Limiter) Allow(ctx context.Context, key string, r *http.Request) (*Result, error) {
+	pattern, limit := l.policy.Match(r)
+
+	tokens, allowed, err := l.store.Take(ctx, key+"|"+pattern, limit, l.now())
+	if err != nil {
+		return nil, err
+	}
+
+	rate := limit.Rate()
+	result := &Result{
+		Allowed:   allowed,
+		Route:     pattern,
+		Limit:     limit,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     secondsToDuration((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result, nil
+}
+
+// refill returns tokens in bucket after elapsed time.
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*limit.Rate())
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
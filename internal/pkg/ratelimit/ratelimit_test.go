@@ -0,0 +1,154 @@
+package ratelimit_test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  ratelimit.Limit
+		expectErr bool
+	}{
+		{"10/1s", ratelimit.Limit{Requests: 10, Period: time.Second}, false},
+		{" 100/1m ", ratelimit.Limit{Requests: 100, Period: time.Minute}, false},
+		{"10", ratelimit.Limit{}, true},
+		{"0/1s", ratelimit.Limit{}, true},
+		{"10/0s", ratelimit.Limit{}, true},
+		{"ten/1s", ratelimit.Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			limit, err := ratelimit.ParseLimit(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestPolicy_Match(t *testing.T) {
+	policy, err := ratelimit.ParsePolicy("100/1m", "POST /products=10/1s; POST /pvz/{pvzId}/close_last_reception=5/1m")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		expectPattern string
+		expectLimit   ratelimit.Limit
+	}{
+		{"exact route", nethttp.MethodPost, "/products", "POST /products", ratelimit.Limit{Requests: 10, Period: time.Second}},
+		{"route with wildcard", nethttp.MethodPost, "/pvz/123/close_last_reception", "POST /pvz/{pvzId}/close_last_reception", ratelimit.Limit{Requests: 5, Period: time.Minute}},
+		{"other method", nethttp.MethodGet, "/products", "", ratelimit.Limit{Requests: 100, Period: time.Minute}},
+		{"other route", nethttp.MethodGet, "/pvz", "", ratelimit.Limit{Requests: 100, Period: time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, limit := policy.Match(httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectPattern, pattern)
+			assert.Equal(t, tt.expectLimit, limit)
+		})
+	}
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		def    string
+		routes string
+	}{
+		{"invalid default", "100", ""},
+		{"route without limit", "100/1m", "POST /products"},
+		{"invalid route limit", "100/1m", "POST /products=fast"},
+		{"invalid pattern", "100/1m", "POST products=10/1s"},
+		{"duplicate pattern", "100/1m", "POST /products=10/1s;POST /products=20/1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ratelimit.ParsePolicy(tt.def, tt.routes)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 2, Period: 2 * time.Second}
+	now := time.Now()
+	ctx := context.Background()
+
+	tokens, allowed, err := store.Take(ctx, "key", limit, now)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 1.0, tokens)
+
+	_, allowed, _ = store.Take(ctx, "key", limit, now)
+	assert.True(t, allowed)
+
+	_, allowed, _ = store.Take(ctx, "key", limit, now)
+	assert.False(t, allowed, "bucket is empty")
+
+	_, allowed, _ = store.Take(ctx, "other", limit, now)
+	assert.True(t, allowed, "buckets are separate per key")
+
+	// one token per second is refilled
+	tokens, allowed, _ = store.Take(ctx, "key", limit, now.Add(time.Second))
+	assert.True(t, allowed)
+	assert.InDelta(t, 0.0, tokens, 1e-9)
+
+	// bucket is not refilled over capacity
+	tokens, allowed, _ = store.Take(ctx, "key", limit, now.Add(time.Hour))
+	assert.True(t, allowed)
+	assert.InDelta(t, 1.0, tokens, 1e-9)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	policy, err := ratelimit.ParsePolicy("100/1m", "POST /products=2/1h")
+	require.NoError(t, err)
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policy)
+	ctx := context.Background()
+	req := httptest.NewRequest(nethttp.MethodPost, "/products", nil)
+
+	result, err := limiter.Allow(ctx, "user:a", req)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "POST /products", result.Route)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, 2, result.Limit.Requests)
+	assert.Zero(t, result.RetryAfter)
+
+	result, err = limiter.Allow(ctx, "user:a", req)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = limiter.Allow(ctx, "user:a", req)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.InDelta(t, 30*time.Minute, result.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Hour, result.Reset, float64(time.Second))
+
+	// other routes have own buckets
+	result, err = limiter.Allow(ctx, "user:a", httptest.NewRequest(nethttp.MethodGet, "/pvz", nil))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 99, result.Remaining)
+}
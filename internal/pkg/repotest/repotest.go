@@ -0,0 +1,876 @@
+// Package repotest is contract test suite for repositories.
+// Every storage backend runs it, so services can rely on same errors,
+// ordering and invariants whichever backend is configured.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories of one storage backend. They share storage,
+// so reception repository sees pvz created by pvz repository.
+type Repositories struct {
+	PVZ       pvz_domain.PVZRepository
+	Reception reception_domain.ReceptionRepository
+	Product   product_domain.ProductRepository
+	User      auth_domain.UserRepository
+	Journal   sync_domain.JournalRepository
+	// LoginAttempts is not bound to storage of other repositories.
+	LoginAttempts auth_domain.LoginAttemptRepository
+
+	// Tx runs transactions over same storage.
+	Tx database.Transactor
+	// RollsBack is false for storage which keeps changes of failed transaction (memdb).
+	RollsBack bool
+}
+
+// Factory returns repositories over empty storage.
+type Factory func(t *testing.T) Repositories
+
+// base is start of test timeline. Times are in UTC with microsecond precision,
+// so they survive round trip through any backend.
+var base = time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return base.Add(time.Duration(minutes) * time.Minute)
+}
+
+// Run runs contract suite, every test gets repositories from newRepos.
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("PVZ", func(t *testing.T) { testPVZ(t, newRepos) })
+	t.Run("Reception", func(t *testing.T) { testReception(t, newRepos) })
+	t.Run("Product", func(t *testing.T) { testProduct(t, newRepos) })
+	t.Run("User", func(t *testing.T) { testUser(t, newRepos) })
+	t.Run("Journal", func(t *testing.T) { testJournal(t, newRepos) })
+	t.Run("LoginAttempts", func(t *testing.T) { testLoginAttempts(t, newRepos) })
+}
+
+func createPVZ(t *testing.T, r Repositories, registered time.Time) *pvz_domain.PVZ {
+	t.Helper()
+
+	id := uuid.NewString()
+	pvz, err := r.PVZ.Create(context.Background(), &pvz_domain.PVZ{ID: &id, RegistrationDate: &registered, City: pvz_domain.Kazan})
+	require.NoError(t, err)
+
+	return pvz
+}
+
+func openReception(t *testing.T, r Repositories, pvzID string, opened time.Time) *reception_domain.Reception {
+	t.Helper()
+
+	reception, err := r.Reception.Create(context.Background(), &reception_domain.Reception{
+		ID:       uuid.NewString(),
+		DateTime: opened,
+		PVZID:    pvzID,
+		Status:   reception_domain.InProgress,
+	})
+	require.NoError(t, err)
+
+	return reception
+}
+
+func addProduct(t *testing.T, r Repositories, receptionID string, added time.Time) *product_domain.Product {
+	t.Helper()
+
+	product, err := r.Product.Create(context.Background(), &product_domain.Product{
+		ID:          uuid.NewString(),
+		DateTime:    added,
+		Type:        product_domain.Shoes,
+		ReceptionID: receptionID,
+	})
+	require.NoError(t, err)
+
+	return product
+}
+
+func ids[T any](items []T, id func(T) string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, id(item))
+	}
+
+	return out
+}
+
+func pvzID(p *pvz_domain.PVZ) string                   { return *p.ID }
+func receptionID(r *reception_domain.Reception) string { return r.ID }
+func productID(p *product_domain.Product) string       { return p.ID }
+
+func testPVZ(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		r := newRepos(t)
+
+		created := createPVZ(t, r, at(0))
+		assert.True(t, created.RegistrationDate.Equal(at(0)))
+		assert.Equal(t, pvz_domain.Kazan, created.City)
+
+		got, err := r.PVZ.GetByID(ctx, *created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, *created.ID, *got.ID)
+		assert.True(t, got.RegistrationDate.Equal(at(0)))
+		assert.Equal(t, created.City, got.City)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		r := newRepos(t)
+
+		created := createPVZ(t, r, at(0))
+		_, err := r.PVZ.Create(ctx, created)
+		assert.ErrorIs(t, err, pvz_domain.ErrPVZAlreadyExists)
+	})
+
+	t.Run("get unknown", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.PVZ.GetByID(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, pvz_domain.ErrPVZNotFound)
+	})
+
+	t.Run("list all", func(t *testing.T) {
+		r := newRepos(t)
+
+		pvzs, err := r.PVZ.ListAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, pvzs)
+		assert.Empty(t, pvzs)
+
+		first := createPVZ(t, r, at(0))
+		second := createPVZ(t, r, at(1))
+
+		pvzs, err = r.PVZ.ListAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{*first.ID, *second.ID}, ids(pvzs, pvzID))
+	})
+
+	t.Run("list page", func(t *testing.T) {
+		r := newRepos(t)
+
+		pvzs, err := r.PVZ.ListPage(ctx, "", nil, 10)
+		require.NoError(t, err)
+		assert.NotNil(t, pvzs)
+		assert.Empty(t, pvzs)
+
+		newest := createPVZ(t, r, at(2))
+		oldest := createPVZ(t, r, at(0))
+		// same registration date, ordered by id
+		sameA := createPVZ(t, r, at(1))
+		sameB := createPVZ(t, r, at(1))
+		if *sameB.ID < *sameA.ID {
+			sameA, sameB = sameB, sameA
+		}
+		want := []string{*oldest.ID, *sameA.ID, *sameB.ID, *newest.ID}
+
+		var (
+			got   []string
+			after *pvz_domain.PageKey
+		)
+		for range len(want) {
+			page, err := r.PVZ.ListPage(ctx, "", after, 3)
+			require.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+
+			got = append(got, ids(page, pvzID)...)
+			last := page[len(page)-1]
+			after = &pvz_domain.PageKey{RegistrationDate: *last.RegistrationDate, ID: *last.ID}
+		}
+		assert.Equal(t, want, got)
+
+		page, err := r.PVZ.ListPage(ctx, "", after, 3)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		// restricted to one pvz
+		page, err = r.PVZ.ListPage(ctx, *sameB.ID, nil, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{*sameB.ID}, ids(page, pvzID))
+	})
+
+	t.Run("list with receptions", func(t *testing.T) {
+		r := newRepos(t)
+
+		older := createPVZ(t, r, at(0))
+		newer := createPVZ(t, r, at(1))
+
+		closed := openReception(t, r, *older.ID, at(10))
+		_, err := r.Reception.CloseLastReception(ctx, *older.ID, at(20), "")
+		require.NoError(t, err)
+		open := openReception(t, r, *older.ID, at(30))
+		outside := openReception(t, r, *newer.ID, at(100))
+
+		alive := addProduct(t, r, open.ID, at(31))
+		addProduct(t, r, open.ID, at(32))
+		_, err = r.Product.DeleteLastFromReception(ctx, open.ID, at(33), "")
+		require.NoError(t, err)
+
+		from, to := at(5), at(50)
+		result, err := r.PVZ.ListWithReceptions(ctx, &from, &to, "", 1, 10)
+		require.NoError(t, err)
+
+		// reception of newer pvz is out of range
+		require.Len(t, result, 1)
+		assert.Equal(t, *older.ID, *result[0].PVZ.ID)
+		assert.Equal(t, []string{open.ID, closed.ID}, ids(result[0].Receptions, func(r *pvz_domain.ReceptionWithProducts) string { return r.Reception.ID }))
+
+		// deleted products are hidden
+		assert.Equal(t, []string{alive.ID}, ids(result[0].Receptions[0].Products, productID))
+		assert.NotNil(t, result[0].Receptions[1].Products)
+		assert.Empty(t, result[0].Receptions[1].Products)
+
+		// without date filter
+		result, err = r.PVZ.ListWithReceptions(ctx, nil, nil, "", 1, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{*older.ID, *newer.ID}, ids(result, func(p *pvz_domain.PVZWithReceptions) string { return *p.PVZ.ID }))
+
+		// rows are paged, first page has newest pvz
+		result, err = r.PVZ.ListWithReceptions(ctx, nil, nil, "", 1, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, *newer.ID, *result[0].PVZ.ID)
+		assert.Equal(t, []string{outside.ID}, ids(result[0].Receptions, func(r *pvz_domain.ReceptionWithProducts) string { return r.Reception.ID }))
+
+		result, err = r.PVZ.ListWithReceptions(ctx, nil, nil, "", 10, 10)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+
+		// pvz filter is applied before paging, older pvz is not on first unfiltered page
+		result, err = r.PVZ.ListWithReceptions(ctx, nil, nil, *older.ID, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, *older.ID, *result[0].PVZ.ID)
+		assert.Equal(t, []string{open.ID}, ids(result[0].Receptions, func(r *pvz_domain.ReceptionWithProducts) string { return r.Reception.ID }))
+	})
+}
+
+func testReception(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("create and find", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+
+		created := openReception(t, r, *pvz.ID, at(1))
+		assert.Equal(t, *pvz.ID, created.PVZID)
+		assert.Equal(t, reception_domain.InProgress, created.Status)
+		assert.True(t, created.DateTime.Equal(at(1)))
+
+		got, err := r.Reception.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+		assert.Equal(t, reception_domain.InProgress, got.Status)
+		assert.Empty(t, got.CloseReason)
+		assert.Nil(t, got.ClosedAt)
+
+		open, err := r.Reception.FindLastOpenByPVZ(ctx, *pvz.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, open.ID)
+	})
+
+	t.Run("unknown pvz", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.Reception.Create(ctx, &reception_domain.Reception{
+			ID:       uuid.NewString(),
+			DateTime: at(0),
+			PVZID:    uuid.NewString(),
+			Status:   reception_domain.InProgress,
+		})
+		assert.ErrorIs(t, err, reception_domain.ErrPVZNotFound)
+	})
+
+	t.Run("one open reception per pvz", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+		openReception(t, r, *pvz.ID, at(1))
+
+		_, err := r.Reception.Create(ctx, &reception_domain.Reception{
+			ID:       uuid.NewString(),
+			DateTime: at(2),
+			PVZID:    *pvz.ID,
+			Status:   reception_domain.InProgress,
+		})
+		assert.ErrorIs(t, err, reception_domain.ErrFoundOpenedReception)
+
+		// other pvz is not affected
+		other := createPVZ(t, r, at(0))
+		openReception(t, r, *other.ID, at(2))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+
+		_, err := r.Reception.FindByID(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, reception_domain.ErrReceptionNotFound)
+
+		_, err = r.Reception.FindLastOpenByPVZ(ctx, *pvz.ID)
+		assert.ErrorIs(t, err, reception_domain.ErrNoOpenReception)
+
+		_, err = r.Reception.CloseLastReception(ctx, *pvz.ID, at(1), "")
+		assert.ErrorIs(t, err, reception_domain.ErrNoOpenReception)
+	})
+
+	t.Run("close last", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+		open := openReception(t, r, *pvz.ID, at(1))
+
+		closed, err := r.Reception.CloseLastReception(ctx, *pvz.ID, at(5), "employee@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, open.ID, closed.ID)
+		assert.Equal(t, reception_domain.Close, closed.Status)
+		assert.Equal(t, reception_domain.CloseReasonManual, closed.CloseReason)
+		require.NotNil(t, closed.ClosedAt)
+		assert.True(t, closed.ClosedAt.Equal(at(5)))
+		assert.Equal(t, "employee@example.com", closed.ClosedBy)
+		assert.Equal(t, 4*time.Minute, closed.Duration())
+
+		_, err = r.Reception.CloseLastReception(ctx, *pvz.ID, at(6), "")
+		assert.ErrorIs(t, err, reception_domain.ErrNoOpenReception)
+
+		_, err = r.Reception.FindLastOpenByPVZ(ctx, *pvz.ID)
+		assert.ErrorIs(t, err, reception_domain.ErrNoOpenReception)
+
+		// new reception can be opened after close
+		openReception(t, r, *pvz.ID, at(7))
+	})
+
+	t.Run("close stale", func(t *testing.T) {
+		r := newRepos(t)
+
+		idle := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+		active := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+		addProduct(t, r, active.ID, at(20))
+		fresh := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(15))
+
+		closedPVZ := createPVZ(t, r, at(0))
+		openReception(t, r, *closedPVZ.ID, at(1))
+		_, err := r.Reception.CloseLastReception(ctx, *closedPVZ.ID, at(2), "")
+		require.NoError(t, err)
+
+		closed, err := r.Reception.CloseStale(ctx, at(10), at(30))
+		require.NoError(t, err)
+		assert.Equal(t, []string{idle.ID}, ids(closed, receptionID))
+		assert.Equal(t, reception_domain.CloseReasonAutoClosed, closed[0].CloseReason)
+		assert.Empty(t, closed[0].ClosedBy)
+		require.NotNil(t, closed[0].ClosedAt)
+		assert.True(t, closed[0].ClosedAt.Equal(at(30)))
+
+		for _, id := range []string{active.ID, fresh.ID} {
+			got, err := r.Reception.FindByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, reception_domain.InProgress, got.Status)
+		}
+
+		closed, err = r.Reception.CloseStale(ctx, at(10), at(31))
+		require.NoError(t, err)
+		assert.NotNil(t, closed)
+		assert.Empty(t, closed)
+	})
+
+	t.Run("list by pvz", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+
+		receptions, err := r.Reception.ListByPVZ(ctx, *pvz.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, receptions)
+		assert.Empty(t, receptions)
+
+		first := openReception(t, r, *pvz.ID, at(1))
+		_, err = r.Reception.CloseLastReception(ctx, *pvz.ID, at(2), "")
+		require.NoError(t, err)
+		second := openReception(t, r, *pvz.ID, at(3))
+		openReception(t, r, *createPVZ(t, r, at(0)).ID, at(4))
+
+		receptions, err = r.Reception.ListByPVZ(ctx, *pvz.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{second.ID, first.ID}, ids(receptions, receptionID))
+		assert.Equal(t, reception_domain.CloseReasonManual, receptions[1].CloseReason)
+	})
+}
+
+func testProduct(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		r := newRepos(t)
+		reception := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+
+		created := addProduct(t, r, reception.ID, at(2))
+		assert.Equal(t, product_domain.Shoes, created.Type)
+		assert.Equal(t, reception.ID, created.ReceptionID)
+		assert.True(t, created.DateTime.Equal(at(2)))
+
+		got, err := r.Product.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+		assert.False(t, got.IsDeleted())
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		r := newRepos(t)
+		reception := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+		created := addProduct(t, r, reception.ID, at(2))
+
+		_, err := r.Product.Create(ctx, &product_domain.Product{
+			ID:          created.ID,
+			DateTime:    at(3),
+			Type:        product_domain.Electronics,
+			ReceptionID: reception.ID,
+		})
+		assert.ErrorIs(t, err, product_domain.ErrProductAlreadyExists)
+	})
+
+	t.Run("unknown reception", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.Product.Create(ctx, &product_domain.Product{
+			ID:          uuid.NewString(),
+			DateTime:    at(0),
+			Type:        product_domain.Shoes,
+			ReceptionID: uuid.NewString(),
+		})
+		assert.ErrorIs(t, err, product_domain.ErrReceptionNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := newRepos(t)
+		reception := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+
+		_, err := r.Product.GetByID(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, product_domain.ErrProductNotFound)
+
+		_, err = r.Product.GetLastByReception(ctx, reception.ID)
+		assert.ErrorIs(t, err, product_domain.ErrProductNotFound)
+
+		_, err = r.Product.DeleteLastFromReception(ctx, reception.ID, at(2), "")
+		assert.ErrorIs(t, err, product_domain.ErrNoProductsToDelete)
+
+		products, err := r.Product.ListByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, products)
+		assert.Empty(t, products)
+	})
+
+	t.Run("soft delete and restore", func(t *testing.T) {
+		r := newRepos(t)
+		reception := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+
+		first := addProduct(t, r, reception.ID, at(2))
+		second := addProduct(t, r, reception.ID, at(3))
+
+		last, err := r.Product.GetLastByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, last.ID)
+
+		products, err := r.Product.ListByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{second.ID, first.ID}, ids(products, productID))
+
+		deleted, err := r.Product.DeleteLastFromReception(ctx, reception.ID, at(4), "employee@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, deleted.ID)
+		require.True(t, deleted.IsDeleted())
+		assert.True(t, deleted.DeletedAt.Equal(at(4)))
+		assert.Equal(t, "employee@example.com", deleted.DeletedBy)
+
+		// deleted product is hidden from all reads but GetByID
+		last, err = r.Product.GetLastByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, last.ID)
+
+		products, err = r.Product.ListByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{first.ID}, ids(products, productID))
+
+		got, err := r.Product.GetByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.True(t, got.IsDeleted())
+
+		// not deleted product cant be restored
+		_, err = r.Product.Restore(ctx, first.ID)
+		assert.ErrorIs(t, err, product_domain.ErrProductNotFound)
+
+		restored, err := r.Product.Restore(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, restored.ID)
+		assert.False(t, restored.IsDeleted())
+
+		products, err = r.Product.ListByReception(ctx, reception.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{second.ID, first.ID}, ids(products, productID))
+	})
+
+	t.Run("restore into closed reception", func(t *testing.T) {
+		r := newRepos(t)
+		pvz := createPVZ(t, r, at(0))
+		reception := openReception(t, r, *pvz.ID, at(1))
+		product := addProduct(t, r, reception.ID, at(2))
+
+		_, err := r.Product.DeleteLastFromReception(ctx, reception.ID, at(3), "")
+		require.NoError(t, err)
+		_, err = r.Reception.CloseLastReception(ctx, *pvz.ID, at(4), "")
+		require.NoError(t, err)
+
+		_, err = r.Product.Restore(ctx, product.ID)
+		assert.ErrorIs(t, err, product_domain.ErrProductNotFound)
+
+		got, err := r.Product.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.True(t, got.IsDeleted())
+	})
+}
+
+func testUser(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	newUser := func(email string) *auth_domain.User {
+		return &auth_domain.User{
+			Email:    auth_domain.Email(email),
+			Password: "hash",
+			Role:     auth_domain.RoleEmployee,
+		}
+	}
+
+	t.Run("create and find", func(t *testing.T) {
+		r := newRepos(t)
+
+		created, err := r.User.Create(ctx, newUser("user@example.com"))
+		require.NoError(t, err)
+		assert.NoError(t, uuid.Validate(created.ID))
+		assert.Equal(t, auth_domain.Email("user@example.com"), created.Email)
+		assert.Equal(t, auth_domain.RoleEmployee, created.Role)
+
+		got, err := r.User.FindByEmail(ctx, "user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+		assert.Equal(t, "hash", got.Password)
+	})
+
+	t.Run("unique email", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.User.Create(ctx, newUser("user@example.com"))
+		require.NoError(t, err)
+
+		_, err = r.User.Create(ctx, newUser("user@example.com"))
+		assert.ErrorIs(t, err, auth_domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.User.FindByEmail(ctx, "missing@example.com")
+		assert.ErrorIs(t, err, auth_domain.ErrUserNotFound)
+
+		_, err = r.User.UpdateRole(ctx, "missing@example.com", auth_domain.RoleModerator)
+		assert.ErrorIs(t, err, auth_domain.ErrUserNotFound)
+
+		err = r.User.UpdatePassword(ctx, uuid.NewString(), "new-hash")
+		assert.ErrorIs(t, err, auth_domain.ErrUserNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		r := newRepos(t)
+
+		created, err := r.User.Create(ctx, newUser("user@example.com"))
+		require.NoError(t, err)
+
+		updated, err := r.User.UpdateRole(ctx, "user@example.com", auth_domain.RoleModerator)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, updated.ID)
+		assert.Equal(t, auth_domain.RoleModerator, updated.Role)
+
+		require.NoError(t, r.User.UpdatePassword(ctx, created.ID, "new-hash"))
+
+		got, err := r.User.FindByEmail(ctx, "user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, auth_domain.RoleModerator, got.Role)
+		assert.Equal(t, "new-hash", got.Password)
+	})
+}
+
+func testJournal(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	newResult := func(status sync_domain.Status) *sync_domain.Result {
+		return &sync_domain.Result{
+			OperationID: uuid.NewString(),
+			Type:        sync_domain.OpenReception,
+			PVZID:       uuid.NewString(),
+			Status:      status,
+			UserEmail:   "employee@example.com",
+			ClientTime:  at(0),
+			AppliedAt:   at(5),
+		}
+	}
+
+	t.Run("save and find", func(t *testing.T) {
+		r := newRepos(t)
+
+		applied := newResult(sync_domain.StatusApplied)
+		applied.EntityID = applied.OperationID
+		require.NoError(t, r.Journal.Save(ctx, applied))
+
+		got, err := r.Journal.Find(ctx, applied.OperationID)
+		require.NoError(t, err)
+		assert.True(t, got.ClientTime.Equal(at(0)))
+		assert.True(t, got.AppliedAt.Equal(at(5)))
+
+		// times are compared above, location depends on backend
+		got.ClientTime, got.AppliedAt = applied.ClientTime, applied.AppliedAt
+		assert.Equal(t, applied, got)
+	})
+
+	t.Run("rejected operation is saved as it came", func(t *testing.T) {
+		r := newRepos(t)
+
+		rejected := newResult(sync_domain.StatusRejected)
+		rejected.Type = "scan_barcode"
+		rejected.PVZID = "not-uuid"
+		rejected.ClientTime = time.Time{}
+		rejected.Error = "sync: invalid operation type: scan_barcode"
+		require.NoError(t, r.Journal.Save(ctx, rejected))
+
+		got, err := r.Journal.Find(ctx, rejected.OperationID)
+		require.NoError(t, err)
+		assert.Equal(t, rejected.Type, got.Type)
+		assert.Equal(t, rejected.PVZID, got.PVZID)
+		assert.Equal(t, rejected.Error, got.Error)
+		assert.True(t, got.ClientTime.IsZero())
+	})
+
+	t.Run("result is saved once", func(t *testing.T) {
+		r := newRepos(t)
+
+		first := newResult(sync_domain.StatusConflict)
+		require.NoError(t, r.Journal.Save(ctx, first))
+
+		second := *first
+		second.Status = sync_domain.StatusApplied
+		err := r.Journal.Save(ctx, &second)
+		assert.ErrorIs(t, err, sync_domain.ErrResultAlreadySaved)
+
+		got, err := r.Journal.Find(ctx, first.OperationID)
+		require.NoError(t, err)
+		assert.Equal(t, sync_domain.StatusConflict, got.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.Journal.Find(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, sync_domain.ErrResultNotFound)
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		r := newRepos(t)
+
+		saved := newResult(sync_domain.StatusApplied)
+		require.NoError(t, r.Journal.Save(ctx, saved))
+
+		err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+			got, err := r.Journal.Claim(ctx, saved.OperationID)
+			require.NoError(t, err)
+			assert.Equal(t, saved.OperationID, got.OperationID)
+
+			_, err = r.Journal.Claim(ctx, uuid.NewString())
+			assert.ErrorIs(t, err, sync_domain.ErrResultNotFound)
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("result is saved after failed savepoint", func(t *testing.T) {
+		r := newRepos(t)
+		reception := openReception(t, r, *createPVZ(t, r, at(0)).ID, at(1))
+		product := addProduct(t, r, reception.ID, at(2))
+
+		result := newResult(sync_domain.StatusApplied)
+		var added *product_domain.Product
+		err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+			err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+				var err error
+				added, err = r.Product.Create(ctx, &product_domain.Product{
+					ID: uuid.NewString(), DateTime: at(3), Type: product_domain.Shoes, ReceptionID: reception.ID,
+				})
+				require.NoError(t, err)
+
+				_, err = r.Product.Create(ctx, &product_domain.Product{
+					ID: product.ID, DateTime: at(4), Type: product_domain.Shoes, ReceptionID: reception.ID,
+				})
+				return err
+			})
+			require.ErrorIs(t, err, product_domain.ErrProductAlreadyExists)
+
+			return r.Journal.Save(ctx, result)
+		})
+		require.NoError(t, err)
+
+		_, err = r.Journal.Find(ctx, result.OperationID)
+		require.NoError(t, err)
+
+		if r.RollsBack {
+			_, err = r.Product.GetByID(ctx, added.ID)
+			assert.ErrorIs(t, err, product_domain.ErrProductNotFound)
+		}
+	})
+
+	t.Run("failed transaction saves nothing", func(t *testing.T) {
+		r := newRepos(t)
+		if !r.RollsBack {
+			t.Skip("storage does not roll back")
+		}
+
+		result := newResult(sync_domain.StatusApplied)
+		failure := errors.New("operation failed")
+		err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, r.Journal.Save(ctx, result))
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = r.Journal.Find(ctx, result.OperationID)
+		assert.ErrorIs(t, err, sync_domain.ErrResultNotFound)
+	})
+
+	t.Run("after commit callbacks", func(t *testing.T) {
+		r := newRepos(t)
+
+		var called []string
+		record := func(ctx context.Context, name string) {
+			database.AfterCommit(ctx, func() { called = append(called, name) })
+		}
+
+		failure := errors.New("operation failed")
+		err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+			record(ctx, "outer")
+
+			err := r.Tx.InTx(ctx, func(ctx context.Context) error {
+				record(ctx, "rolled back savepoint")
+				return failure
+			})
+			require.ErrorIs(t, err, failure)
+
+			err = r.Tx.InTx(ctx, func(ctx context.Context) error {
+				record(ctx, "savepoint")
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Empty(t, called, "callbacks wait for commit")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer", "savepoint"}, called)
+
+		called = nil
+		err = r.Tx.InTx(ctx, func(ctx context.Context) error {
+			record(ctx, "rolled back")
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		assert.Empty(t, called)
+	})
+}
+
+func testLoginAttempts(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	window := 15 * time.Minute
+
+	t.Run("counted up to limit, then locked", func(t *testing.T) {
+		r := newRepos(t)
+
+		for i := 1; i <= 3; i++ {
+			attempts, err := r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(i), window, 3, at(60))
+			require.NoError(t, err)
+			assert.Equal(t, i, attempts.Failures)
+			assert.Nil(t, attempts.LockedUntil)
+		}
+
+		attempts, err := r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(4), window, 3, at(64))
+		require.NoError(t, err)
+		assert.Equal(t, 4, attempts.Failures)
+		require.NotNil(t, attempts.LockedUntil)
+		assert.True(t, at(64).Equal(*attempts.LockedUntil))
+
+		// lock is not moved by attempts while locked
+		attempts, err = r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(5), window, 3, at(65))
+		require.NoError(t, err)
+		require.NotNil(t, attempts.LockedUntil)
+		assert.True(t, at(64).Equal(*attempts.LockedUntil))
+
+		got, err := r.LoginAttempts.Get(ctx, "email:a")
+		require.NoError(t, err)
+		assert.Equal(t, 5, got.Failures)
+		assert.True(t, got.IsLocked(at(6)))
+	})
+
+	t.Run("starts over after window and lockout", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(0), window, 1, at(10))
+		require.NoError(t, err)
+		attempts, err := r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(1), window, 1, at(11))
+		require.NoError(t, err)
+		require.NotNil(t, attempts.LockedUntil)
+
+		attempts, err = r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(11), window, 1, at(21))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempts.Failures)
+		assert.Nil(t, attempts.LockedUntil)
+
+		attempts, err = r.LoginAttempts.RegisterAttempt(ctx, "email:a", at(40), window, 1, at(50))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempts.Failures)
+	})
+
+	t.Run("no lockout without limit", func(t *testing.T) {
+		r := newRepos(t)
+
+		for i := range 3 {
+			attempts, err := r.LoginAttempts.RegisterAttempt(ctx, "ip:10.0.0.1", at(i), window, 0, at(60))
+			require.NoError(t, err)
+			assert.Nil(t, attempts.LockedUntil)
+		}
+	})
+
+	t.Run("release and reset", func(t *testing.T) {
+		r := newRepos(t)
+
+		_, err := r.LoginAttempts.RegisterAttempt(ctx, "ip:10.0.0.1", at(0), window, 5, at(60))
+		require.NoError(t, err)
+
+		require.NoError(t, r.LoginAttempts.Release(ctx, "ip:10.0.0.1"))
+		require.NoError(t, r.LoginAttempts.Release(ctx, "ip:10.0.0.1"))
+		require.NoError(t, r.LoginAttempts.Release(ctx, "ip:unknown"))
+
+		got, err := r.LoginAttempts.Get(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.Failures)
+
+		require.NoError(t, r.LoginAttempts.Reset(ctx, "ip:10.0.0.1"))
+
+		got, err = r.LoginAttempts.Get(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, &auth_domain.LoginAttempts{Key: "ip:10.0.0.1"}, got)
+	})
+}
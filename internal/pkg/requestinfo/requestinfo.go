@@ -0,0 +1,88 @@
+// Package requestinfo carries per-request metadata (request id, client ip, actor)
+// through context, so application services can use it without knowing
+// about transport layer.
+package requestinfo
+
+import (
+	"context"
+	"sync"
+)
+
+type key string
+
+const (
+	requestIDKey key = "request_id"
+	clientIPKey  key = "client_ip"
+	actorKey     key = "actor"
+	holderKey    key = "actor_holder"
+)
+
+// Actor is authenticated user (or service) who performs request.
+type Actor struct {
+	Email string
+	Role  string
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns request id from ctx or empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ClientIP returns client ip from ctx or empty string.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+// actorHolder is filled by WithActor called on derived contexts,
+// so actor is visible to middlewares which wrap authentication.
+type actorHolder struct {
+	mu    sync.Mutex
+	actor *Actor
+}
+
+// WithActorHolder prepares ctx of request for actor which is not authenticated yet.
+// ActorFrom(ctx) returns actor passed to WithActor on any context derived from ctx.
+func WithActorHolder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, holderKey, &actorHolder{})
+}
+
+// WithActor puts actor into ctx and into holder of ctx if there is one.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	if holder, ok := ctx.Value(holderKey).(*actorHolder); ok {
+		holder.mu.Lock()
+		holder.actor = &actor
+		holder.mu.Unlock()
+	}
+
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns actor from ctx. ok is false for anonymous requests.
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	if actor, ok := ctx.Value(actorKey).(Actor); ok {
+		return actor, true
+	}
+
+	holder, ok := ctx.Value(holderKey).(*actorHolder)
+	if !ok {
+		return Actor{}, false
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if holder.actor == nil {
+		return Actor{}, false
+	}
+
+	return *holder.actor, true
+}
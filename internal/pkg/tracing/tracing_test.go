@@ -0,0 +1,113 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	return recorder
+}
+
+func TestPgxTracer(t *testing.T) {
+	recorder := newRecorder(t)
+	tracer := tracing.NewPgxTracer()
+
+	ctx, parent := tracing.Start(context.Background(), "PVZService.ListWithReceptions")
+
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT broken"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("syntax error")})
+
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	ok, failed := spans[0], spans[1]
+	assert.Equal(t, "postgres.query", ok.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), ok.Parent().SpanID())
+	assert.Equal(t, codes.Unset, ok.Status().Code)
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "syntax error", failed.Status().Description)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := newRecorder(t)
+
+	_, ok := tracing.Start(context.Background(), "PVZService.Create")
+	tracing.End(ok, nil)
+
+	_, failed := tracing.Start(context.Background(), "PVZService.DeleteLastProduct")
+	tracing.End(failed, errors.New("no open reception found"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "no open reception found", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
+
+func TestLogger_TraceIDs(t *testing.T) {
+	newRecorder(t)
+
+	var out bytes.Buffer
+	log := logger.NewTestLoggerWithOutput(&out)
+
+	ctx, span := tracing.Start(context.Background(), "test")
+	defer span.End()
+
+	log.Info().Ctx(ctx).Msg("with trace")
+
+	assert.Contains(t, out.String(), `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+	assert.Contains(t, out.String(), `"span_id":"`+span.SpanContext().SpanID().String()+`"`)
+}
+
+func TestSetup(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Enabled: false})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), config.TracingConfig{Enabled: true, Exporter: "zipkin"})
+	assert.Error(t, err)
+
+	shutdown, err = tracing.Setup(context.Background(), config.TracingConfig{
+		Enabled:     true,
+		Exporter:    tracing.ExporterStdout,
+		SampleRatio: 1,
+		ServiceName: "pvz-avito-test",
+	})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
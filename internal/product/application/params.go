@@ -2,6 +2,7 @@ package application
 
 import (
 	"fmt"
+	"time"
 
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
@@ -12,6 +13,13 @@ type CreateParams struct {
 	Type     product_domain.ProductType
 	PVZID    string
 	UserRole auth_domain.Role
+	// AllowedPVZID is set for api keys restricted to one pvz.
+	AllowedPVZID string
+
+	// ID and DateTime are set by offline terminals,
+	// server generates them if nil.
+	ID       *string
+	DateTime *time.Time
 }
 
 func (p CreateParams) Validate() error {
@@ -23,6 +31,33 @@ func (p CreateParams) Validate() error {
 		return fmt.Errorf("%w: %w", product_domain.ErrInvalidIDFormat, err)
 	}
 
+	if p.ID != nil {
+		if err := uuid.Validate(*p.ID); err != nil {
+			return fmt.Errorf("%w: %w", product_domain.ErrInvalidIDFormat, err)
+		}
+	}
+
+	if p.UserRole != auth_domain.RoleEmployee {
+		return product_domain.ErrAccessDenied
+	}
+
+	if p.AllowedPVZID != "" && p.AllowedPVZID != p.PVZID {
+		return product_domain.ErrAccessDenied
+	}
+
+	return nil
+}
+
+type RestoreParams struct {
+	ProductID string
+	UserRole  auth_domain.Role
+}
+
+func (p RestoreParams) Validate() error {
+	if err := uuid.Validate(p.ProductID); err != nil {
+		return fmt.Errorf("%w: %w", product_domain.ErrInvalidIDFormat, err)
+	}
+
 	if p.UserRole != auth_domain.RoleEmployee {
 		return product_domain.ErrAccessDenied
 	}
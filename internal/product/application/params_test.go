@@ -12,6 +12,7 @@ import (
 
 func Test_ProductCreateParams_Validate(t *testing.T) {
 	validID := uuid.New().String()
+	badID := "bad-uuid"
 
 	tests := []struct {
 		name      string
@@ -22,6 +23,31 @@ func Test_ProductCreateParams_Validate(t *testing.T) {
 		{"invalid type", application.CreateParams{Type: "Food", PVZID: validID, UserRole: auth_domain.RoleEmployee}, true},
 		{"invalid UUID", application.CreateParams{Type: product_domain.Shoes, PVZID: "bad-uuid", UserRole: auth_domain.RoleEmployee}, true},
 		{"access denied", application.CreateParams{Type: product_domain.Shoes, PVZID: validID, UserRole: auth_domain.RoleModerator}, true},
+		{"allowed pvz", application.CreateParams{Type: product_domain.Shoes, PVZID: validID, UserRole: auth_domain.RoleEmployee, AllowedPVZID: validID}, false},
+		{"other pvz not allowed", application.CreateParams{Type: product_domain.Shoes, PVZID: validID, UserRole: auth_domain.RoleEmployee, AllowedPVZID: uuid.NewString()}, true},
+		{"client id", application.CreateParams{Type: product_domain.Shoes, PVZID: validID, UserRole: auth_domain.RoleEmployee, ID: &validID}, false},
+		{"invalid client id", application.CreateParams{Type: product_domain.Shoes, PVZID: validID, UserRole: auth_domain.RoleEmployee, ID: &badID}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func Test_ProductRestoreParams_Validate(t *testing.T) {
+	validID := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		params    application.RestoreParams
+		expectErr bool
+	}{
+		{"valid", application.RestoreParams{ProductID: validID, UserRole: auth_domain.RoleEmployee}, false},
+		{"invalid UUID", application.RestoreParams{ProductID: "bad-uuid", UserRole: auth_domain.RoleEmployee}, true},
+		{"access denied", application.RestoreParams{ProductID: validID, UserRole: auth_domain.RoleModerator}, true},
 	}
 
 	for _, tt := range tests {
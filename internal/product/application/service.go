@@ -5,8 +5,10 @@ import (
 	"errors"
 	"time"
 
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	"github.com/google/uuid"
@@ -15,32 +17,42 @@ import (
 type ProductService struct {
 	productRepo   product_domain.ProductRepository
 	receptionRepo reception_domain.ReceptionRepository
+	auditor       audit_domain.Auditor
 
 	log *logger.ZerologLogger
 }
 
-func NewProductService(productRepo product_domain.ProductRepository, receptionRepo reception_domain.ReceptionRepository, l *logger.ZerologLogger) *ProductService {
+func NewProductService(
+	productRepo product_domain.ProductRepository,
+	receptionRepo reception_domain.ReceptionRepository,
+	auditor audit_domain.Auditor,
+	l *logger.ZerologLogger,
+) *ProductService {
 	return &ProductService{
 		productRepo:   productRepo,
 		receptionRepo: receptionRepo,
+		auditor:       auditor,
 		log:           l,
 	}
 }
 
-func (s *ProductService) Create(ctx context.Context, params CreateParams) (*product_domain.Product, error) {
+func (s *ProductService) Create(ctx context.Context, params CreateParams) (_ *product_domain.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Create")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("CreateProduct")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("CreateProduct")
 		return nil, err
 	}
 
 	lastReception, err := s.receptionRepo.FindLastOpenByPVZ(ctx, params.PVZID)
 	if err != nil && !errors.Is(err, reception_domain.ErrNoOpenReception) {
-		s.log.Error().Any("params", params).Err(err).Msg("Error finding last open reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error finding last open reception")
 		return nil, err
 	}
 
 	if lastReception == nil {
-		s.log.Error().Any("params", params).Err(err).Msg("No open reception found")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("No open reception found")
 		return nil, reception_domain.ErrNoOpenReception
 	}
 
@@ -50,15 +62,77 @@ func (s *ProductService) Create(ctx context.Context, params CreateParams) (*prod
 		Type:        params.Type,
 		ReceptionID: lastReception.ID,
 	}
+	if params.ID != nil {
+		product.ID = *params.ID
+	}
+	if params.DateTime != nil {
+		product.DateTime = *params.DateTime
+	}
 
 	created, err := s.productRepo.Create(ctx, product)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("product", product).Err(err).Msg("Error creating product")
+		s.log.Error().Ctx(ctx).Any("params", params).Any("product", product).Err(err).Msg("Error creating product")
 		return nil, err
 	}
 
 	metrics.ProductsAddedTotal.Inc()
 
-	s.log.Info().Any("params", params).Any("product", created).Msg("CreateProduct successful")
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionProductAdd,
+		EntityType: audit_domain.EntityProduct,
+		EntityID:   created.ID,
+		After:      created,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("product", created).Msg("CreateProduct successful")
 	return created, nil
 }
+
+func (s *ProductService) Restore(ctx context.Context, params RestoreParams) (_ *product_domain.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("RestoreProduct")
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, params.ProductID)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error getting product")
+		return nil, err
+	}
+
+	if !product.IsDeleted() {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(product_domain.ErrProductNotDeleted).Msg("Product is not deleted")
+		return nil, product_domain.ErrProductNotDeleted
+	}
+
+	reception, err := s.receptionRepo.FindByID(ctx, product.ReceptionID)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Any("product", product).Err(err).Msg("Error finding reception of product")
+		return nil, err
+	}
+
+	if reception.Status != reception_domain.InProgress {
+		s.log.Error().Ctx(ctx).Any("params", params).Any("reception", reception).Err(product_domain.ErrReceptionClosed).Msg("Reception is closed")
+		return nil, product_domain.ErrReceptionClosed
+	}
+
+	restored, err := s.productRepo.Restore(ctx, product.ID)
+	if err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Any("product", product).Err(err).Msg("Error restoring product")
+		return nil, err
+	}
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionProductRestore,
+		EntityType: audit_domain.EntityProduct,
+		EntityID:   restored.ID,
+		Before:     product,
+		After:      restored,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("product", restored).Msg("RestoreProduct successful")
+	return restored, nil
+}
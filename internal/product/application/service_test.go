@@ -3,7 +3,9 @@ package application_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/product/application"
@@ -131,7 +133,10 @@ func TestProductCreate(t *testing.T) {
 
 			logger := logger.NewTestLogger()
 
-			service := application.NewProductService(productRepo, receptionRepo, logger)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewProductService(productRepo, receptionRepo, auditor, logger)
 			_, err := service.Create(context.Background(), tt.params)
 
 			if tt.expectErr != nil {
@@ -143,3 +148,120 @@ func TestProductCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestProductRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productID := uuid.NewString()
+	receptionID := uuid.NewString()
+	deletedAt := time.Now()
+
+	validParams := application.RestoreParams{
+		ProductID: productID,
+		UserRole:  auth_domain.RoleEmployee,
+	}
+
+	deleted := &product_domain.Product{
+		ID:          productID,
+		Type:        product_domain.Shoes,
+		ReceptionID: receptionID,
+		DeletedAt:   &deletedAt,
+		DeletedBy:   "emp@example.com",
+	}
+
+	tests := []struct {
+		name      string
+		params    application.RestoreParams
+		mockSetup func(*reception_mocks.MockReceptionRepository, *product_mocks.MockProductRepository)
+		expectErr error
+	}{
+		{
+			name:   "successful restore",
+			params: validParams,
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				p.EXPECT().GetByID(gomock.Any(), productID).Return(deleted, nil)
+
+				r.EXPECT().
+					FindByID(gomock.Any(), receptionID).
+					Return(&reception_domain.Reception{ID: receptionID, Status: reception_domain.InProgress}, nil)
+
+				p.EXPECT().
+					Restore(gomock.Any(), productID).
+					Return(&product_domain.Product{ID: productID, Type: product_domain.Shoes, ReceptionID: receptionID}, nil)
+			},
+		},
+		{
+			name:   "product not deleted",
+			params: validParams,
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				p.EXPECT().
+					GetByID(gomock.Any(), productID).
+					Return(&product_domain.Product{ID: productID, ReceptionID: receptionID}, nil)
+			},
+			expectErr: product_domain.ErrProductNotDeleted,
+		},
+		{
+			name:   "product not found",
+			params: validParams,
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				p.EXPECT().GetByID(gomock.Any(), productID).Return(nil, product_domain.ErrProductNotFound)
+			},
+			expectErr: product_domain.ErrProductNotFound,
+		},
+		{
+			name:   "reception closed",
+			params: validParams,
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				p.EXPECT().GetByID(gomock.Any(), productID).Return(deleted, nil)
+
+				r.EXPECT().
+					FindByID(gomock.Any(), receptionID).
+					Return(&reception_domain.Reception{ID: receptionID, Status: reception_domain.Close}, nil)
+			},
+			expectErr: product_domain.ErrReceptionClosed,
+		},
+		{
+			name:   "reception closed concurrently",
+			params: validParams,
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				p.EXPECT().GetByID(gomock.Any(), productID).Return(deleted, nil)
+
+				r.EXPECT().
+					FindByID(gomock.Any(), receptionID).
+					Return(&reception_domain.Reception{ID: receptionID, Status: reception_domain.InProgress}, nil)
+
+				p.EXPECT().Restore(gomock.Any(), productID).Return(nil, product_domain.ErrProductNotFound)
+			},
+			expectErr: product_domain.ErrProductNotFound,
+		},
+		{
+			name:      "access denied",
+			params:    application.RestoreParams{ProductID: productID, UserRole: auth_domain.RoleModerator},
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {},
+			expectErr: product_domain.ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receptionRepo := reception_mocks.NewMockReceptionRepository(ctrl)
+			productRepo := product_mocks.NewMockProductRepository(ctrl)
+			tt.mockSetup(receptionRepo, productRepo)
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewProductService(productRepo, receptionRepo, auditor, logger.NewTestLogger())
+			restored, err := service.Restore(context.Background(), tt.params)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.False(t, restored.IsDeleted())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/gateway"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ProductService interface {
+	Create(ctx context.Context, product application.CreateParams) (*product_domain.Product, error)
+	Restore(ctx context.Context, params application.RestoreParams) (*product_domain.Product, error)
+}
+
+// GRPCHandler implements ProductService, it is also served on REST by gateway.
+type GRPCHandler struct {
+	pb.UnimplementedProductServiceServer
+	svc ProductService
+}
+
+func NewGRPCHandler(svc ProductService) *GRPCHandler {
+	return &GRPCHandler{
+		svc: svc,
+	}
+}
+
+func (h *GRPCHandler) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.Product, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.CreateParams{
+		Type:         product_domain.ProductType(req.GetType()),
+		PVZID:        req.GetPvzId(),
+		UserRole:     auth_domain.Role(claims.Role),
+		AllowedPVZID: claims.PVZID,
+	}
+
+	product, err := h.svc.Create(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	gateway.SetStatus(ctx, http.StatusCreated)
+
+	return newProduct(product), nil
+}
+
+func (h *GRPCHandler) RestoreProduct(ctx context.Context, req *pb.RestoreProductRequest) (*pb.Product, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.RestoreParams{
+		ProductID: req.GetProductId(),
+		UserRole:  auth_domain.Role(claims.Role),
+	}
+
+	product, err := h.svc.Restore(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	return newProduct(product), nil
+}
+
+func newProduct(p *product_domain.Product) *pb.Product {
+	return &pb.Product{
+		Id:          p.ID,
+		DateTime:    timestamppb.New(p.DateTime),
+		Type:        p.Type.String(),
+		ReceptionId: p.ReceptionID,
+	}
+}
@@ -12,6 +12,7 @@ import (
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_grpc "github.com/0x0FACED/pvz-avito/internal/product/delivery/grpc"
 	product_http "github.com/0x0FACED/pvz-avito/internal/product/delivery/http"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	"github.com/0x0FACED/pvz-avito/internal/product/mocks"
@@ -26,7 +27,7 @@ func TestProductHandler_Create(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
 		name           string
-		request        product_http.CreateRequest
+		request        createRequest
 		userRole       string
 		mockSetup      func(*mocks.MockProductService)
 		expectedStatus int
@@ -34,7 +35,7 @@ func TestProductHandler_Create(t *testing.T) {
 	}{
 		{
 			name: "successful product creation",
-			request: product_http.CreateRequest{
+			request: createRequest{
 				Type:  "электроника",
 				PVZID: "pvz-123",
 			},
@@ -58,7 +59,7 @@ func TestProductHandler_Create(t *testing.T) {
 		},
 		{
 			name: "access denied for moderator",
-			request: product_http.CreateRequest{
+			request: createRequest{
 				Type:  "электроника",
 				PVZID: "pvz-123",
 			},
@@ -72,7 +73,7 @@ func TestProductHandler_Create(t *testing.T) {
 		},
 		{
 			name: "invalid product type",
-			request: product_http.CreateRequest{
+			request: createRequest{
 				Type:  "invalidtype",
 				PVZID: "pvz-123",
 			},
@@ -82,11 +83,11 @@ func TestProductHandler_Create(t *testing.T) {
 					Return(nil, product_domain.ErrInvalidProductType)
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "invalid request",
+			expectErr:      "invalid product type",
 		},
 		{
 			name: "reception not found",
-			request: product_http.CreateRequest{
+			request: createRequest{
 				Type:  "электроника",
 				PVZID: "pvz-123",
 			},
@@ -96,7 +97,7 @@ func TestProductHandler_Create(t *testing.T) {
 					Return(nil, product_domain.ErrReceptionNotFound)
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "reception not found",
+			expectErr:      "no open reception found",
 		},
 	}
 
@@ -107,7 +108,7 @@ func TestProductHandler_Create(t *testing.T) {
 				tt.mockSetup(productSvcMock)
 			}
 
-			handler := product_http.NewHandler(productSvcMock)
+			handler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvcMock))
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest(nethttp.MethodPost, "/products", bytes.NewReader(body))
@@ -118,7 +119,7 @@ func TestProductHandler_Create(t *testing.T) {
 			req = req.WithContext(ctx)
 			rec := httptest.NewRecorder()
 
-			handler.Create(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
@@ -127,7 +128,7 @@ func TestProductHandler_Create(t *testing.T) {
 				_ = json.NewDecoder(rec.Body).Decode(&errResp)
 				assert.Contains(t, errResp.Error(), tt.expectErr)
 			} else {
-				var resp product_http.CreateResponse
+				var resp createResponse
 				_ = json.NewDecoder(rec.Body).Decode(&resp)
 				assert.NotEmpty(t, resp.ID)
 				assert.Equal(t, tt.request.Type, resp.Type)
@@ -142,12 +143,12 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		productSvcMock := mocks.NewMockProductService(ctrl)
-		handler := product_http.NewHandler(productSvcMock)
+		handler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvcMock))
 
 		req := httptest.NewRequest(nethttp.MethodPost, "/products", bytes.NewReader([]byte("{invalid}")))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusBadRequest, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -157,9 +158,9 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("missing claims in context", func(t *testing.T) {
 		productSvcMock := mocks.NewMockProductService(ctrl)
-		handler := product_http.NewHandler(productSvcMock)
+		handler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvcMock))
 
-		validRequest := product_http.CreateRequest{
+		validRequest := createRequest{
 			Type:  "электроника",
 			PVZID: "pvz-123",
 		}
@@ -168,7 +169,7 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 		req := httptest.NewRequest(nethttp.MethodPost, "/products", bytes.NewReader(body))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -178,9 +179,9 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("invalid claims type in context", func(t *testing.T) {
 		productSvcMock := mocks.NewMockProductService(ctrl)
-		handler := product_http.NewHandler(productSvcMock)
+		handler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvcMock))
 
-		validRequest := product_http.CreateRequest{
+		validRequest := createRequest{
 			Type:  "электроника",
 			PVZID: "pvz-123",
 		}
@@ -190,7 +191,7 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 		req := httptest.NewRequest(nethttp.MethodPost, "/products", bytes.NewReader(body)).WithContext(ctx)
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -198,3 +199,137 @@ func TestProductHandler_Create_ErrorCases(t *testing.T) {
 		assert.Equal(t, "access denied", errResp.Error())
 	})
 }
+
+func TestProductHandler_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	tests := []struct {
+		name           string
+		productID      string
+		userRole       string
+		mockSetup      func(*mocks.MockProductService)
+		expectedStatus int
+		expectErr      string
+	}{
+		{
+			name:      "successful restore",
+			productID: "product-123",
+			userRole:  "employee",
+			mockSetup: func(m *mocks.MockProductService) {
+				m.EXPECT().Restore(
+					gomock.Any(),
+					application.RestoreParams{
+						ProductID: "product-123",
+						UserRole:  auth_domain.RoleEmployee,
+					},
+				).Return(&product_domain.Product{
+					ID:          "product-123",
+					DateTime:    now,
+					Type:        product_domain.Shoes,
+					ReceptionID: "rec-123",
+				}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+		},
+		{
+			name:      "access denied for moderator",
+			productID: "product-123",
+			userRole:  "moderator",
+			mockSetup: func(m *mocks.MockProductService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).
+					Return(nil, product_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectErr:      "access denied",
+		},
+		{
+			name:      "product not found",
+			productID: "product-123",
+			userRole:  "employee",
+			mockSetup: func(m *mocks.MockProductService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).
+					Return(nil, product_domain.ErrProductNotFound)
+			},
+			expectedStatus: nethttp.StatusNotFound,
+			expectErr:      "product not found",
+		},
+		{
+			name:      "product not deleted",
+			productID: "product-123",
+			userRole:  "employee",
+			mockSetup: func(m *mocks.MockProductService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).
+					Return(nil, product_domain.ErrProductNotDeleted)
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "product is not deleted",
+		},
+		{
+			name:      "reception closed",
+			productID: "product-123",
+			userRole:  "employee",
+			mockSetup: func(m *mocks.MockProductService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).
+					Return(nil, product_domain.ErrReceptionClosed)
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "reception already closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productSvcMock := mocks.NewMockProductService(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(productSvcMock)
+			}
+
+			handler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvcMock))
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/products/"+tt.productID+"/restore", nil)
+
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Role: tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			serve(handler, rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectErr != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Contains(t, errResp.Error(), tt.expectErr)
+			} else {
+				var resp createResponse
+				_ = json.NewDecoder(rec.Body).Decode(&resp)
+				assert.Equal(t, tt.productID, resp.ID)
+			}
+		})
+	}
+}
+
+// serve sends request through routes of handler, as server mux does.
+func serve(handler *product_http.Handler, w nethttp.ResponseWriter, req *nethttp.Request) {
+	mux := nethttp.NewServeMux()
+	handler.RegisterRoutes(mux)
+	mux.ServeHTTP(w, req)
+}
+
+// json of REST clients, it must keep working with gateway.
+
+type createRequest struct {
+	Type  string `json:"type"`
+	PVZID string `json:"pvzId"`
+}
+
+type createResponse struct {
+	ID          string    `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	Type        string    `json:"type"`
+	ReceptionID string    `json:"receptionId"`
+}
@@ -21,4 +21,12 @@ type Product struct {
 	DateTime    time.Time
 	Type        ProductType
 	ReceptionID string
+
+	// soft deletion info, DeletedAt is nil for alive products
+	DeletedAt *time.Time
+	DeletedBy string
+}
+
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
 }
@@ -3,12 +3,17 @@ package domain
 import "errors"
 
 var (
-	ErrProductNotFound  = errors.New("product: product not found")
-	ErrInternalDatabase = errors.New("product: internal database error")
+	ErrProductNotFound      = errors.New("product: product not found")
+	ErrProductAlreadyExists = errors.New("product: product already exists")
+	ErrInternalDatabase     = errors.New("product: internal database error")
 
 	ErrReceptionNotFound  = errors.New("product: reception not found")
 	ErrNoProductsToDelete = errors.New("product: no products to delete")
 
+	// restore
+	ErrProductNotDeleted = errors.New("product: product is not deleted")
+	ErrReceptionClosed   = errors.New("product: reception is already closed")
+
 	ErrInvalidProductType = errors.New("product: invalid product type")
 	ErrInvalidIDFormat    = errors.New("product: invalid id format")
 	ErrAccessDenied       = errors.New("product: only employee can add new products")
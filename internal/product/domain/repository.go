@@ -1,11 +1,21 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// ProductRepository hides soft deleted products from all reads
+// except GetByID, which is used to restore them.
+// Contract is checked by repotest.Run for every storage backend,
+// lists are ordered newest first and are empty (not nil) if nothing is found.
 type ProductRepository interface {
 	Create(ctx context.Context, product *Product) (*Product, error)
 	GetByID(ctx context.Context, id string) (*Product, error)
 	GetLastByReception(ctx context.Context, receptionID string) (*Product, error)
-	DeleteLastFromReception(ctx context.Context, receptionID string) error
+	// DeleteLastFromReception soft deletes last alive product of reception and returns it.
+	DeleteLastFromReception(ctx context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*Product, error)
+	// Restore undoes soft deletion if reception of product is still in progress.
+	Restore(ctx context.Context, id string) (*Product, error)
 	ListByReception(ctx context.Context, receptionID string) ([]*Product, error)
 }
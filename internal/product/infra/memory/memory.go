@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// ProductMemoryRepository keeps products in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type ProductMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewProductMemoryRepository(db *memdb.DB) *ProductMemoryRepository {
+	return &ProductMemoryRepository{db: db}
+}
+
+func (r *ProductMemoryRepository) Create(_ context.Context, product *product_domain.Product) (*product_domain.Product, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	if r.db.Reception(product.ReceptionID) == nil {
+		return nil, fmt.Errorf("%w: id %s", product_domain.ErrReceptionNotFound, product.ReceptionID)
+	}
+	if r.db.Product(product.ID) != nil {
+		return nil, fmt.Errorf("%w: id %s", product_domain.ErrProductAlreadyExists, product.ID)
+	}
+
+	row := &product_domain.Product{
+		ID:          product.ID,
+		DateTime:    product.DateTime,
+		Type:        product.Type,
+		ReceptionID: product.ReceptionID,
+	}
+	r.db.Products = append(r.db.Products, row)
+
+	return cloneProduct(row), nil
+}
+
+func (r *ProductMemoryRepository) GetByID(_ context.Context, id string) (*product_domain.Product, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	row := r.db.Product(id)
+	if row == nil {
+		return nil, fmt.Errorf("%w: id %s", product_domain.ErrProductNotFound, id)
+	}
+
+	return cloneProduct(row), nil
+}
+
+func (r *ProductMemoryRepository) GetLastByReception(_ context.Context, receptionID string) (*product_domain.Product, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	products := r.db.AliveProducts(receptionID)
+	if len(products) == 0 {
+		return nil, fmt.Errorf("%w: reception %s", product_domain.ErrProductNotFound, receptionID)
+	}
+
+	return cloneProduct(products[0]), nil
+}
+
+func (r *ProductMemoryRepository) DeleteLastFromReception(_ context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*product_domain.Product, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	products := r.db.AliveProducts(receptionID)
+	if len(products) == 0 {
+		return nil, fmt.Errorf("%w: no products found for reception_id: %s", product_domain.ErrNoProductsToDelete, receptionID)
+	}
+
+	row := products[0]
+	row.DeletedAt = &deletedAt
+	row.DeletedBy = deletedBy
+
+	return cloneProduct(row), nil
+}
+
+func (r *ProductMemoryRepository) Restore(_ context.Context, id string) (*product_domain.Product, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := r.db.Product(id)
+	if row == nil || !row.IsDeleted() {
+		return nil, fmt.Errorf("%w: no deleted product %s in open reception", product_domain.ErrProductNotFound, id)
+	}
+
+	// product cant be restored into closed reception
+	if reception := r.db.Reception(row.ReceptionID); reception == nil || reception.Status != reception_domain.InProgress {
+		return nil, fmt.Errorf("%w: no deleted product %s in open reception", product_domain.ErrProductNotFound, id)
+	}
+
+	row.DeletedAt = nil
+	row.DeletedBy = ""
+
+	return cloneProduct(row), nil
+}
+
+func (r *ProductMemoryRepository) ListByReception(_ context.Context, receptionID string) ([]*product_domain.Product, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	products := make([]*product_domain.Product, 0)
+	for _, row := range r.db.AliveProducts(receptionID) {
+		products = append(products, cloneProduct(row))
+	}
+
+	return products, nil
+}
+
+func cloneProduct(p *product_domain.Product) *product_domain.Product {
+	c := *p
+	if p.DeletedAt != nil {
+		deletedAt := *p.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+
+	return &c
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	pgx "github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -37,13 +39,15 @@ func (r *ProductPostgresRepository) Create(ctx context.Context, product *product
 	created.Type = product.Type
 	created.ReceptionID = product.ReceptionID
 
-	err := r.pool.QueryRow(ctx, query, args).Scan(&created.ID, &created.DateTime)
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(&created.ID, &created.DateTime)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			switch pgErr.Code {
 			case "23503":
 				return nil, fmt.Errorf("%w: %w", product_domain.ErrReceptionNotFound, err)
+			case "23505":
+				return nil, fmt.Errorf("%w: %w", product_domain.ErrProductAlreadyExists, err)
 			}
 		}
 		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
@@ -54,7 +58,7 @@ func (r *ProductPostgresRepository) Create(ctx context.Context, product *product
 
 func (r *ProductPostgresRepository) GetByID(ctx context.Context, id string) (*product_domain.Product, error) {
 	query := `
-		SELECT id, date_time, type, reception_id
+		SELECT id, date_time, type, reception_id, deleted_at, COALESCE(deleted_by, '')
 		FROM avito.products
 		WHERE id = @id
 	`
@@ -64,11 +68,13 @@ func (r *ProductPostgresRepository) GetByID(ctx context.Context, id string) (*pr
 	}
 
 	product := product_domain.Product{}
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&product.ID,
 		&product.DateTime,
 		&product.Type,
 		&product.ReceptionID,
+		&product.DeletedAt,
+		&product.DeletedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -84,7 +90,7 @@ func (r *ProductPostgresRepository) GetLastByReception(ctx context.Context, rece
 	query := `
 		SELECT id, date_time, type, reception_id
 		FROM avito.products
-		WHERE reception_id = @reception_id
+		WHERE reception_id = @reception_id AND deleted_at IS NULL
 		ORDER BY date_time DESC
 		LIMIT 1
 	`
@@ -94,7 +100,7 @@ func (r *ProductPostgresRepository) GetLastByReception(ctx context.Context, rece
 	}
 
 	product := product_domain.Product{}
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&product.ID,
 		&product.DateTime,
 		&product.Type,
@@ -110,52 +116,86 @@ func (r *ProductPostgresRepository) GetLastByReception(ctx context.Context, rece
 	return &product, nil
 }
 
-func (r *ProductPostgresRepository) DeleteLastFromReception(ctx context.Context, receptionID string) error {
-	var count int
-
-	checkQuery := `
-		SELECT COUNT(*) 
-		FROM avito.products 
-		WHERE reception_id = @reception_id
+func (r *ProductPostgresRepository) DeleteLastFromReception(ctx context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*product_domain.Product, error) {
+	query := `
+		UPDATE avito.products
+		SET deleted_at = @deleted_at, deleted_by = NULLIF(@deleted_by, '')
+		WHERE id = (
+			SELECT id
+			FROM avito.products
+			WHERE reception_id = @reception_id AND deleted_at IS NULL
+			ORDER BY date_time DESC
+			LIMIT 1
+			FOR UPDATE
+		)
+		RETURNING id, date_time, type, reception_id, deleted_at, COALESCE(deleted_by, '')
 	`
 
 	args := pgx.NamedArgs{
 		"reception_id": receptionID,
+		"deleted_at":   deletedAt,
+		"deleted_by":   deletedBy,
 	}
 
-	err := r.pool.QueryRow(ctx, checkQuery, args).Scan(&count)
+	product := product_domain.Product{}
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+		&product.DeletedAt,
+		&product.DeletedBy,
+	)
 	if err != nil {
-		return fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no products found for reception_id: %s", product_domain.ErrNoProductsToDelete, receptionID)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
 	}
 
-	if count == 0 {
-		return fmt.Errorf("%w: no products found for reception_id: %s", product_domain.ErrNoProductsToDelete, receptionID)
-	}
+	return &product, nil
+}
 
+func (r *ProductPostgresRepository) Restore(ctx context.Context, id string) (*product_domain.Product, error) {
+	// reception status is checked in the same statement,
+	// so product cant be restored into reception closed concurrently
 	query := `
-		DELETE FROM avito.products
-		WHERE id = (
-			SELECT id
-			FROM avito.products
-			WHERE reception_id = @reception_id
-			ORDER BY date_time DESC
-			LIMIT 1
-		)
+		UPDATE avito.products p
+		SET deleted_at = NULL, deleted_by = NULL
+		FROM avito.receptions r
+		WHERE p.id = @id
+		  AND p.deleted_at IS NOT NULL
+		  AND r.id = p.reception_id
+		  AND r.status = 'in_progress'
+		RETURNING p.id, p.date_time, p.type, p.reception_id
 	`
 
-	_, err = r.pool.Exec(ctx, query, args)
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	product := product_domain.Product{}
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+	)
 	if err != nil {
-		return fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no deleted product %s in open reception", product_domain.ErrProductNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
 	}
 
-	return nil
+	return &product, nil
 }
 
 func (r *ProductPostgresRepository) ListByReception(ctx context.Context, receptionID string) ([]*product_domain.Product, error) {
 	query := `
 		SELECT id, date_time, type, reception_id
 		FROM avito.products
-		WHERE reception_id = @reception_id
+		WHERE reception_id = @reception_id AND deleted_at IS NULL
 		ORDER BY date_time DESC
 	`
 
@@ -163,13 +203,13 @@ func (r *ProductPostgresRepository) ListByReception(ctx context.Context, recepti
 		"reception_id": receptionID,
 	}
 
-	rows, err := r.pool.Query(ctx, query, args)
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
 	}
 	defer rows.Close()
 
-	var products []*product_domain.Product
+	products := make([]*product_domain.Product, 0)
 	for rows.Next() {
 		var product product_domain.Product
 		err := rows.Scan(
@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type ProductSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewProductSQLiteRepository(db *sql.DB) *ProductSQLiteRepository {
+	return &ProductSQLiteRepository{db: db}
+}
+
+func (r *ProductSQLiteRepository) Create(ctx context.Context, product *product_domain.Product) (*product_domain.Product, error) {
+	query := `
+		INSERT INTO products (id, date_time, type, reception_id)
+		VALUES (@id, @date_time, @type, @reception_id)
+		RETURNING id, date_time
+	`
+
+	var created product_domain.Product
+	created.Type = product.Type
+	created.ReceptionID = product.ReceptionID
+
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query,
+		sql.Named("id", product.ID),
+		sql.Named("date_time", database.SQLiteTime(product.DateTime)),
+		sql.Named("type", product.Type.String()),
+		sql.Named("reception_id", product.ReceptionID),
+	).Scan(&created.ID, &created.DateTime)
+	if err != nil {
+		switch database.SQLiteErrorCode(err) {
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return nil, fmt.Errorf("%w: %w", product_domain.ErrReceptionNotFound, err)
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return nil, fmt.Errorf("%w: %w", product_domain.ErrProductAlreadyExists, err)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return &created, nil
+}
+
+func (r *ProductSQLiteRepository) GetByID(ctx context.Context, id string) (*product_domain.Product, error) {
+	query := `
+		SELECT id, date_time, type, reception_id, deleted_at, COALESCE(deleted_by, '')
+		FROM products
+		WHERE id = @id
+	`
+
+	product := product_domain.Product{}
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("id", id)).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+		&product.DeletedAt,
+		&product.DeletedBy,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", product_domain.ErrProductNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return &product, nil
+}
+
+func (r *ProductSQLiteRepository) GetLastByReception(ctx context.Context, receptionID string) (*product_domain.Product, error) {
+	query := `
+		SELECT id, date_time, type, reception_id
+		FROM products
+		WHERE reception_id = @reception_id AND deleted_at IS NULL
+		ORDER BY date_time DESC
+		LIMIT 1
+	`
+
+	product := product_domain.Product{}
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("reception_id", receptionID)).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", product_domain.ErrProductNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return &product, nil
+}
+
+func (r *ProductSQLiteRepository) DeleteLastFromReception(ctx context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*product_domain.Product, error) {
+	// no FOR UPDATE in sqlite, statement runs under database write lock
+	query := `
+		UPDATE products
+		SET deleted_at = @deleted_at, deleted_by = NULLIF(@deleted_by, '')
+		WHERE id = (
+			SELECT id
+			FROM products
+			WHERE reception_id = @reception_id AND deleted_at IS NULL
+			ORDER BY date_time DESC
+			LIMIT 1
+		)
+		RETURNING id, date_time, type, reception_id, deleted_at, COALESCE(deleted_by, '')
+	`
+
+	product := product_domain.Product{}
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query,
+		sql.Named("reception_id", receptionID),
+		sql.Named("deleted_at", database.SQLiteTime(deletedAt)),
+		sql.Named("deleted_by", deletedBy),
+	).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+		&product.DeletedAt,
+		&product.DeletedBy,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no products found for reception_id: %s", product_domain.ErrNoProductsToDelete, receptionID)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return &product, nil
+}
+
+func (r *ProductSQLiteRepository) Restore(ctx context.Context, id string) (*product_domain.Product, error) {
+	// reception status is checked in the same statement,
+	// so product cant be restored into reception closed concurrently
+	query := `
+		UPDATE products
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = @id
+		  AND deleted_at IS NOT NULL
+		  AND reception_id IN (SELECT id FROM receptions WHERE status = 'in_progress')
+		RETURNING id, date_time, type, reception_id
+	`
+
+	product := product_domain.Product{}
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("id", id)).Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.Type,
+		&product.ReceptionID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no deleted product %s in open reception", product_domain.ErrProductNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return &product, nil
+}
+
+func (r *ProductSQLiteRepository) ListByReception(ctx context.Context, receptionID string) ([]*product_domain.Product, error) {
+	query := `
+		SELECT id, date_time, type, reception_id
+		FROM products
+		WHERE reception_id = @reception_id AND deleted_at IS NULL
+		ORDER BY date_time DESC
+	`
+
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query, sql.Named("reception_id", receptionID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	products := make([]*product_domain.Product, 0)
+	for rows.Next() {
+		var product product_domain.Product
+		err := rows.Scan(
+			&product.ID,
+			&product.DateTime,
+			&product.Type,
+			&product.ReceptionID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+		}
+		products = append(products, &product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", product_domain.ErrInternalDatabase, err)
+	}
+
+	return products, nil
+}
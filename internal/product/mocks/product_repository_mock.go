@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -57,17 +58,18 @@ func (mr *MockProductRepositoryMockRecorder) Create(ctx, product any) *gomock.Ca
 }
 
 // DeleteLastFromReception mocks base method.
-func (m *MockProductRepository) DeleteLastFromReception(ctx context.Context, receptionID string) error {
+func (m *MockProductRepository) DeleteLastFromReception(ctx context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*domain.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLastFromReception", ctx, receptionID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteLastFromReception", ctx, receptionID, deletedAt, deletedBy)
+	ret0, _ := ret[0].(*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteLastFromReception indicates an expected call of DeleteLastFromReception.
-func (mr *MockProductRepositoryMockRecorder) DeleteLastFromReception(ctx, receptionID any) *gomock.Call {
+func (mr *MockProductRepositoryMockRecorder) DeleteLastFromReception(ctx, receptionID, deletedAt, deletedBy any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastFromReception", reflect.TypeOf((*MockProductRepository)(nil).DeleteLastFromReception), ctx, receptionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastFromReception", reflect.TypeOf((*MockProductRepository)(nil).DeleteLastFromReception), ctx, receptionID, deletedAt, deletedBy)
 }
 
 // GetByID mocks base method.
@@ -114,3 +116,18 @@ func (mr *MockProductRepositoryMockRecorder) ListByReception(ctx, receptionID an
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByReception", reflect.TypeOf((*MockProductRepository)(nil).ListByReception), ctx, receptionID)
 }
+
+// Restore mocks base method.
+func (m *MockProductRepository) Restore(ctx context.Context, id string) (*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductRepository)(nil).Restore), ctx, id)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/product/delivery/http/handler.go
+// Source: internal/product/delivery/grpc/handler.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/product/delivery/http/handler.go -destination=internal/product/mocks/product_service_mock.go -package=mocks
+//	mockgen -source=internal/product/delivery/grpc/handler.go -destination=internal/product/mocks/product_service_mock.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -56,3 +56,18 @@ func (mr *MockProductServiceMockRecorder) Create(ctx, product any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductService)(nil).Create), ctx, product)
 }
+
+// Restore mocks base method.
+func (m *MockProductService) Restore(ctx context.Context, params application.RestoreParams) (*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, params)
+	ret0, _ := ret[0].(*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductServiceMockRecorder) Restore(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductService)(nil).Restore), ctx, params)
+}
@@ -40,6 +40,9 @@ type ListWithReceptionsParams struct {
 	EndDate   *time.Time
 	Page      *int
 	Limit     *int
+	// AllowedPVZID is set for api keys restricted to one pvz,
+	// paging runs over its rows only.
+	AllowedPVZID string
 }
 
 func (p *ListWithReceptionsParams) Validate() error {
@@ -62,9 +65,35 @@ func (p *ListWithReceptionsParams) Validate() error {
 	return nil
 }
 
+// StreamPVZs reads pvz from storage by pages of PageSize.
+const (
+	DefaultStreamPageSize = 100
+	MaxStreamPageSize     = 1000
+)
+
+type StreamParams struct {
+	// PageSize is DefaultStreamPageSize if 0.
+	PageSize       int
+	WithReceptions bool
+	// AllowedPVZID is set for api keys restricted to one pvz,
+	// only this pvz is streamed.
+	AllowedPVZID string
+}
+
+func (p StreamParams) Validate() error {
+	if p.PageSize < 0 || p.PageSize > MaxStreamPageSize {
+		return fmt.Errorf("%w: %d", pvz_domain.ErrInvalidPageSize, p.PageSize)
+	}
+
+	return nil
+}
+
 type CloseLastReceptionParams struct {
-	PVZID    string
-	UserRole auth_domain.Role
+	PVZID     string
+	UserRole  auth_domain.Role
+	UserEmail string
+	// ClosedAt is set by offline terminals, server time is used if nil.
+	ClosedAt *time.Time
 }
 
 func (p CloseLastReceptionParams) Validate() error {
@@ -80,8 +109,11 @@ func (p CloseLastReceptionParams) Validate() error {
 }
 
 type DeleteLastProductParams struct {
-	PVZID    string
-	UserRole auth_domain.Role
+	PVZID     string
+	UserRole  auth_domain.Role
+	UserEmail string
+	// DeletedAt is set by offline terminals, server time is used if nil.
+	DeletedAt *time.Time
 }
 
 func (p DeleteLastProductParams) Validate() error {
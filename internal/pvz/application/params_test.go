@@ -99,3 +99,23 @@ func Test_DeleteLastProductParams_Validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_StreamParams_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    application.StreamParams
+		expectErr bool
+	}{
+		{"default page size", application.StreamParams{}, false},
+		{"max page size", application.StreamParams{PageSize: application.MaxStreamPageSize, WithReceptions: true}, false},
+		{"negative page size", application.StreamParams{PageSize: -1}, true},
+		{"too big page size", application.StreamParams{PageSize: application.MaxStreamPageSize + 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"time"
 
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
@@ -17,6 +19,7 @@ type PVZService struct {
 	pvzRepo       pvz_domain.PVZRepository
 	receptionRepo reception_domain.ReceptionRepository
 	productRepo   product_domain.ProductRepository
+	auditor       audit_domain.Auditor
 
 	log *logger.ZerologLogger
 }
@@ -25,19 +28,24 @@ func NewPVZService(
 	pvzRepo pvz_domain.PVZRepository,
 	receptionRepo reception_domain.ReceptionRepository,
 	productRepo product_domain.ProductRepository,
+	auditor audit_domain.Auditor,
 	l *logger.ZerologLogger,
 ) *PVZService {
 	return &PVZService{
 		pvzRepo:       pvzRepo,
 		receptionRepo: receptionRepo,
 		productRepo:   productRepo,
+		auditor:       auditor,
 		log:           l,
 	}
 }
 
-func (s *PVZService) Create(ctx context.Context, params CreateParams) (*pvz_domain.PVZ, error) {
+func (s *PVZService) Create(ctx context.Context, params CreateParams) (_ *pvz_domain.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.Create")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("CreatePVZ")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("CreatePVZ")
 		return nil, err
 	}
 
@@ -58,79 +66,242 @@ func (s *PVZService) Create(ctx context.Context, params CreateParams) (*pvz_doma
 
 	created, err := s.pvzRepo.Create(ctx, &pvz)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("pvz", pvz).Err(err).Msg("Error creating PVZ")
+		s.log.Error().Ctx(ctx).Any("params", params).Any("pvz", pvz).Err(err).Msg("Error creating PVZ")
 		return nil, err
 	}
 
 	metrics.PvzCreatedTotal.Inc()
 
-	s.log.Info().Any("params", params).Any("pvz", created).Msg("CreatePVZ successful")
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionPVZCreate,
+		EntityType: audit_domain.EntityPVZ,
+		EntityID:   *created.ID,
+		After:      created,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("pvz", created).Msg("CreatePVZ successful")
 
 	return created, nil
 }
 
-func (s *PVZService) CloseLastReception(ctx context.Context, params CloseLastReceptionParams) (*reception_domain.Reception, error) {
+func (s *PVZService) CloseLastReception(ctx context.Context, params CloseLastReceptionParams) (_ *reception_domain.Reception, err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.CloseLastReception")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("CloseLastReception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("CloseLastReception")
 		return nil, err
 	}
 
-	reception, err := s.receptionRepo.CloseLastReception(ctx, params.PVZID)
+	closedAt := time.Now()
+	if params.ClosedAt != nil {
+		closedAt = *params.ClosedAt
+	}
+
+	reception, err := s.receptionRepo.CloseLastReception(ctx, params.PVZID, closedAt, params.UserEmail)
 	if err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("Error closing last reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error closing last reception")
 		return nil, err
 	}
 
-	s.log.Info().Any("params", params).Any("reception", reception).Msg("CloseLastReception successful")
+	s.ObserveClosedReception(ctx, reception)
+
+	// reception state right before closing
+	before := *reception
+	before.Status = reception_domain.InProgress
+	before.CloseReason = ""
+	before.ClosedAt = nil
+	before.ClosedBy = ""
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionReceptionClose,
+		EntityType: audit_domain.EntityReception,
+		EntityID:   reception.ID,
+		Before:     &before,
+		After:      reception,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("reception", reception).Msg("CloseLastReception successful")
 
 	return reception, nil
 }
 
-func (s *PVZService) DeleteLastProduct(ctx context.Context, params DeleteLastProductParams) error {
+// ObserveClosedReception writes reception duration and products count metrics.
+// It is called for receptions closed by user and by auto closer.
+// Errors are only logged, reception is already closed at this point.
+func (s *PVZService) ObserveClosedReception(ctx context.Context, reception *reception_domain.Reception) {
+	pvz, err := s.pvzRepo.GetByID(ctx, reception.PVZID)
+	if err != nil {
+		s.log.Warn().Ctx(ctx).Any("reception", reception).Err(err).Msg("Cant get pvz for reception metrics")
+		return
+	}
+
+	products, err := s.productRepo.ListByReception(ctx, reception.ID)
+	if err != nil {
+		s.log.Warn().Ctx(ctx).Any("reception", reception).Err(err).Msg("Cant get products for reception metrics")
+		return
+	}
+
+	city := pvz.City.String()
+	metrics.ReceptionDuration.WithLabelValues(city).Observe(reception.Duration().Seconds())
+	metrics.ProductsPerReception.WithLabelValues(city).Observe(float64(len(products)))
+}
+
+func (s *PVZService) DeleteLastProduct(ctx context.Context, params DeleteLastProductParams) (err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.DeleteLastProduct")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("DeleteLastProduct")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("DeleteLastProduct")
 		return err
 	}
 
 	reception, err := s.receptionRepo.FindLastOpenByPVZ(ctx, params.PVZID)
 	if err != nil && !errors.Is(err, reception_domain.ErrNoOpenReception) {
-		s.log.Error().Any("params", params).Err(err).Msg("Error finding last open reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error finding last open reception")
 		return err
 	}
 
 	if reception == nil {
-		s.log.Error().Any("params", params).Err(reception_domain.ErrNoOpenReception).Msg("No open reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(reception_domain.ErrNoOpenReception).Msg("No open reception")
 		return reception_domain.ErrNoOpenReception
 	}
 
-	err = s.productRepo.DeleteLastFromReception(ctx, reception.ID)
+	deletedAt := time.Now()
+	if params.DeletedAt != nil {
+		deletedAt = *params.DeletedAt
+	}
+
+	deleted, err := s.productRepo.DeleteLastFromReception(ctx, reception.ID, deletedAt, params.UserEmail)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("reception", reception).Err(err).Msg("Error deleting last product")
+		s.log.Error().Ctx(ctx).Any("params", params).Any("reception", reception).Err(err).Msg("Error deleting last product")
 		return err
 	}
 
-	s.log.Info().Any("params", params).Any("reception", reception).Msg("DeleteLastProduct successful")
+	// product state right before deletion
+	before := *deleted
+	before.DeletedAt = nil
+	before.DeletedBy = ""
+
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionProductDelete,
+		EntityType: audit_domain.EntityProduct,
+		EntityID:   deleted.ID,
+		Before:     &before,
+		After:      deleted,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("reception", reception).Msg("DeleteLastProduct successful")
 	return nil
 }
 
-func (s *PVZService) ListAllPVZs(ctx context.Context) ([]*pvz_domain.PVZ, error) {
+func (s *PVZService) ListAllPVZs(ctx context.Context) (_ []*pvz_domain.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.ListAllPVZs")
+	defer func() { tracing.End(span, err) }()
+
 	pvzs, err := s.pvzRepo.ListAllPVZs(ctx)
 	if err != nil {
-		s.log.Error().Err(err).Msg("ListAllPVZs")
+		s.log.Error().Ctx(ctx).Err(err).Msg("ListAllPVZs")
 		return pvzs, err
 	}
 
 	return pvzs, nil
 }
 
-func (s *PVZService) ListWithReceptions(ctx context.Context, params ListWithReceptionsParams) ([]*pvz_domain.PVZWithReceptions, error) {
-	pvzWithReceptions, err := s.pvzRepo.ListWithReceptions(ctx, params.StartDate, params.EndDate, *params.Page, *params.Limit)
+// StreamPVZs passes all pvz to send one by one, oldest first.
+// Pvz are read by pages, so whole listing is never kept in memory.
+// Streaming stops on first send error or when ctx is canceled.
+func (s *PVZService) StreamPVZs(ctx context.Context, params StreamParams, send func(*pvz_domain.PVZWithReceptions) error) (err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.StreamPVZs")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("StreamPVZs")
+		return err
+	}
+
+	pageSize := params.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultStreamPageSize
+	}
+
+	var (
+		after *pvz_domain.PageKey
+		sent  int
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			s.log.Info().Ctx(ctx).Any("params", params).Int("sent", sent).Err(err).Msg("StreamPVZs canceled")
+			return err
+		}
+
+		page, err := s.pvzRepo.ListPage(ctx, params.AllowedPVZID, after, pageSize)
+		if err != nil {
+			s.log.Error().Ctx(ctx).Any("params", params).Any("after", after).Err(err).Msg("Error listing pvz page")
+			return err
+		}
+
+		for _, pvz := range page {
+			item := &pvz_domain.PVZWithReceptions{PVZ: pvz}
+			if params.WithReceptions {
+				item.Receptions, err = s.receptionsWithProducts(ctx, *pvz.ID)
+				if err != nil {
+					s.log.Error().Ctx(ctx).Any("pvz", pvz).Err(err).Msg("Error listing pvz receptions")
+					return err
+				}
+			}
+
+			if err := send(item); err != nil {
+				s.log.Info().Ctx(ctx).Any("params", params).Int("sent", sent).Err(err).Msg("StreamPVZs stopped")
+				return err
+			}
+			sent++
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+
+		last := page[len(page)-1]
+		after = &pvz_domain.PageKey{RegistrationDate: *last.RegistrationDate, ID: *last.ID}
+	}
+
+	s.log.Info().Ctx(ctx).Any("params", params).Int("sent", sent).Msg("StreamPVZs successful")
+
+	return nil
+}
+
+// receptionsWithProducts returns receptions of pvz with alive products, newest first.
+func (s *PVZService) receptionsWithProducts(ctx context.Context, pvzID string) ([]*pvz_domain.ReceptionWithProducts, error) {
+	receptions, err := s.receptionRepo.ListByPVZ(ctx, pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pvz_domain.ReceptionWithProducts, 0, len(receptions))
+	for _, reception := range receptions {
+		products, err := s.productRepo.ListByReception(ctx, reception.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &pvz_domain.ReceptionWithProducts{Reception: reception, Products: products})
+	}
+
+	return result, nil
+}
+
+func (s *PVZService) ListWithReceptions(ctx context.Context, params ListWithReceptionsParams) (_ []*pvz_domain.PVZWithReceptions, err error) {
+	ctx, span := tracing.Start(ctx, "PVZService.ListWithReceptions")
+	defer func() { tracing.End(span, err) }()
+
+	pvzWithReceptions, err := s.pvzRepo.ListWithReceptions(ctx, params.StartDate, params.EndDate, params.AllowedPVZID, *params.Page, *params.Limit)
 	if err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("ListWithReceptions")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("ListWithReceptions")
 		return nil, err
 	}
 
-	s.log.Info().Any("params", params).Any("resultCount", len(pvzWithReceptions)).Msg("ListWithReceptions successful")
+	s.log.Info().Ctx(ctx).Any("params", params).Any("resultCount", len(pvzWithReceptions)).Msg("ListWithReceptions successful")
 
 	return pvzWithReceptions, nil
 }
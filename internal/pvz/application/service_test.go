@@ -2,9 +2,11 @@ package application_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
@@ -17,6 +19,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
@@ -130,7 +136,10 @@ func TestPVZService_Create(t *testing.T) {
 
 			logger := logger.NewTestLogger()
 
-			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, logger)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, auditor, logger)
 			_, err := service.Create(context.Background(), tt.params)
 
 			if tt.expectErr != nil {
@@ -149,27 +158,66 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 
 	pvzID := uuid.NewString()
 	receptionID := uuid.NewString()
+	email := "employee@example.com"
+	openedAt := time.Now().Add(-time.Hour)
+	city := pvz_domain.Moscow
+
+	closedReception := func(_ context.Context, _ string, closedAt time.Time, closedBy string) (*reception_domain.Reception, error) {
+		assert.Equal(t, email, closedBy)
+		return &reception_domain.Reception{
+			ID:          receptionID,
+			DateTime:    openedAt,
+			PVZID:       pvzID,
+			Status:      reception_domain.Close,
+			CloseReason: reception_domain.CloseReasonManual,
+			ClosedAt:    &closedAt,
+			ClosedBy:    closedBy,
+		}, nil
+	}
 
 	tests := []struct {
 		name      string
 		params    application.CloseLastReceptionParams
-		mockSetup func(*reception_mocks.MockReceptionRepository)
+		mockSetup func(*pvz_mocks.MockPVZRepository, *reception_mocks.MockReceptionRepository, *product_mocks.MockProductRepository)
 		expectErr error
 	}{
 		{
 			name: "successful close",
 			params: application.CloseLastReceptionParams{
-				PVZID:    pvzID,
-				UserRole: auth_domain.RoleEmployee,
+				PVZID:     pvzID,
+				UserRole:  auth_domain.RoleEmployee,
+				UserEmail: email,
 			},
-			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 				r.EXPECT().
-					CloseLastReception(gomock.Any(), pvzID).
-					Return(&reception_domain.Reception{
-						ID:     receptionID,
-						PVZID:  pvzID,
-						Status: reception_domain.Close,
-					}, nil)
+					CloseLastReception(gomock.Any(), pvzID, gomock.Any(), email).
+					DoAndReturn(closedReception)
+
+				pv.EXPECT().
+					GetByID(gomock.Any(), pvzID).
+					Return(&pvz_domain.PVZ{ID: &pvzID, City: city}, nil)
+
+				p.EXPECT().
+					ListByReception(gomock.Any(), receptionID).
+					Return([]*product_domain.Product{{ID: uuid.NewString()}}, nil)
+			},
+			expectErr: nil,
+		},
+		{
+			name: "successful close when metrics lookup fails",
+			params: application.CloseLastReceptionParams{
+				PVZID:     pvzID,
+				UserRole:  auth_domain.RoleEmployee,
+				UserEmail: email,
+			},
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				r.EXPECT().
+					CloseLastReception(gomock.Any(), pvzID, gomock.Any(), email).
+					DoAndReturn(closedReception)
+
+				pv.EXPECT().
+					GetByID(gomock.Any(), pvzID).
+					Return(nil, pvz_domain.ErrInternalDatabase)
 			},
 			expectErr: nil,
 		},
@@ -179,9 +227,9 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 				PVZID:    pvzID,
 				UserRole: auth_domain.RoleEmployee,
 			},
-			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 				r.EXPECT().
-					CloseLastReception(gomock.Any(), pvzID).
+					CloseLastReception(gomock.Any(), pvzID, gomock.Any(), gomock.Any()).
 					Return(nil, reception_domain.ErrNoOpenReception)
 			},
 			expectErr: reception_domain.ErrNoOpenReception,
@@ -192,9 +240,9 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 				PVZID:    pvzID,
 				UserRole: auth_domain.RoleEmployee,
 			},
-			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 				r.EXPECT().
-					CloseLastReception(gomock.Any(), pvzID).
+					CloseLastReception(gomock.Any(), pvzID, gomock.Any(), gomock.Any()).
 					Return(nil, pvz_domain.ErrInternalDatabase)
 			},
 			expectErr: pvz_domain.ErrInternalDatabase,
@@ -205,7 +253,7 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 				PVZID:    "",
 				UserRole: auth_domain.RoleEmployee,
 			},
-			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 			},
 			expectErr: pvz_domain.ErrInvalidIDFormat,
 		},
@@ -215,7 +263,7 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 				PVZID:    pvzID,
 				UserRole: auth_domain.RoleModerator,
 			},
-			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+			mockSetup: func(pv *pvz_mocks.MockPVZRepository, r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 			},
 			expectErr: pvz_domain.ErrAccessDenied,
 		},
@@ -226,18 +274,23 @@ func TestPVZService_CloseLastReception(t *testing.T) {
 			pvzRepo := pvz_mocks.NewMockPVZRepository(ctrl)
 			receptionRepo := reception_mocks.NewMockReceptionRepository(ctrl)
 			productRepo := product_mocks.NewMockProductRepository(ctrl)
-			tt.mockSetup(receptionRepo)
+			tt.mockSetup(pvzRepo, receptionRepo, productRepo)
 
 			logger := logger.NewTestLogger()
 
-			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, logger)
-			_, err := service.CloseLastReception(context.Background(), tt.params)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, auditor, logger)
+			reception, err := service.CloseLastReception(context.Background(), tt.params)
 
 			if tt.expectErr != nil {
 				require.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectErr)
 			} else {
 				require.NoError(t, err)
+				require.NotNil(t, reception.ClosedAt)
+				assert.Equal(t, tt.params.UserEmail, reception.ClosedBy)
 			}
 		})
 	}
@@ -259,8 +312,9 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 		{
 			name: "successful delete",
 			params: application.DeleteLastProductParams{
-				PVZID:    pvzID,
-				UserRole: auth_domain.RoleEmployee,
+				PVZID:     pvzID,
+				UserRole:  auth_domain.RoleEmployee,
+				UserEmail: "emp@example.com",
 			},
 			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
 				r.EXPECT().
@@ -272,8 +326,8 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 					}, nil)
 
 				p.EXPECT().
-					DeleteLastFromReception(gomock.Any(), receptionID).
-					Return(nil)
+					DeleteLastFromReception(gomock.Any(), receptionID, gomock.Any(), "emp@example.com").
+					Return(&product_domain.Product{ID: uuid.NewString(), ReceptionID: receptionID}, nil)
 			},
 			expectErr: nil,
 		},
@@ -319,11 +373,32 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 					}, nil)
 
 				p.EXPECT().
-					DeleteLastFromReception(gomock.Any(), receptionID).
-					Return(pvz_domain.ErrInternalDatabase)
+					DeleteLastFromReception(gomock.Any(), receptionID, gomock.Any(), gomock.Any()).
+					Return(nil, pvz_domain.ErrInternalDatabase)
 			},
 			expectErr: pvz_domain.ErrInternalDatabase,
 		},
+		{
+			name: "no products to delete",
+			params: application.DeleteLastProductParams{
+				PVZID:    pvzID,
+				UserRole: auth_domain.RoleEmployee,
+			},
+			mockSetup: func(r *reception_mocks.MockReceptionRepository, p *product_mocks.MockProductRepository) {
+				r.EXPECT().
+					FindLastOpenByPVZ(gomock.Any(), pvzID).
+					Return(&reception_domain.Reception{
+						ID:     receptionID,
+						PVZID:  pvzID,
+						Status: reception_domain.InProgress,
+					}, nil)
+
+				p.EXPECT().
+					DeleteLastFromReception(gomock.Any(), receptionID, gomock.Any(), gomock.Any()).
+					Return(nil, product_domain.ErrNoProductsToDelete)
+			},
+			expectErr: product_domain.ErrNoProductsToDelete,
+		},
 		{
 			name: "invalid params - empty pvzID",
 			params: application.DeleteLastProductParams{
@@ -354,7 +429,10 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 			tt.mockSetup(receptionRepo, productRepo)
 
 			log := logger.NewTestLogger()
-			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, log)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, auditor, log)
 
 			err := service.DeleteLastProduct(context.Background(), tt.params)
 
@@ -368,6 +446,43 @@ func TestPVZService_DeleteLastProduct(t *testing.T) {
 	}
 }
 
+func TestPVZService_ErrorRecordedInSpan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	pvzID := uuid.NewString()
+	receptionRepo := reception_mocks.NewMockReceptionRepository(ctrl)
+	receptionRepo.EXPECT().
+		FindLastOpenByPVZ(gomock.Any(), pvzID).
+		Return(nil, reception_domain.ErrNoOpenReception)
+
+	service := application.NewPVZService(
+		pvz_mocks.NewMockPVZRepository(ctrl),
+		receptionRepo,
+		product_mocks.NewMockProductRepository(ctrl),
+		audit_mocks.NewMockAuditor(ctrl),
+		logger.NewTestLogger(),
+	)
+
+	err := service.DeleteLastProduct(context.Background(), application.DeleteLastProductParams{
+		PVZID:    pvzID,
+		UserRole: auth_domain.RoleEmployee,
+	})
+	require.ErrorIs(t, err, reception_domain.ErrNoOpenReception)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "PVZService.DeleteLastProduct", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, err.Error(), spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1)
+}
+
 func TestPVZService_ListWithReceptions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -396,7 +511,7 @@ func TestPVZService_ListWithReceptions(t *testing.T) {
 			},
 			mockSetup: func(r *pvz_mocks.MockPVZRepository) {
 				r.EXPECT().
-					ListWithReceptions(gomock.Any(), &startDate, &endDate, page, limit).
+					ListWithReceptions(gomock.Any(), &startDate, &endDate, "", page, limit).
 					Return([]*pvz_domain.PVZWithReceptions{
 						{
 							PVZ: &pvz_domain.PVZ{
@@ -437,7 +552,7 @@ func TestPVZService_ListWithReceptions(t *testing.T) {
 			},
 			mockSetup: func(r *pvz_mocks.MockPVZRepository) {
 				r.EXPECT().
-					ListWithReceptions(gomock.Any(), &startDate, &endDate, page, limit).
+					ListWithReceptions(gomock.Any(), &startDate, &endDate, "", page, limit).
 					Return([]*pvz_domain.PVZWithReceptions{}, nil)
 			},
 			expectCount: 0,
@@ -453,12 +568,31 @@ func TestPVZService_ListWithReceptions(t *testing.T) {
 			},
 			mockSetup: func(r *pvz_mocks.MockPVZRepository) {
 				r.EXPECT().
-					ListWithReceptions(gomock.Any(), &startDate, &endDate, page, limit).
+					ListWithReceptions(gomock.Any(), &startDate, &endDate, "", page, limit).
 					Return(nil, pvz_domain.ErrInternalDatabase)
 			},
 			expectCount: 0,
 			expectErr:   pvz_domain.ErrInternalDatabase,
 		},
+		{
+			name: "restricted to one pvz",
+			params: application.ListWithReceptionsParams{
+				StartDate:    &startDate,
+				EndDate:      &endDate,
+				Page:         &page,
+				Limit:        &limit,
+				AllowedPVZID: pvzID,
+			},
+			mockSetup: func(r *pvz_mocks.MockPVZRepository) {
+				r.EXPECT().
+					ListWithReceptions(gomock.Any(), &startDate, &endDate, pvzID, page, limit).
+					Return([]*pvz_domain.PVZWithReceptions{
+						{PVZ: &pvz_domain.PVZ{ID: &pvzID, City: "Казань"}},
+					}, nil)
+			},
+			expectCount: 1,
+			expectErr:   nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -469,7 +603,10 @@ func TestPVZService_ListWithReceptions(t *testing.T) {
 			tt.mockSetup(pvzRepo)
 
 			log := logger.NewTestLogger()
-			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, log)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewPVZService(pvzRepo, receptionRepo, productRepo, auditor, log)
 
 			result, err := service.ListWithReceptions(context.Background(), tt.params)
 
@@ -483,3 +620,136 @@ func TestPVZService_ListWithReceptions(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZService_StreamPVZs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	regDate := time.Now()
+	newPVZ := func() *pvz_domain.PVZ {
+		id := uuid.NewString()
+		return &pvz_domain.PVZ{ID: &id, RegistrationDate: &regDate, City: pvz_domain.Moscow}
+	}
+	first, second, third := newPVZ(), newPVZ(), newPVZ()
+	keyOf := func(p *pvz_domain.PVZ) *pvz_domain.PageKey {
+		return &pvz_domain.PageKey{RegistrationDate: *p.RegistrationDate, ID: *p.ID}
+	}
+
+	type repos struct {
+		pvz       *pvz_mocks.MockPVZRepository
+		reception *reception_mocks.MockReceptionRepository
+		product   *product_mocks.MockProductRepository
+	}
+
+	newService := func() (*application.PVZService, repos) {
+		r := repos{
+			pvz:       pvz_mocks.NewMockPVZRepository(ctrl),
+			reception: reception_mocks.NewMockReceptionRepository(ctrl),
+			product:   product_mocks.NewMockProductRepository(ctrl),
+		}
+		return application.NewPVZService(r.pvz, r.reception, r.product, audit_mocks.NewMockAuditor(ctrl), logger.NewTestLogger()), r
+	}
+
+	collect := func(sent *[]string) func(*pvz_domain.PVZWithReceptions) error {
+		return func(p *pvz_domain.PVZWithReceptions) error {
+			*sent = append(*sent, *p.PVZ.ID)
+			return nil
+		}
+	}
+
+	t.Run("pages until short page", func(t *testing.T) {
+		service, r := newService()
+		gomock.InOrder(
+			r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, 2).Return([]*pvz_domain.PVZ{first, second}, nil),
+			r.pvz.EXPECT().ListPage(gomock.Any(), "", keyOf(second), 2).Return([]*pvz_domain.PVZ{third}, nil),
+		)
+
+		var sent []string
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: 2}, collect(&sent))
+		require.NoError(t, err)
+		assert.Equal(t, []string{*first.ID, *second.ID, *third.ID}, sent)
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		service, r := newService()
+		r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, application.DefaultStreamPageSize).Return([]*pvz_domain.PVZ{}, nil)
+
+		var sent []string
+		err := service.StreamPVZs(context.Background(), application.StreamParams{}, collect(&sent))
+		require.NoError(t, err)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("with receptions", func(t *testing.T) {
+		service, r := newService()
+		reception := &reception_domain.Reception{ID: uuid.NewString(), PVZID: *first.ID, Status: reception_domain.Close}
+		product := &product_domain.Product{ID: uuid.NewString(), Type: product_domain.Shoes, ReceptionID: reception.ID}
+
+		r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, 10).Return([]*pvz_domain.PVZ{first}, nil)
+		r.reception.EXPECT().ListByPVZ(gomock.Any(), *first.ID).Return([]*reception_domain.Reception{reception}, nil)
+		r.product.EXPECT().ListByReception(gomock.Any(), reception.ID).Return([]*product_domain.Product{product}, nil)
+
+		var sent []*pvz_domain.PVZWithReceptions
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: 10, WithReceptions: true}, func(p *pvz_domain.PVZWithReceptions) error {
+			sent = append(sent, p)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		require.Len(t, sent[0].Receptions, 1)
+		assert.Equal(t, reception, sent[0].Receptions[0].Reception)
+		assert.Equal(t, []*product_domain.Product{product}, sent[0].Receptions[0].Products)
+	})
+
+	t.Run("restricted to one pvz", func(t *testing.T) {
+		service, r := newService()
+		r.pvz.EXPECT().ListPage(gomock.Any(), *second.ID, nil, 2).Return([]*pvz_domain.PVZ{second}, nil)
+
+		var sent []string
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: 2, AllowedPVZID: *second.ID}, collect(&sent))
+		require.NoError(t, err)
+		assert.Equal(t, []string{*second.ID}, sent)
+	})
+
+	t.Run("send error stops stream", func(t *testing.T) {
+		service, r := newService()
+		r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, 2).Return([]*pvz_domain.PVZ{first, second}, nil)
+
+		sendErr := errors.New("client is gone")
+		calls := 0
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: 2}, func(*pvz_domain.PVZWithReceptions) error {
+			calls++
+			return sendErr
+		})
+		assert.ErrorIs(t, err, sendErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("canceled context stops paging", func(t *testing.T) {
+		service, r := newService()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, 2).Return([]*pvz_domain.PVZ{first, second}, nil)
+
+		err := service.StreamPVZs(ctx, application.StreamParams{PageSize: 2}, func(*pvz_domain.PVZWithReceptions) error {
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		service, r := newService()
+		r.pvz.EXPECT().ListPage(gomock.Any(), "", nil, 2).Return(nil, pvz_domain.ErrInternalDatabase)
+
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: 2}, collect(new([]string)))
+		assert.ErrorIs(t, err, pvz_domain.ErrInternalDatabase)
+	})
+
+	t.Run("invalid page size", func(t *testing.T) {
+		service, _ := newService()
+
+		err := service.StreamPVZs(context.Background(), application.StreamParams{PageSize: -1}, collect(new([]string)))
+		assert.ErrorIs(t, err, pvz_domain.ErrInvalidPageSize)
+	})
+}
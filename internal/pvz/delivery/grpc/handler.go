@@ -2,16 +2,37 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/gateway"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	"github.com/0x0FACED/pvz-avito/internal/pvz/application"
 	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
 	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type PVZService interface {
+	Create(ctx context.Context, params application.CreateParams) (*pvz_domain.PVZ, error)
+	DeleteLastProduct(ctx context.Context, params application.DeleteLastProductParams) error
+	CloseLastReception(ctx context.Context, params application.CloseLastReceptionParams) (*reception_domain.Reception, error)
+	ListWithReceptions(ctx context.Context, params application.ListWithReceptionsParams) ([]*pvz_domain.PVZWithReceptions, error)
 	ListAllPVZs(ctx context.Context) ([]*pvz_domain.PVZ, error)
+	StreamPVZs(ctx context.Context, params application.StreamParams, send func(*pvz_domain.PVZWithReceptions) error) error
 }
 
+// GRPCHandler implements PVZService, it is also served on REST by gateway.
+// Errors are apperr catalogue statuses, gateway turns them back into http errors.
 type GRPCHandler struct {
 	pb.UnimplementedPVZServiceServer
 	svc PVZService
@@ -23,20 +44,230 @@ func NewGRPCHandler(svc PVZService) *GRPCHandler {
 	}
 }
 
+func (h *GRPCHandler) CreatePVZ(ctx context.Context, req *pb.CreatePVZRequest) (*pb.PVZ, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.CreateParams{
+		ID:       req.Id,
+		City:     pvz_domain.City(req.GetCity()),
+		UserRole: auth_domain.Role(claims.Role),
+	}
+	if req.RegistrationDate != nil {
+		regDate := req.RegistrationDate.AsTime()
+		params.RegistrationDate = &regDate
+	}
+
+	pvz, err := h.svc.Create(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	gateway.SetStatus(ctx, http.StatusCreated)
+
+	return newPVZ(pvz), nil
+}
+
+func (h *GRPCHandler) CloseLastReception(ctx context.Context, req *pb.CloseLastReceptionRequest) (*pb.Reception, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.CloseLastReceptionParams{
+		PVZID:     req.GetPvzId(),
+		UserRole:  auth_domain.Role(claims.Role),
+		UserEmail: claims.Email,
+	}
+
+	reception, err := h.svc.CloseLastReception(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	return newReception(reception), nil
+}
+
+func (h *GRPCHandler) DeleteLastProduct(ctx context.Context, req *pb.DeleteLastProductRequest) (*emptypb.Empty, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.DeleteLastProductParams{
+		PVZID:     req.GetPvzId(),
+		UserRole:  auth_domain.Role(claims.Role),
+		UserEmail: claims.Email,
+	}
+
+	err := h.svc.DeleteLastProduct(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListPVZ reports malformed filters as apperr.ErrInvalidRequest with name of filter.
+func (h *GRPCHandler) ListPVZ(ctx context.Context, req *pb.ListPVZRequest) (*pb.ListPVZResponse, error) {
+	claims, ok := httpcommon.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrNoAuth)
+	}
+
+	var (
+		startDate *time.Time
+		endDate   *time.Time
+		page      = 1
+		limit     = 10
+	)
+
+	if req.GetStartDate() != "" {
+		t, err := time.Parse(time.DateOnly, req.GetStartDate())
+		if err != nil {
+			return nil, apperr.GRPCError(ctx, fmt.Errorf("%w: invalid startDate", apperr.ErrInvalidRequest))
+		}
+		startDate = &t
+	}
+
+	if req.GetEndDate() != "" {
+		t, err := time.Parse(time.DateOnly, req.GetEndDate())
+		if err != nil {
+			return nil, apperr.GRPCError(ctx, fmt.Errorf("%w: invalid endDate", apperr.ErrInvalidRequest))
+		}
+		endDate = &t
+	}
+
+	if req.GetPage() != "" {
+		p, err := strconv.Atoi(req.GetPage())
+		if err != nil || p < 1 {
+			return nil, apperr.GRPCError(ctx, fmt.Errorf("%w: invalid page", apperr.ErrInvalidRequest))
+		}
+		page = p
+	}
+
+	if req.GetLimit() != "" {
+		l, err := strconv.Atoi(req.GetLimit())
+		if err != nil || l < 1 {
+			return nil, apperr.GRPCError(ctx, fmt.Errorf("%w: invalid limit", apperr.ErrInvalidRequest))
+		}
+		limit = l
+	}
+
+	params := application.ListWithReceptionsParams{
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Page:         &page,
+		Limit:        &limit,
+		AllowedPVZID: claims.PVZID,
+	}
+
+	result, err := h.svc.ListWithReceptions(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	resp := &pb.ListPVZResponse{
+		Items: make([]*pb.PVZWithReceptions, 0, len(result)),
+	}
+	for _, p := range result {
+		resp.Items = append(resp.Items, newPVZWithReceptions(p))
+	}
+
+	return resp, nil
+}
+
 func (h *GRPCHandler) GetPVZList(ctx context.Context, req *pb.GetPVZListRequest) (*pb.GetPVZListResponse, error) {
 	pvzs, err := h.svc.ListAllPVZs(ctx)
 	if err != nil {
-		return nil, err
+		return nil, apperr.GRPCError(ctx, err)
 	}
 
 	resp := &pb.GetPVZListResponse{}
 	for _, p := range pvzs {
-		resp.Pvzs = append(resp.Pvzs, &pb.PVZ{
-			Id:               *p.ID,
-			RegistrationDate: timestamppb.New(*p.RegistrationDate),
-			City:             p.City.String(),
-		})
+		resp.Pvzs = append(resp.Pvzs, newPVZ(p))
 	}
 
 	return resp, nil
 }
+
+func (h *GRPCHandler) StreamPVZs(req *pb.StreamPVZsRequest, stream grpc.ServerStreamingServer[pb.PVZWithReceptions]) error {
+	claims, ok := httpcommon.ClaimsFromContext(stream.Context())
+	if !ok {
+		return apperr.GRPCError(stream.Context(), apperr.ErrNoAuth)
+	}
+
+	params := application.StreamParams{
+		PageSize:       int(req.GetPageSize()),
+		WithReceptions: req.GetWithReceptions(),
+		AllowedPVZID:   claims.PVZID,
+	}
+
+	err := h.svc.StreamPVZs(stream.Context(), params, func(p *pvz_domain.PVZWithReceptions) error {
+		return stream.Send(newPVZWithReceptions(p))
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		return apperr.GRPCError(stream.Context(), err)
+	}
+
+	return nil
+}
+
+func newPVZ(p *pvz_domain.PVZ) *pb.PVZ {
+	resp := &pb.PVZ{
+		City: p.City.String(),
+	}
+	if p.ID != nil {
+		resp.Id = *p.ID
+	}
+	if p.RegistrationDate != nil {
+		resp.RegistrationDate = timestamppb.New(*p.RegistrationDate)
+	}
+
+	return resp
+}
+
+func newReception(r *reception_domain.Reception) *pb.Reception {
+	resp := &pb.Reception{
+		Id:       r.ID,
+		DateTime: timestamppb.New(r.DateTime),
+		PvzId:    r.PVZID,
+		Status:   r.Status.String(),
+		ClosedBy: r.ClosedBy,
+	}
+	if r.ClosedAt != nil {
+		resp.ClosedAt = timestamppb.New(*r.ClosedAt)
+	}
+
+	return resp
+}
+
+func newPVZWithReceptions(p *pvz_domain.PVZWithReceptions) *pb.PVZWithReceptions {
+	resp := &pb.PVZWithReceptions{
+		Pvz: newPVZ(p.PVZ),
+	}
+
+	for _, r := range p.Receptions {
+		reception := &pb.ReceptionWithProducts{
+			Reception: newReception(r.Reception),
+		}
+
+		for _, product := range r.Products {
+			reception.Products = append(reception.Products, &pb.Product{
+				Id:          product.ID,
+				DateTime:    timestamppb.New(product.DateTime),
+				Type:        product.Type.String(),
+				ReceptionId: product.ReceptionID,
+			})
+		}
+
+		resp.Receptions = append(resp.Receptions, reception)
+	}
+
+	return resp
+}
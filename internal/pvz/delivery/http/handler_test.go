@@ -13,12 +13,14 @@ import (
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_grpc "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc"
 	pvz_http "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/http"
 	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pvz/mocks"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -30,7 +32,7 @@ func TestPVZHandler_Create(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
 		name           string
-		request        pvz_http.CreateRequest
+		request        createRequest
 		userRole       string
 		mockSetup      func(*mocks.MockPVZService)
 		expectedStatus int
@@ -38,7 +40,7 @@ func TestPVZHandler_Create(t *testing.T) {
 	}{
 		{
 			name: "successful creation",
-			request: pvz_http.CreateRequest{
+			request: createRequest{
 				ID:               &pvzID,
 				RegistrationDate: &now,
 				City:             "Москва",
@@ -64,7 +66,7 @@ func TestPVZHandler_Create(t *testing.T) {
 		},
 		{
 			name: "access denied for employee",
-			request: pvz_http.CreateRequest{
+			request: createRequest{
 				ID:               &pvzID,
 				RegistrationDate: &now,
 				City:             "Москва",
@@ -79,7 +81,7 @@ func TestPVZHandler_Create(t *testing.T) {
 		},
 		{
 			name: "pvz already exists",
-			request: pvz_http.CreateRequest{
+			request: createRequest{
 				ID:               &pvzID,
 				RegistrationDate: &now,
 				City:             "Москва",
@@ -101,7 +103,7 @@ func TestPVZHandler_Create(t *testing.T) {
 				tt.mockSetup(pvzSvcMock)
 			}
 
-			handler := pvz_http.NewHandler(pvzSvcMock)
+			handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest(nethttp.MethodPost, "/pvz", bytes.NewReader(body))
@@ -113,7 +115,7 @@ func TestPVZHandler_Create(t *testing.T) {
 
 			rec := httptest.NewRecorder()
 
-			handler.Create(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
@@ -122,7 +124,7 @@ func TestPVZHandler_Create(t *testing.T) {
 				_ = json.NewDecoder(rec.Body).Decode(&errResp)
 				assert.Contains(t, errResp.Error(), tt.expectErr)
 			} else {
-				var resp pvz_http.CreateResponse
+				var resp createResponse
 				_ = json.NewDecoder(rec.Body).Decode(&resp)
 				assert.Equal(t, *tt.request.ID, *resp.ID)
 				assert.Equal(t, tt.request.City, resp.City)
@@ -132,24 +134,25 @@ func TestPVZHandler_Create(t *testing.T) {
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		pvzSvcMock := mocks.NewMockPVZService(ctrl)
-		handler := pvz_http.NewHandler(pvzSvcMock)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 		req := httptest.NewRequest(nethttp.MethodPost, "/pvz", bytes.NewReader([]byte("{invalid}")))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
-		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
+		assert.Equal(t, nethttp.StatusBadRequest, rec.Code)
 		var errResp httpcommon.ErrorResponse
 		_ = json.NewDecoder(rec.Body).Decode(&errResp)
+		assert.Equal(t, "invalid_body", errResp.Code)
 		assert.Equal(t, "invalid request body", errResp.Error())
 	})
 
 	t.Run("missing claims in context", func(t *testing.T) {
 		pvzSvcMock := mocks.NewMockPVZService(ctrl)
-		handler := pvz_http.NewHandler(pvzSvcMock)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
-		validRequest := pvz_http.CreateRequest{
+		validRequest := createRequest{
 			ID:               &pvzID,
 			RegistrationDate: &now,
 			City:             "Москва",
@@ -159,7 +162,7 @@ func TestPVZHandler_Create(t *testing.T) {
 		req := httptest.NewRequest(nethttp.MethodPost, "/pvz", bytes.NewReader(body))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -221,7 +224,7 @@ func TestPVZHandler_CloseLastReception(t *testing.T) {
 					Return(nil, reception_domain.ErrNoOpenReception)
 			},
 			expectedStatus: nethttp.StatusBadRequest,
-			expectErr:      "reception already closed",
+			expectErr:      "no open reception found",
 		},
 	}
 
@@ -232,11 +235,10 @@ func TestPVZHandler_CloseLastReception(t *testing.T) {
 				tt.mockSetup(pvzSvcMock)
 			}
 
-			handler := pvz_http.NewHandler(pvzSvcMock)
+			handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 			req := httptest.NewRequest("POST", "/pvz/"+tt.pvzID+"/close_last_reception", nil)
 
-			req.SetPathValue("pvzId", "pvz-123")
 			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
 				Role: tt.userRole,
 			})
@@ -244,7 +246,7 @@ func TestPVZHandler_CloseLastReception(t *testing.T) {
 
 			rec := httptest.NewRecorder()
 
-			handler.CloseLastReception(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
@@ -253,7 +255,7 @@ func TestPVZHandler_CloseLastReception(t *testing.T) {
 				_ = json.NewDecoder(rec.Body).Decode(&errResp)
 				assert.Equal(t, tt.expectErr, errResp.Error())
 			} else {
-				var resp pvz_http.CloseResponse
+				var resp closeResponse
 				_ = json.NewDecoder(rec.Body).Decode(&resp)
 				assert.Equal(t, tt.pvzID, resp.PVZID)
 				assert.Equal(t, reception_domain.Close, reception_domain.Status(resp.Status))
@@ -263,18 +265,58 @@ func TestPVZHandler_CloseLastReception(t *testing.T) {
 
 	t.Run("missing claims in context", func(t *testing.T) {
 		pvzSvcMock := mocks.NewMockPVZService(ctrl)
-		handler := pvz_http.NewHandler(pvzSvcMock)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 		req := httptest.NewRequest(nethttp.MethodPost, "/pvz/pvz-123/close_last_reception", nil)
 		rec := httptest.NewRecorder()
 
-		handler.CloseLastReception(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
 		_ = json.NewDecoder(rec.Body).Decode(&errResp)
 		assert.Equal(t, "access denied", errResp.Error())
 	})
+
+	t.Run("closing info in response", func(t *testing.T) {
+		pvzSvcMock := mocks.NewMockPVZService(ctrl)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
+
+		closedAt := now.Add(time.Hour)
+		pvzSvcMock.EXPECT().CloseLastReception(
+			gomock.Any(),
+			application.CloseLastReceptionParams{
+				PVZID:     "pvz-123",
+				UserRole:  auth_domain.RoleEmployee,
+				UserEmail: "employee@example.com",
+			},
+		).Return(&reception_domain.Reception{
+			ID:       "rec-123",
+			DateTime: now,
+			PVZID:    "pvz-123",
+			Status:   reception_domain.Close,
+			ClosedAt: &closedAt,
+			ClosedBy: "employee@example.com",
+		}, nil)
+
+		req := httptest.NewRequest(nethttp.MethodPost, "/pvz/pvz-123/close_last_reception", nil)
+		ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+			Email: "employee@example.com",
+			Role:  "employee",
+		})
+		req = req.WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		serve(handler, rec, req)
+
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		var resp closeResponse
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		if assert.NotNil(t, resp.ClosedAt) {
+			assert.True(t, closedAt.Equal(*resp.ClosedAt))
+		}
+		assert.Equal(t, "employee@example.com", resp.ClosedBy)
+	})
 }
 
 func TestPVZHandler_DeleteLastProduct(t *testing.T) {
@@ -346,11 +388,10 @@ func TestPVZHandler_DeleteLastProduct(t *testing.T) {
 				tt.mockSetup(pvzSvcMock)
 			}
 
-			handler := pvz_http.NewHandler(pvzSvcMock)
+			handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 			req := httptest.NewRequest("POST", "/pvz/"+tt.pvzID+"/delete_last_product", nil)
 
-			req.SetPathValue("pvzId", "pvz-123")
 			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
 				Role: tt.userRole,
 			})
@@ -358,7 +399,7 @@ func TestPVZHandler_DeleteLastProduct(t *testing.T) {
 
 			rec := httptest.NewRecorder()
 
-			handler.DeleteLastProduct(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
@@ -366,18 +407,20 @@ func TestPVZHandler_DeleteLastProduct(t *testing.T) {
 				var errResp httpcommon.ErrorResponse
 				_ = json.NewDecoder(rec.Body).Decode(&errResp)
 				assert.Equal(t, tt.expectErr, errResp.Error())
+			} else {
+				assert.Empty(t, rec.Body.String())
 			}
 		})
 	}
 
 	t.Run("missing claims in context", func(t *testing.T) {
 		pvzSvcMock := mocks.NewMockPVZService(ctrl)
-		handler := pvz_http.NewHandler(pvzSvcMock)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 		req := httptest.NewRequest(nethttp.MethodPost, "/pvz/pvz-123/delete_last_product", nil)
 		rec := httptest.NewRecorder()
 
-		handler.DeleteLastProduct(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -404,6 +447,7 @@ func TestPVZHandler_ListWithReceptions(t *testing.T) {
 		mockSetup      func(*mocks.MockPVZService)
 		expectedStatus int
 		expectedCount  int
+		expectErr      string
 	}{
 		{
 			name: "successful list with all params",
@@ -474,8 +518,8 @@ func TestPVZHandler_ListWithReceptions(t *testing.T) {
 				"startDate": "invalid-date",
 			},
 			mockSetup:      nil,
-			expectedStatus: nethttp.StatusOK,
-			expectedCount:  0,
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "invalid request: invalid startDate",
 		},
 		{
 			name: "invalid page number",
@@ -483,8 +527,18 @@ func TestPVZHandler_ListWithReceptions(t *testing.T) {
 				"page": "invalid",
 			},
 			mockSetup:      nil,
-			expectedStatus: nethttp.StatusOK,
-			expectedCount:  0,
+			expectedStatus: nethttp.StatusBadRequest,
+			expectErr:      "invalid request: invalid page",
+		},
+		{
+			name:        "database error",
+			queryParams: map[string]string{},
+			mockSetup: func(m *mocks.MockPVZService) {
+				m.EXPECT().ListWithReceptions(gomock.Any(), gomock.Any()).
+					Return(nil, pvz_domain.ErrInternalDatabase)
+			},
+			expectedStatus: nethttp.StatusInternalServerError,
+			expectErr:      "internal error",
 		},
 	}
 
@@ -495,7 +549,7 @@ func TestPVZHandler_ListWithReceptions(t *testing.T) {
 				tt.mockSetup(pvzSvcMock)
 			}
 
-			handler := pvz_http.NewHandler(pvzSvcMock)
+			handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
 
 			req := httptest.NewRequest(nethttp.MethodGet, "/pvz", nil)
 			q := req.URL.Query()
@@ -503,18 +557,111 @@ func TestPVZHandler_ListWithReceptions(t *testing.T) {
 				q.Add(k, v)
 			}
 			req.URL.RawQuery = q.Encode()
+			req = req.WithContext(context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Role: "employee",
+			}))
 
 			rec := httptest.NewRecorder()
 
-			handler.ListWithReceptions(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
-			var resp []pvz_http.ListResponse
-			_ = json.NewDecoder(rec.Body).Decode(&resp)
+			if tt.expectErr != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Equal(t, tt.expectErr, errResp.Error())
+				return
+			}
+
+			var resp []listResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
 			assert.Equal(t, tt.expectedCount, len(resp))
 		})
 	}
+
+	t.Run("empty lists are in response", func(t *testing.T) {
+		pvzSvcMock := mocks.NewMockPVZService(ctrl)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
+
+		pvzWithoutReceptions := "pvz-2"
+		pvzSvcMock.EXPECT().ListWithReceptions(gomock.Any(), gomock.Any()).
+			Return([]*pvz_domain.PVZWithReceptions{
+				{
+					PVZ: &pvz_domain.PVZ{
+						ID:               &pvzID,
+						RegistrationDate: &now,
+						City:             "Москва",
+					},
+					Receptions: []*pvz_domain.ReceptionWithProducts{
+						{
+							Reception: &reception_domain.Reception{
+								ID:       "rec-1",
+								DateTime: now,
+								PVZID:    pvzID,
+								Status:   reception_domain.InProgress,
+							},
+							Products: []*product_domain.Product{},
+						},
+					},
+				},
+				{
+					PVZ: &pvz_domain.PVZ{
+						ID:               &pvzWithoutReceptions,
+						RegistrationDate: &now,
+						City:             "Казань",
+					},
+					Receptions: []*pvz_domain.ReceptionWithProducts{},
+				},
+			}, nil)
+
+		req := httptest.NewRequest(nethttp.MethodGet, "/pvz", nil)
+		req = req.WithContext(context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+			Role: "employee",
+		}))
+		rec := httptest.NewRecorder()
+
+		serve(handler, rec, req)
+
+		assert.Equal(t, nethttp.StatusOK, rec.Code)
+		assert.JSONEq(t, `[
+			{
+				"pvz": {"id": "`+pvzID+`", "registrationDate": "2025-04-12T00:00:00Z", "city": "Москва"},
+				"receptions": [
+					{
+						"reception": {
+							"id": "rec-1",
+							"dateTime": "2025-04-12T00:00:00Z",
+							"pvzId": "`+pvzID+`",
+							"status": "in_progress",
+							"closedAt": null,
+							"closedBy": ""
+						},
+						"products": []
+					}
+				]
+			},
+			{
+				"pvz": {"id": "pvz-2", "registrationDate": "2025-04-12T00:00:00Z", "city": "Казань"},
+				"receptions": []
+			}
+		]`, rec.Body.String())
+	})
+
+	t.Run("missing claims in context", func(t *testing.T) {
+		pvzSvcMock := mocks.NewMockPVZService(ctrl)
+		handler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvcMock))
+
+		req := httptest.NewRequest(nethttp.MethodGet, "/pvz", nil)
+		rec := httptest.NewRecorder()
+
+		serve(handler, rec, req)
+
+		assert.Equal(t, nethttp.StatusUnauthorized, rec.Code)
+		var errResp httpcommon.ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+		assert.Equal(t, "no_auth", errResp.Code)
+	})
 }
 
 type createParamsMatcher struct {
@@ -546,3 +693,50 @@ func (m createParamsMatcher) Matches(x interface{}) bool {
 func (m createParamsMatcher) String() string {
 	return "matches application.CreateParams by value"
 }
+
+// serve sends request through routes of handler, as server mux does.
+func serve(handler *pvz_http.Handler, w nethttp.ResponseWriter, req *nethttp.Request) {
+	mux := nethttp.NewServeMux()
+	handler.RegisterRoutes(mux)
+	mux.ServeHTTP(w, req)
+}
+
+// json of REST clients, it must keep working with gateway.
+
+type createRequest struct {
+	ID               *string    `json:"id,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
+	City             string     `json:"city"`
+}
+
+type createResponse struct {
+	ID               *string    `json:"id,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
+	City             string     `json:"city"`
+}
+
+type closeResponse struct {
+	ID       string     `json:"id"`
+	DateTime time.Time  `json:"dateTime"`
+	PVZID    string     `json:"pvzId"`
+	Status   string     `json:"status"`
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+	ClosedBy string     `json:"closedBy,omitempty"`
+}
+
+type listResponse struct {
+	PVZ struct {
+		ID               string    `json:"id"`
+		RegistrationDate time.Time `json:"registrationDate"`
+		City             string    `json:"city"`
+	} `json:"pvz"`
+	Receptions []struct {
+		Reception closeResponse `json:"reception"`
+		Products  []struct {
+			ID          string    `json:"id"`
+			DateTime    time.Time `json:"dateTime"`
+			Type        string    `json:"type"`
+			ReceptionID string    `json:"receptionId"`
+		} `json:"products"`
+	} `json:"receptions"`
+}
@@ -34,6 +34,12 @@ type PVZ struct {
 	City             City
 }
 
+// PageKey is position in pvz list ordered by registration date and id.
+type PageKey struct {
+	RegistrationDate time.Time
+	ID               string
+}
+
 type PVZWithReceptions struct {
 	PVZ        *PVZ
 	Receptions []*ReceptionWithProducts
@@ -6,6 +6,7 @@ var (
 	// create
 	ErrPVZAlreadyExists = errors.New("pvz: pvz already exists")
 	ErrInternalDatabase = errors.New("pvz: internal database error")
+	ErrPVZNotFound      = errors.New("pvz: pvz not found")
 )
 
 var (
@@ -18,4 +19,6 @@ var (
 var (
 	// create
 	ErrInvalidIDFormat = errors.New("pvz: invalid id format")
+	// stream
+	ErrInvalidPageSize = errors.New("pvz: invalid page size")
 )
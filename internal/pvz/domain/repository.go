@@ -5,8 +5,19 @@ import (
 	"time"
 )
 
+// PVZRepository contract is checked by repotest.Run for every storage backend.
+// Errors wrap ErrPVZAlreadyExists, ErrPVZNotFound or ErrInternalDatabase,
+// lists are empty (not nil) if nothing is found.
 type PVZRepository interface {
 	Create(ctx context.Context, pvz *PVZ) (*PVZ, error)
+	GetByID(ctx context.Context, id string) (*PVZ, error)
 	ListAllPVZs(ctx context.Context) ([]*PVZ, error)
-	ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]*PVZWithReceptions, error)
+	// ListPage returns up to limit pvz after key (from the start if key is nil),
+	// oldest first. Keyset pages do not shift while new pvz are created.
+	// Non-empty pvzID restricts listing to that pvz.
+	ListPage(ctx context.Context, pvzID string, after *PageKey, limit int) ([]*PVZ, error)
+	// ListWithReceptions pages over receptions joined with alive products,
+	// newest pvz and receptions first. Non-empty pvzID restricts rows to that pvz
+	// before paging.
+	ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*PVZWithReceptions, error)
 }
@@ -0,0 +1,250 @@
+// Package cached decorates pvz repository with read-through cache of listings
+// and reception/product repositories with invalidation of that cache on writes.
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/cache"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	metricsLabel  = "pvz"
+	generationKey = "pvz:generation"
+)
+
+// Invalidator drops all cached pvz listings.
+type Invalidator interface {
+	Invalidate(ctx context.Context) error
+}
+
+// PVZRepository caches ListAllPVZs and ListWithReceptions for ttl.
+// Cache keys contain generation, Invalidate switches to new generation,
+// so all listings are dropped at once, in external cache for all replicas too.
+// Listing loaded before invalidation is stored under old generation and never read.
+// Cache errors are not returned, repository is queried instead.
+type PVZRepository struct {
+	next  pvz_domain.PVZRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewPVZRepository(next pvz_domain.PVZRepository, c cache.Cache, ttl time.Duration) *PVZRepository {
+	return &PVZRepository{next: next, cache: c, ttl: ttl}
+}
+
+func (r *PVZRepository) Create(ctx context.Context, pvz *pvz_domain.PVZ) (*pvz_domain.PVZ, error) {
+	created, err := r.next.Create(ctx, pvz)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r)
+
+	return created, nil
+}
+
+func (r *PVZRepository) GetByID(ctx context.Context, id string) (*pvz_domain.PVZ, error) {
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *PVZRepository) ListAllPVZs(ctx context.Context) ([]*pvz_domain.PVZ, error) {
+	return readThrough(ctx, r, "all", func() ([]*pvz_domain.PVZ, error) {
+		return r.next.ListAllPVZs(ctx)
+	})
+}
+
+// ListPage is not cached, pages are read once by streaming clients.
+func (r *PVZRepository) ListPage(ctx context.Context, pvzID string, after *pvz_domain.PageKey, limit int) ([]*pvz_domain.PVZ, error) {
+	return r.next.ListPage(ctx, pvzID, after, limit)
+}
+
+func (r *PVZRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*pvz_domain.PVZWithReceptions, error) {
+	key := fmt.Sprintf("list:%s:%s:%s:%d:%d", keyTime(startDate), keyTime(endDate), pvzID, page, limit)
+
+	return readThrough(ctx, r, key, func() ([]*pvz_domain.PVZWithReceptions, error) {
+		return r.next.ListWithReceptions(ctx, startDate, endDate, pvzID, page, limit)
+	})
+}
+
+func (r *PVZRepository) Invalidate(ctx context.Context) error {
+	_, err := r.newGeneration(ctx)
+	return err
+}
+
+func (r *PVZRepository) generation(ctx context.Context) (string, error) {
+	gen, ok, err := r.cache.Get(ctx, generationKey)
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		return string(gen), nil
+	}
+
+	// generation was never set or evicted
+	return r.newGeneration(ctx)
+}
+
+func (r *PVZRepository) newGeneration(ctx context.Context) (string, error) {
+	gen := uuid.NewString()
+	if err := r.cache.Set(ctx, generationKey, []byte(gen), 0); err != nil {
+		return "", err
+	}
+
+	return gen, nil
+}
+
+func readThrough[T any](ctx context.Context, r *PVZRepository, key string, load func() (T, error)) (T, error) {
+	gen, err := r.generation(ctx)
+	if err != nil {
+		metrics.CacheRequestsTotal.WithLabelValues(metricsLabel, "error").Inc()
+		return load()
+	}
+
+	key = "pvz:" + gen + ":" + key
+
+	raw, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		metrics.CacheRequestsTotal.WithLabelValues(metricsLabel, "error").Inc()
+		return load()
+	}
+
+	if ok {
+		var cached T
+		if err := json.Unmarshal(raw, &cached); err == nil {
+			metrics.CacheRequestsTotal.WithLabelValues(metricsLabel, "hit").Inc()
+			return cached, nil
+		}
+	}
+
+	metrics.CacheRequestsTotal.WithLabelValues(metricsLabel, "miss").Inc()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	if raw, err := json.Marshal(value); err == nil {
+		_ = r.cache.Set(ctx, key, raw, r.ttl)
+	}
+
+	return value, nil
+}
+
+func keyTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// invalidate is deferred until write is committed, otherwise listing can be
+// cached again before commit. Write is already done, so error is not returned
+// to caller, listings stay stale until ttl.
+func invalidate(ctx context.Context, inv Invalidator) {
+	ctx = context.WithoutCancel(ctx)
+	database.AfterCommit(ctx, func() {
+		if err := inv.Invalidate(ctx); err != nil {
+			metrics.CacheRequestsTotal.WithLabelValues(metricsLabel, "error").Inc()
+		}
+	})
+}
+
+// ReceptionRepository invalidates pvz listings after reception writes.
+type ReceptionRepository struct {
+	reception_domain.ReceptionRepository
+	invalidator Invalidator
+}
+
+func NewReceptionRepository(next reception_domain.ReceptionRepository, inv Invalidator) *ReceptionRepository {
+	return &ReceptionRepository{ReceptionRepository: next, invalidator: inv}
+}
+
+func (r *ReceptionRepository) Create(ctx context.Context, reception *reception_domain.Reception) (*reception_domain.Reception, error) {
+	created, err := r.ReceptionRepository.Create(ctx, reception)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r.invalidator)
+
+	return created, nil
+}
+
+func (r *ReceptionRepository) CloseLastReception(ctx context.Context, pvzID string, closedAt time.Time, closedBy string) (*reception_domain.Reception, error) {
+	closed, err := r.ReceptionRepository.CloseLastReception(ctx, pvzID, closedAt, closedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r.invalidator)
+
+	return closed, nil
+}
+
+func (r *ReceptionRepository) CloseStale(ctx context.Context, idleSince, closedAt time.Time) ([]*reception_domain.Reception, error) {
+	closed, err := r.ReceptionRepository.CloseStale(ctx, idleSince, closedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(closed) > 0 {
+		invalidate(ctx, r.invalidator)
+	}
+
+	return closed, nil
+}
+
+// ProductRepository invalidates pvz listings after product writes.
+type ProductRepository struct {
+	product_domain.ProductRepository
+	invalidator Invalidator
+}
+
+func NewProductRepository(next product_domain.ProductRepository, inv Invalidator) *ProductRepository {
+	return &ProductRepository{ProductRepository: next, invalidator: inv}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *product_domain.Product) (*product_domain.Product, error) {
+	created, err := r.ProductRepository.Create(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r.invalidator)
+
+	return created, nil
+}
+
+func (r *ProductRepository) DeleteLastFromReception(ctx context.Context, receptionID string, deletedAt time.Time, deletedBy string) (*product_domain.Product, error) {
+	deleted, err := r.ProductRepository.DeleteLastFromReception(ctx, receptionID, deletedAt, deletedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r.invalidator)
+
+	return deleted, nil
+}
+
+func (r *ProductRepository) Restore(ctx context.Context, id string) (*product_domain.Product, error) {
+	restored, err := r.ProductRepository.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidate(ctx, r.invalidator)
+
+	return restored, nil
+}
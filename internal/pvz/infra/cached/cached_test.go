@@ -0,0 +1,242 @@
+package cached_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/cache"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	product_mocks "github.com/0x0FACED/pvz-avito/internal/product/mocks"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pvz/infra/cached"
+	pvz_mocks "github.com/0x0FACED/pvz-avito/internal/pvz/mocks"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_mocks "github.com/0x0FACED/pvz-avito/internal/reception/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func listing() []*pvz_domain.PVZWithReceptions {
+	id := uuid.NewString()
+	registered := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	return []*pvz_domain.PVZWithReceptions{{
+		PVZ: &pvz_domain.PVZ{ID: &id, RegistrationDate: &registered, City: pvz_domain.Kazan},
+		Receptions: []*pvz_domain.ReceptionWithProducts{{
+			Reception: &reception_domain.Reception{ID: uuid.NewString(), DateTime: registered, PVZID: id, Status: reception_domain.InProgress},
+			Products:  []*product_domain.Product{},
+		}},
+	}}
+}
+
+func TestPVZRepository_ListWithReceptions(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("second call is served from cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := pvz_mocks.NewMockPVZRepository(ctrl)
+		repo := cached.NewPVZRepository(next, cache.NewLRU(16), time.Minute)
+
+		expected := listing()
+		next.EXPECT().ListWithReceptions(gomock.Any(), &from, &to, "", 1, 10).Return(expected, nil).Times(1)
+
+		for range 2 {
+			result, err := repo.ListWithReceptions(ctx, &from, &to, "", 1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		}
+	})
+
+	t.Run("params are part of key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := pvz_mocks.NewMockPVZRepository(ctrl)
+		repo := cached.NewPVZRepository(next, cache.NewLRU(16), time.Minute)
+
+		next.EXPECT().ListWithReceptions(gomock.Any(), &from, &to, "", 1, 10).Return(listing(), nil)
+		next.EXPECT().ListWithReceptions(gomock.Any(), &from, &to, "", 2, 10).Return(listing(), nil)
+		next.EXPECT().ListWithReceptions(gomock.Any(), nil, nil, "", 1, 10).Return(listing(), nil)
+		next.EXPECT().ListWithReceptions(gomock.Any(), nil, nil, "pvz-1", 1, 10).Return(listing(), nil)
+
+		_, err := repo.ListWithReceptions(ctx, &from, &to, "", 1, 10)
+		require.NoError(t, err)
+		_, err = repo.ListWithReceptions(ctx, &from, &to, "", 2, 10)
+		require.NoError(t, err)
+		_, err = repo.ListWithReceptions(ctx, nil, nil, "", 1, 10)
+		require.NoError(t, err)
+		_, err = repo.ListWithReceptions(ctx, nil, nil, "pvz-1", 1, 10)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := pvz_mocks.NewMockPVZRepository(ctrl)
+		repo := cached.NewPVZRepository(next, cache.NewLRU(16), time.Minute)
+
+		gomock.InOrder(
+			next.EXPECT().ListWithReceptions(gomock.Any(), nil, nil, "", 1, 10).Return(nil, pvz_domain.ErrInternalDatabase),
+			next.EXPECT().ListWithReceptions(gomock.Any(), nil, nil, "", 1, 10).Return(listing(), nil),
+		)
+
+		_, err := repo.ListWithReceptions(ctx, nil, nil, "", 1, 10)
+		assert.ErrorIs(t, err, pvz_domain.ErrInternalDatabase)
+
+		result, err := repo.ListWithReceptions(ctx, nil, nil, "", 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("broken cache falls back to repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := pvz_mocks.NewMockPVZRepository(ctrl)
+		repo := cached.NewPVZRepository(next, brokenCache{}, time.Minute)
+
+		next.EXPECT().ListWithReceptions(gomock.Any(), nil, nil, "", 1, 10).Return(listing(), nil).Times(2)
+
+		for range 2 {
+			result, err := repo.ListWithReceptions(ctx, nil, nil, "", 1, 10)
+			require.NoError(t, err)
+			assert.Len(t, result, 1)
+		}
+	})
+}
+
+func TestPVZRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		write func(t *testing.T, pvz *pvz_mocks.MockPVZRepository, receptions *reception_mocks.MockReceptionRepository, products *product_mocks.MockProductRepository, repo *cached.PVZRepository)
+		// invalidates is false if write failed or changed nothing
+		invalidates bool
+	}{
+		{
+			name: "pvz created",
+			write: func(t *testing.T, pvz *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, _ *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				pvz.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&pvz_domain.PVZ{}, nil)
+				_, err := repo.Create(ctx, &pvz_domain.PVZ{})
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "pvz create failed",
+			write: func(t *testing.T, pvz *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, _ *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				pvz.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, pvz_domain.ErrPVZAlreadyExists)
+				_, err := repo.Create(ctx, &pvz_domain.PVZ{})
+				require.Error(t, err)
+			},
+			invalidates: false,
+		},
+		{
+			name: "reception opened",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, receptions *reception_mocks.MockReceptionRepository, _ *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				receptions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&reception_domain.Reception{}, nil)
+				_, err := cached.NewReceptionRepository(receptions, repo).Create(ctx, &reception_domain.Reception{})
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "reception closed",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, receptions *reception_mocks.MockReceptionRepository, _ *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				receptions.EXPECT().CloseLastReception(gomock.Any(), "pvz", now, "").Return(&reception_domain.Reception{}, nil)
+				_, err := cached.NewReceptionRepository(receptions, repo).CloseLastReception(ctx, "pvz", now, "")
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "no stale receptions",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, receptions *reception_mocks.MockReceptionRepository, _ *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				receptions.EXPECT().CloseStale(gomock.Any(), now, now).Return([]*reception_domain.Reception{}, nil)
+				_, err := cached.NewReceptionRepository(receptions, repo).CloseStale(ctx, now, now)
+				require.NoError(t, err)
+			},
+			invalidates: false,
+		},
+		{
+			name: "product added",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, products *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				products.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&product_domain.Product{}, nil)
+				_, err := cached.NewProductRepository(products, repo).Create(ctx, &product_domain.Product{})
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "product deleted",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, products *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				products.EXPECT().DeleteLastFromReception(gomock.Any(), "reception", now, "").Return(&product_domain.Product{}, nil)
+				_, err := cached.NewProductRepository(products, repo).DeleteLastFromReception(ctx, "reception", now, "")
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "product restored",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, products *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				products.EXPECT().Restore(gomock.Any(), "product").Return(&product_domain.Product{}, nil)
+				_, err := cached.NewProductRepository(products, repo).Restore(ctx, "product")
+				require.NoError(t, err)
+			},
+			invalidates: true,
+		},
+		{
+			name: "product restore failed",
+			write: func(t *testing.T, _ *pvz_mocks.MockPVZRepository, _ *reception_mocks.MockReceptionRepository, products *product_mocks.MockProductRepository, repo *cached.PVZRepository) {
+				products.EXPECT().Restore(gomock.Any(), "product").Return(nil, product_domain.ErrProductNotFound)
+				_, err := cached.NewProductRepository(products, repo).Restore(ctx, "product")
+				require.Error(t, err)
+			},
+			invalidates: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pvz := pvz_mocks.NewMockPVZRepository(ctrl)
+			receptions := reception_mocks.NewMockReceptionRepository(ctrl)
+			products := product_mocks.NewMockProductRepository(ctrl)
+			repo := cached.NewPVZRepository(pvz, cache.NewLRU(16), time.Minute)
+
+			loads := 1
+			if tt.invalidates {
+				loads = 2
+			}
+			pvz.EXPECT().ListAllPVZs(gomock.Any()).Return([]*pvz_domain.PVZ{}, nil).Times(loads)
+
+			_, err := repo.ListAllPVZs(ctx)
+			require.NoError(t, err)
+
+			tt.write(t, pvz, receptions, products, repo)
+
+			_, err = repo.ListAllPVZs(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
+
+// brokenCache is unavailable external cache.
+type brokenCache struct{}
+
+var errCacheDown = errors.New("cache is down")
+
+func (brokenCache) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errCacheDown
+}
+
+func (brokenCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errCacheDown
+}
+
+func (brokenCache) Delete(context.Context, string) error {
+	return errCacheDown
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"github.com/google/uuid"
+)
+
+// PVZMemoryRepository keeps pvz in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type PVZMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewPVZMemoryRepository(db *memdb.DB) *PVZMemoryRepository {
+	return &PVZMemoryRepository{db: db}
+}
+
+func (r *PVZMemoryRepository) Create(_ context.Context, pvz *pvz_domain.PVZ) (*pvz_domain.PVZ, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := clonePVZ(pvz)
+	// same defaults as table columns
+	if row.ID == nil {
+		id := uuid.NewString()
+		row.ID = &id
+	}
+	if row.RegistrationDate == nil {
+		now := time.Now()
+		row.RegistrationDate = &now
+	}
+
+	if r.db.PVZ(*row.ID) != nil {
+		return nil, fmt.Errorf("%w: id %s", pvz_domain.ErrPVZAlreadyExists, *row.ID)
+	}
+
+	r.db.PVZs = append(r.db.PVZs, row)
+
+	return clonePVZ(row), nil
+}
+
+func (r *PVZMemoryRepository) GetByID(_ context.Context, id string) (*pvz_domain.PVZ, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	row := r.db.PVZ(id)
+	if row == nil {
+		return nil, fmt.Errorf("%w: id %s", pvz_domain.ErrPVZNotFound, id)
+	}
+
+	return clonePVZ(row), nil
+}
+
+func (r *PVZMemoryRepository) ListAllPVZs(_ context.Context) ([]*pvz_domain.PVZ, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	pvzs := make([]*pvz_domain.PVZ, 0, len(r.db.PVZs))
+	for _, p := range r.db.PVZs {
+		pvzs = append(pvzs, clonePVZ(p))
+	}
+
+	return pvzs, nil
+}
+
+func (r *PVZMemoryRepository) ListPage(_ context.Context, pvzID string, after *pvz_domain.PageKey, limit int) ([]*pvz_domain.PVZ, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	sorted := slices.Clone(r.db.PVZs)
+	slices.SortFunc(sorted, func(a, b *pvz_domain.PVZ) int {
+		return comparePageKeys(pageKey(a), pageKey(b))
+	})
+
+	pvzs := make([]*pvz_domain.PVZ, 0, limit)
+	for _, p := range sorted {
+		if len(pvzs) == limit {
+			break
+		}
+		if after != nil && comparePageKeys(pageKey(p), *after) <= 0 {
+			continue
+		}
+		if pvzID != "" && *p.ID != pvzID {
+			continue
+		}
+
+		pvzs = append(pvzs, clonePVZ(p))
+	}
+
+	return pvzs, nil
+}
+
+func pageKey(p *pvz_domain.PVZ) pvz_domain.PageKey {
+	return pvz_domain.PageKey{RegistrationDate: *p.RegistrationDate, ID: *p.ID}
+}
+
+// comparePageKeys orders pvz by registration date and id, same as ListPage query.
+func comparePageKeys(a, b pvz_domain.PageKey) int {
+	if c := a.RegistrationDate.Compare(b.RegistrationDate); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+// listRow is one row of receptions joined with pvz and alive products,
+// paging is applied to rows, same as in postgres query.
+type listRow struct {
+	pvz       *pvz_domain.PVZ
+	reception *reception_domain.Reception
+	product   *product_domain.Product
+}
+
+func (r *PVZMemoryRepository) ListWithReceptions(_ context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*pvz_domain.PVZWithReceptions, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	var rows []listRow
+	for _, reception := range r.db.Receptions {
+		// BETWEEN with null end date matches nothing in postgres
+		if startDate != nil && (endDate == nil || reception.DateTime.Before(*startDate) || reception.DateTime.After(*endDate)) {
+			continue
+		}
+		if pvzID != "" && reception.PVZID != pvzID {
+			continue
+		}
+
+		pvz := r.db.PVZ(reception.PVZID)
+		products := r.db.AliveProducts(reception.ID)
+		if len(products) == 0 {
+			rows = append(rows, listRow{pvz: pvz, reception: reception})
+		}
+		for _, product := range products {
+			rows = append(rows, listRow{pvz: pvz, reception: reception, product: product})
+		}
+	}
+
+	memdb.SortNewestFirst(rows, func(row listRow) time.Time { return row.reception.DateTime })
+	memdb.SortNewestFirst(rows, func(row listRow) time.Time { return *row.pvz.RegistrationDate })
+
+	offset := (page - 1) * limit
+	if offset < 0 || offset >= len(rows) {
+		rows = nil
+	} else {
+		rows = rows[offset:min(offset+limit, len(rows))]
+	}
+
+	result := make([]*pvz_domain.PVZWithReceptions, 0)
+	pvzs := make(map[string]*pvz_domain.PVZWithReceptions)
+	receptions := make(map[string]*pvz_domain.ReceptionWithProducts)
+	for _, row := range rows {
+		item, ok := pvzs[*row.pvz.ID]
+		if !ok {
+			item = &pvz_domain.PVZWithReceptions{
+				PVZ:        clonePVZ(row.pvz),
+				Receptions: []*pvz_domain.ReceptionWithProducts{},
+			}
+			pvzs[*row.pvz.ID] = item
+			result = append(result, item)
+		}
+
+		reception, ok := receptions[row.reception.ID]
+		if !ok {
+			rc := *row.reception
+			reception = &pvz_domain.ReceptionWithProducts{
+				Reception: &rc,
+				Products:  []*product_domain.Product{},
+			}
+			receptions[row.reception.ID] = reception
+			item.Receptions = append(item.Receptions, reception)
+		}
+
+		if row.product != nil {
+			reception.Products = append(reception.Products, &product_domain.Product{
+				ID:          row.product.ID,
+				DateTime:    row.product.DateTime,
+				Type:        row.product.Type,
+				ReceptionID: row.product.ReceptionID,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func clonePVZ(p *pvz_domain.PVZ) *pvz_domain.PVZ {
+	c := &pvz_domain.PVZ{City: p.City}
+	if p.ID != nil {
+		id := *p.ID
+		c.ID = &id
+	}
+	if p.RegistrationDate != nil {
+		date := *p.RegistrationDate
+		c.RegistrationDate = &date
+	}
+
+	return c
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
 	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
@@ -36,29 +37,51 @@ func (r *PVZPostgresRepository) Create(ctx context.Context, pvz *pvz_domain.PVZ)
 	}
 
 	var created pvz_domain.PVZ
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&created.ID, &created.RegistrationDate, &created.City,
 	)
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr); pgErr.Code == "23505" {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrPVZAlreadyExists, err)
 		}
-		// in openapi wrote that 201 and 400 codes only.
-		// so using domain.ErrInternalDatabase as invalid request (400)
+		// other failures are not caused by request, they are reported as internal (500)
 		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
 	}
 
 	return &created, nil
 }
 
+func (r *PVZPostgresRepository) GetByID(ctx context.Context, id string) (*pvz_domain.PVZ, error) {
+	query := `
+		SELECT id, registration_date, city
+		FROM avito.pvz
+		WHERE id = @id
+	`
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var p pvz_domain.PVZ
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(&p.ID, &p.RegistrationDate, &p.City)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrPVZNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return &p, nil
+}
+
 func (r *PVZPostgresRepository) ListAllPVZs(ctx context.Context) ([]*pvz_domain.PVZ, error) {
 	query := `
 		SELECT id, registration_date, city
 		FROM avito.pvz
 	`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return []*pvz_domain.PVZ{}, nil
@@ -67,7 +90,7 @@ func (r *PVZPostgresRepository) ListAllPVZs(ctx context.Context) ([]*pvz_domain.
 	}
 	defer rows.Close()
 
-	var pvzs []*pvz_domain.PVZ
+	pvzs := make([]*pvz_domain.PVZ, 0)
 	for rows.Next() {
 		var p pvz_domain.PVZ
 		err := rows.Scan(
@@ -89,15 +112,60 @@ func (r *PVZPostgresRepository) ListAllPVZs(ctx context.Context) ([]*pvz_domain.
 	return pvzs, nil
 }
 
-func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]*pvz_domain.PVZWithReceptions, error) {
+func (r *PVZPostgresRepository) ListPage(ctx context.Context, pvzID string, after *pvz_domain.PageKey, limit int) ([]*pvz_domain.PVZ, error) {
+	query := `
+		SELECT id, registration_date, city
+		FROM avito.pvz
+		WHERE (@after_id::uuid IS NULL OR (registration_date, id) > (@after_date, @after_id::uuid))
+		  AND (@pvz_id::uuid IS NULL OR id = @pvz_id::uuid)
+		ORDER BY registration_date, id
+		LIMIT @limit
+	`
+
+	args := pgx.NamedArgs{
+		"after_date": nil,
+		"after_id":   nil,
+		"pvz_id":     nullString(pvzID),
+		"limit":      limit,
+	}
+	if after != nil {
+		args["after_date"] = after.RegistrationDate
+		args["after_id"] = after.ID
+	}
+
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	pvzs := make([]*pvz_domain.PVZ, 0, limit)
+	for rows.Next() {
+		var p pvz_domain.PVZ
+		if err := rows.Scan(&p.ID, &p.RegistrationDate, &p.City); err != nil {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+		}
+
+		pvzs = append(pvzs, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return pvzs, nil
+}
+
+func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*pvz_domain.PVZWithReceptions, error) {
 	query := `
 		SELECT p.id, p.registration_date, p.city,
-		       r.id, r.date_time, r.pvz_id, r.status,
+		       r.id, r.date_time, r.pvz_id, r.status, r.closed_at, r.closed_by,
 		       pr.id, pr.date_time, pr.type, pr.reception_id
 		FROM avito.pvz p
 		RIGHT JOIN avito.receptions r ON r.pvz_id = p.id
-		LEFT JOIN avito.products pr ON pr.reception_id = r.id
+		LEFT JOIN avito.products pr ON pr.reception_id = r.id AND pr.deleted_at IS NULL
 		WHERE (r.date_time BETWEEN @start_date AND @end_date OR @start_date IS NULL)
+		  AND (@pvz_id::uuid IS NULL OR r.pvz_id = @pvz_id::uuid)
 		ORDER BY p.registration_date DESC, r.date_time DESC
 		LIMIT @limit OFFSET @offset
 	`
@@ -106,17 +174,20 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 	args := pgx.NamedArgs{
 		"start_date": startDate,
 		"end_date":   endDate,
+		"pvz_id":     nullString(pvzID),
 		"limit":      limit,
 		"offset":     offset,
 	}
 
-	rows, err := r.pool.Query(ctx, query, args)
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
 	}
 	defer rows.Close()
 
-	result := make(map[string]*pvz_domain.PVZWithReceptions)
+	// pvzs are kept in order of rows, newest first
+	result := make([]*pvz_domain.PVZWithReceptions, 0)
+	pvzsMap := make(map[string]*pvz_domain.PVZWithReceptions)
 	receptionsMap := make(map[string]*pvz_domain.ReceptionWithProducts)
 
 	for rows.Next() {
@@ -128,6 +199,8 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 			receptionDT      *time.Time
 			receptionPVZID   *string
 			receptionStatus  *reception_domain.Status
+			receptionClosed  *time.Time
+			receptionCloser  *string
 			productID        *string
 			productDT        *time.Time
 			productType      *product_domain.ProductType
@@ -136,15 +209,15 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 
 		err := rows.Scan(
 			&pvzID, &pvzRegDate, &pvzCity,
-			&receptionID, &receptionDT, &receptionPVZID, &receptionStatus,
+			&receptionID, &receptionDT, &receptionPVZID, &receptionStatus, &receptionClosed, &receptionCloser,
 			&productID, &productDT, &productType, &productReception,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
 		}
 
-		if _, ok := result[pvzID]; !ok {
-			result[pvzID] = &pvz_domain.PVZWithReceptions{
+		if _, ok := pvzsMap[pvzID]; !ok {
+			pvzsMap[pvzID] = &pvz_domain.PVZWithReceptions{
 				PVZ: &pvz_domain.PVZ{
 					ID:               &pvzID,
 					RegistrationDate: &pvzRegDate,
@@ -152,6 +225,7 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 				},
 				Receptions: []*pvz_domain.ReceptionWithProducts{},
 			}
+			result = append(result, pvzsMap[pvzID])
 		}
 
 		if receptionID != nil {
@@ -163,10 +237,14 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 						DateTime: *receptionDT,
 						PVZID:    *receptionPVZID,
 						Status:   *receptionStatus,
+						ClosedAt: receptionClosed,
 					},
 					Products: []*product_domain.Product{},
 				}
-				result[pvzID].Receptions = append(result[pvzID].Receptions, receptionsMap[receptionKey])
+				if receptionCloser != nil {
+					receptionsMap[receptionKey].Reception.ClosedBy = *receptionCloser
+				}
+				pvzsMap[pvzID].Receptions = append(pvzsMap[pvzID].Receptions, receptionsMap[receptionKey])
 			}
 
 			if productID != nil {
@@ -184,10 +262,13 @@ func (r *PVZPostgresRepository) ListWithReceptions(ctx context.Context, startDat
 		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
 	}
 
-	finalResult := make([]*pvz_domain.PVZWithReceptions, 0, len(result))
-	for _, v := range result {
-		finalResult = append(finalResult, v)
-	}
+	return result, nil
+}
 
-	return finalResult, nil
+// nullString passes empty filter as NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
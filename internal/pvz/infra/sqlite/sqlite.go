@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type PVZSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewPVZSQLiteRepository(db *sql.DB) *PVZSQLiteRepository {
+	return &PVZSQLiteRepository{db: db}
+}
+
+func (r *PVZSQLiteRepository) Create(ctx context.Context, pvz *pvz_domain.PVZ) (*pvz_domain.PVZ, error) {
+	query := `
+		INSERT INTO pvz (id, registration_date, city)
+		VALUES (@id, @registration_date, @city)
+		RETURNING id, registration_date, city
+	`
+
+	var created pvz_domain.PVZ
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query,
+		sql.Named("id", pvz.ID),
+		sql.Named("registration_date", database.SQLiteNullTime(pvz.RegistrationDate)),
+		sql.Named("city", pvz.City.String()),
+	).Scan(&created.ID, &created.RegistrationDate, &created.City)
+	if err != nil {
+		switch database.SQLiteErrorCode(err) {
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrPVZAlreadyExists, err)
+		}
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return &created, nil
+}
+
+func (r *PVZSQLiteRepository) GetByID(ctx context.Context, id string) (*pvz_domain.PVZ, error) {
+	query := `
+		SELECT id, registration_date, city
+		FROM pvz
+		WHERE id = @id
+	`
+
+	var p pvz_domain.PVZ
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("id", id)).Scan(&p.ID, &p.RegistrationDate, &p.City)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrPVZNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return &p, nil
+}
+
+func (r *PVZSQLiteRepository) ListAllPVZs(ctx context.Context) ([]*pvz_domain.PVZ, error) {
+	query := `
+		SELECT id, registration_date, city
+		FROM pvz
+	`
+
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	pvzs := make([]*pvz_domain.PVZ, 0)
+	for rows.Next() {
+		var p pvz_domain.PVZ
+		if err := rows.Scan(&p.ID, &p.RegistrationDate, &p.City); err != nil {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+		}
+
+		pvzs = append(pvzs, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return pvzs, nil
+}
+
+// ListPage reads pvz in (registration_date, id) order, same as postgres repository.
+func (r *PVZSQLiteRepository) ListPage(ctx context.Context, pvzID string, after *pvz_domain.PageKey, limit int) ([]*pvz_domain.PVZ, error) {
+	query := `
+		SELECT id, registration_date, city
+		FROM pvz
+		WHERE (@after_id IS NULL OR (registration_date, id) > (@after_date, @after_id))
+		  AND (@pvz_id = '' OR id = @pvz_id)
+		ORDER BY registration_date, id
+		LIMIT @limit
+	`
+
+	var afterDate, afterID any
+	if after != nil {
+		afterDate = database.SQLiteTime(after.RegistrationDate)
+		afterID = after.ID
+	}
+
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query,
+		sql.Named("after_date", afterDate),
+		sql.Named("after_id", afterID),
+		sql.Named("pvz_id", pvzID),
+		sql.Named("limit", limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	pvzs := make([]*pvz_domain.PVZ, 0, limit)
+	for rows.Next() {
+		var p pvz_domain.PVZ
+		if err := rows.Scan(&p.ID, &p.RegistrationDate, &p.City); err != nil {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+		}
+
+		pvzs = append(pvzs, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return pvzs, nil
+}
+
+// ListWithReceptions uses same join and paging as postgres repository.
+func (r *PVZSQLiteRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*pvz_domain.PVZWithReceptions, error) {
+	query := `
+		SELECT p.id, p.registration_date, p.city,
+		       r.id, r.date_time, r.pvz_id, r.status, r.closed_at, r.closed_by,
+		       pr.id, pr.date_time, pr.type, pr.reception_id
+		FROM pvz p
+		RIGHT JOIN receptions r ON r.pvz_id = p.id
+		LEFT JOIN products pr ON pr.reception_id = r.id AND pr.deleted_at IS NULL
+		WHERE (r.date_time BETWEEN @start_date AND @end_date OR @start_date IS NULL)
+		  AND (@pvz_id = '' OR r.pvz_id = @pvz_id)
+		ORDER BY p.registration_date DESC, r.date_time DESC
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query,
+		sql.Named("start_date", database.SQLiteNullTime(startDate)),
+		sql.Named("end_date", database.SQLiteNullTime(endDate)),
+		sql.Named("pvz_id", pvzID),
+		sql.Named("limit", limit),
+		sql.Named("offset", (page-1)*limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	// pvzs are kept in order of rows, newest first
+	result := make([]*pvz_domain.PVZWithReceptions, 0)
+	pvzsMap := make(map[string]*pvz_domain.PVZWithReceptions)
+	receptionsMap := make(map[string]*pvz_domain.ReceptionWithProducts)
+
+	for rows.Next() {
+		var (
+			pvzID            string
+			pvzRegDate       time.Time
+			pvzCity          pvz_domain.City
+			receptionID      *string
+			receptionDT      *time.Time
+			receptionPVZID   *string
+			receptionStatus  *reception_domain.Status
+			receptionClosed  *time.Time
+			receptionCloser  *string
+			productID        *string
+			productDT        *time.Time
+			productType      *product_domain.ProductType
+			productReception *string
+		)
+
+		err := rows.Scan(
+			&pvzID, &pvzRegDate, &pvzCity,
+			&receptionID, &receptionDT, &receptionPVZID, &receptionStatus, &receptionClosed, &receptionCloser,
+			&productID, &productDT, &productType, &productReception,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+		}
+
+		if _, ok := pvzsMap[pvzID]; !ok {
+			pvzsMap[pvzID] = &pvz_domain.PVZWithReceptions{
+				PVZ: &pvz_domain.PVZ{
+					ID:               &pvzID,
+					RegistrationDate: &pvzRegDate,
+					City:             pvzCity,
+				},
+				Receptions: []*pvz_domain.ReceptionWithProducts{},
+			}
+			result = append(result, pvzsMap[pvzID])
+		}
+
+		if receptionID == nil {
+			continue
+		}
+
+		reception, ok := receptionsMap[*receptionID]
+		if !ok {
+			reception = &pvz_domain.ReceptionWithProducts{
+				Reception: &reception_domain.Reception{
+					ID:       *receptionID,
+					DateTime: *receptionDT,
+					PVZID:    *receptionPVZID,
+					Status:   *receptionStatus,
+					ClosedAt: receptionClosed,
+				},
+				Products: []*product_domain.Product{},
+			}
+			if receptionCloser != nil {
+				reception.Reception.ClosedBy = *receptionCloser
+			}
+			receptionsMap[*receptionID] = reception
+			pvzsMap[pvzID].Receptions = append(pvzsMap[pvzID].Receptions, reception)
+		}
+
+		if productID != nil {
+			reception.Products = append(reception.Products, &product_domain.Product{
+				ID:          *productID,
+				DateTime:    *productDT,
+				Type:        *productType,
+				ReceptionID: *productReception,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", pvz_domain.ErrInternalDatabase, err)
+	}
+
+	return result, nil
+}
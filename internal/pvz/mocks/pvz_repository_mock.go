@@ -57,6 +57,21 @@ func (mr *MockPVZRepositoryMockRecorder) Create(ctx, pvz any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPVZRepository)(nil).Create), ctx, pvz)
 }
 
+// GetByID mocks base method.
+func (m *MockPVZRepository) GetByID(ctx context.Context, id string) (*domain.PVZ, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPVZRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPVZRepository)(nil).GetByID), ctx, id)
+}
+
 // ListAllPVZs mocks base method.
 func (m *MockPVZRepository) ListAllPVZs(ctx context.Context) ([]*domain.PVZ, error) {
 	m.ctrl.T.Helper()
@@ -72,17 +87,32 @@ func (mr *MockPVZRepositoryMockRecorder) ListAllPVZs(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllPVZs", reflect.TypeOf((*MockPVZRepository)(nil).ListAllPVZs), ctx)
 }
 
+// ListPage mocks base method.
+func (m *MockPVZRepository) ListPage(ctx context.Context, pvzID string, after *domain.PageKey, limit int) ([]*domain.PVZ, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPage", ctx, pvzID, after, limit)
+	ret0, _ := ret[0].([]*domain.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPage indicates an expected call of ListPage.
+func (mr *MockPVZRepositoryMockRecorder) ListPage(ctx, pvzID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockPVZRepository)(nil).ListPage), ctx, pvzID, after, limit)
+}
+
 // ListWithReceptions mocks base method.
-func (m *MockPVZRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]*domain.PVZWithReceptions, error) {
+func (m *MockPVZRepository) ListWithReceptions(ctx context.Context, startDate, endDate *time.Time, pvzID string, page, limit int) ([]*domain.PVZWithReceptions, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListWithReceptions", ctx, startDate, endDate, page, limit)
+	ret := m.ctrl.Call(m, "ListWithReceptions", ctx, startDate, endDate, pvzID, page, limit)
 	ret0, _ := ret[0].([]*domain.PVZWithReceptions)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListWithReceptions indicates an expected call of ListWithReceptions.
-func (mr *MockPVZRepositoryMockRecorder) ListWithReceptions(ctx, startDate, endDate, page, limit any) *gomock.Call {
+func (mr *MockPVZRepositoryMockRecorder) ListWithReceptions(ctx, startDate, endDate, pvzID, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithReceptions", reflect.TypeOf((*MockPVZRepository)(nil).ListWithReceptions), ctx, startDate, endDate, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithReceptions", reflect.TypeOf((*MockPVZRepository)(nil).ListWithReceptions), ctx, startDate, endDate, pvzID, page, limit)
 }
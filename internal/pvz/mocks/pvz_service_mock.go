@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/pvz/delivery/http/handler.go
+// Source: internal/pvz/delivery/grpc/handler.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/pvz/delivery/http/handler.go -destination=internal/pvz/mocks/pvz_service_mock.go -package=mocks
+//	mockgen -source=internal/pvz/delivery/grpc/handler.go -destination=internal/pvz/mocks/pvz_service_mock.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -87,6 +87,21 @@ func (mr *MockPVZServiceMockRecorder) DeleteLastProduct(ctx, params any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockPVZService)(nil).DeleteLastProduct), ctx, params)
 }
 
+// ListAllPVZs mocks base method.
+func (m *MockPVZService) ListAllPVZs(ctx context.Context) ([]*domain.PVZ, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllPVZs", ctx)
+	ret0, _ := ret[0].([]*domain.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllPVZs indicates an expected call of ListAllPVZs.
+func (mr *MockPVZServiceMockRecorder) ListAllPVZs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllPVZs", reflect.TypeOf((*MockPVZService)(nil).ListAllPVZs), ctx)
+}
+
 // ListWithReceptions mocks base method.
 func (m *MockPVZService) ListWithReceptions(ctx context.Context, params application.ListWithReceptionsParams) ([]*domain.PVZWithReceptions, error) {
 	m.ctrl.T.Helper()
@@ -101,3 +116,17 @@ func (mr *MockPVZServiceMockRecorder) ListWithReceptions(ctx, params any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithReceptions", reflect.TypeOf((*MockPVZService)(nil).ListWithReceptions), ctx, params)
 }
+
+// StreamPVZs mocks base method.
+func (m *MockPVZService) StreamPVZs(ctx context.Context, params application.StreamParams, send func(*domain.PVZWithReceptions) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPVZs", ctx, params, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPVZs indicates an expected call of StreamPVZs.
+func (mr *MockPVZServiceMockRecorder) StreamPVZs(ctx, params, send any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPVZs", reflect.TypeOf((*MockPVZService)(nil).StreamPVZs), ctx, params, send)
+}
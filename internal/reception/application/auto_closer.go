@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// AutoCloser periodically closes receptions that stay in progress
+// without any activity longer than idleTimeout.
+type AutoCloser struct {
+	repo     reception_domain.ReceptionRepository
+	auditor  audit_domain.Auditor
+	observer ClosedReceptionObserver
+
+	idleTimeout time.Duration
+	interval    time.Duration
+
+	log *logger.ZerologLogger
+}
+
+// ClosedReceptionObserver writes duration and products count metrics of closed
+// reception, implemented by pvz service, which knows city and products of reception.
+type ClosedReceptionObserver interface {
+	ObserveClosedReception(ctx context.Context, reception *reception_domain.Reception)
+}
+
+// systemActorRole marks audit entries made by background jobs.
+const systemActorRole = "system"
+
+func NewAutoCloser(
+	repo reception_domain.ReceptionRepository,
+	auditor audit_domain.Auditor,
+	observer ClosedReceptionObserver,
+	idleTimeout, interval time.Duration,
+	l *logger.ZerologLogger,
+) *AutoCloser {
+	return &AutoCloser{
+		repo:        repo,
+		auditor:     auditor,
+		observer:    observer,
+		idleTimeout: idleTimeout,
+		interval:    interval,
+		log:         l,
+	}
+}
+
+// Run closes stale receptions every interval until ctx is done.
+func (c *AutoCloser) Run(ctx context.Context) error {
+	c.log.Info().Dur("idle_timeout", c.idleTimeout).Dur("interval", c.interval).Msg("Reception auto closer started")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Info().Msg("Reception auto closer stopped")
+			return nil
+		case <-ticker.C:
+			_, _ = c.CloseStale(ctx)
+		}
+	}
+}
+
+// CloseStale runs one pass of closing stale receptions.
+// Returns nil slice without error if another instance holds the lock.
+func (c *AutoCloser) CloseStale(ctx context.Context) ([]*reception_domain.Reception, error) {
+	now := time.Now()
+	idleSince := now.Add(-c.idleTimeout)
+
+	closed, err := c.repo.CloseStale(ctx, idleSince, now)
+	if err != nil {
+		if errors.Is(err, reception_domain.ErrCloseStaleLocked) {
+			c.log.Debug().Msg("Stale receptions are being closed by another instance, skipping")
+			return nil, nil
+		}
+		c.log.Error().Time("idle_since", idleSince).Err(err).Msg("Error closing stale receptions")
+		return nil, err
+	}
+
+	for _, reception := range closed {
+		metrics.ReceptionAutoClosedTotal.Inc()
+		c.observer.ObserveClosedReception(ctx, reception)
+
+		c.log.Warn().
+			Str("reception_id", reception.ID).
+			Str("pvz_id", reception.PVZID).
+			Time("opened_at", reception.DateTime).
+			Dur("duration", reception.Duration()).
+			Str("reason", reception.CloseReason.String()).
+			Msg("Reception closed automatically")
+
+		c.auditor.Record(ctx, audit_domain.Event{
+			Action:     audit_domain.ActionReceptionClose,
+			EntityType: audit_domain.EntityReception,
+			EntityID:   reception.ID,
+			After:      reception,
+			ActorRole:  systemActorRole,
+		})
+	}
+
+	return closed, nil
+}
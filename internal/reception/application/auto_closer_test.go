@@ -0,0 +1,136 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_mocks "github.com/0x0FACED/pvz-avito/internal/reception/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAutoCloser_CloseStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idleTimeout := 2 * time.Hour
+
+	stale := &reception_domain.Reception{
+		ID:          uuid.NewString(),
+		DateTime:    time.Now().Add(-3 * time.Hour),
+		PVZID:       uuid.NewString(),
+		Status:      reception_domain.Close,
+		CloseReason: reception_domain.CloseReasonAutoClosed,
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(*reception_mocks.MockReceptionRepository)
+		expectCount int
+		expectErr   error
+	}{
+		{
+			name: "stale receptions closed",
+			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+				r.EXPECT().
+					CloseStale(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, idleSince, _ time.Time) ([]*reception_domain.Reception, error) {
+						assert.WithinDuration(t, time.Now().Add(-idleTimeout), idleSince, time.Minute)
+						return []*reception_domain.Reception{stale}, nil
+					})
+			},
+			expectCount: 1,
+		},
+		{
+			name: "nothing to close",
+			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+				r.EXPECT().
+					CloseStale(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]*reception_domain.Reception{}, nil)
+			},
+			expectCount: 0,
+		},
+		{
+			name: "locked by another instance",
+			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+				r.EXPECT().
+					CloseStale(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, reception_domain.ErrCloseStaleLocked)
+			},
+			expectCount: 0,
+		},
+		{
+			name: "database error",
+			mockSetup: func(r *reception_mocks.MockReceptionRepository) {
+				r.EXPECT().
+					CloseStale(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, reception_domain.ErrInternalDatabase)
+			},
+			expectErr: reception_domain.ErrInternalDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := reception_mocks.NewMockReceptionRepository(ctrl)
+			tt.mockSetup(repo)
+
+			logger := logger.NewTestLogger()
+
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			// closed receptions get into duration and products metrics
+			observer := reception_mocks.NewMockClosedReceptionObserver(ctrl)
+			observer.EXPECT().ObserveClosedReception(gomock.Any(), stale).Times(tt.expectCount)
+
+			closer := application.NewAutoCloser(repo, auditor, observer, idleTimeout, time.Minute, logger)
+			closed, err := closer.CloseStale(context.Background())
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, closed, tt.expectCount)
+			}
+		})
+	}
+}
+
+func TestAutoCloser_RunStopsOnContextDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := reception_mocks.NewMockReceptionRepository(ctrl)
+	repo.EXPECT().CloseStale(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+	observer := reception_mocks.NewMockClosedReceptionObserver(ctrl)
+
+	closer := application.NewAutoCloser(repo, auditor, observer, time.Hour, 10*time.Millisecond, logger.NewTestLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- closer.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("auto closer did not stop after context cancel")
+	}
+}
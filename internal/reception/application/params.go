@@ -2,6 +2,7 @@ package application
 
 import (
 	"fmt"
+	"time"
 
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
@@ -11,6 +12,13 @@ import (
 type CreateParams struct {
 	PVZID    string
 	UserRole auth_domain.Role
+	// AllowedPVZID is set for api keys restricted to one pvz.
+	AllowedPVZID string
+
+	// ID and DateTime are set by offline terminals,
+	// server generates them if nil.
+	ID       *string
+	DateTime *time.Time
 }
 
 func (p CreateParams) Validate() error {
@@ -18,9 +26,19 @@ func (p CreateParams) Validate() error {
 		return fmt.Errorf("%w: %w", reception_domain.ErrInvalidIDFormat, err)
 	}
 
+	if p.ID != nil {
+		if err := uuid.Validate(*p.ID); err != nil {
+			return fmt.Errorf("%w: %w", reception_domain.ErrInvalidIDFormat, err)
+		}
+	}
+
 	if p.UserRole != auth_domain.RoleEmployee {
 		return reception_domain.ErrAccessDenied
 	}
 
+	if p.AllowedPVZID != "" && p.AllowedPVZID != p.PVZID {
+		return reception_domain.ErrAccessDenied
+	}
+
 	return nil
 }
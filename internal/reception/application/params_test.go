@@ -11,6 +11,7 @@ import (
 
 func Test_ReceptionCreateParams_Validate(t *testing.T) {
 	validID := uuid.New().String()
+	badID := "notanuuid"
 
 	tests := []struct {
 		name    string
@@ -20,6 +21,10 @@ func Test_ReceptionCreateParams_Validate(t *testing.T) {
 		{"valid", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleEmployee}, false},
 		{"invalid UUID", application.CreateParams{PVZID: "notanuuid", UserRole: auth_domain.RoleEmployee}, true},
 		{"access denied", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleModerator}, true},
+		{"allowed pvz", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleEmployee, AllowedPVZID: validID}, false},
+		{"other pvz not allowed", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleEmployee, AllowedPVZID: uuid.NewString()}, true},
+		{"client id", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleEmployee, ID: &validID}, false},
+		{"invalid client id", application.CreateParams{PVZID: validID, UserRole: auth_domain.RoleEmployee, ID: &badID}, true},
 	}
 
 	for _, tt := range tests {
@@ -5,39 +5,46 @@ import (
 	"errors"
 	"time"
 
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	"github.com/google/uuid"
 )
 
 type ReceptionService struct {
-	repo reception_domain.ReceptionRepository
+	repo    reception_domain.ReceptionRepository
+	auditor audit_domain.Auditor
 
 	log *logger.ZerologLogger
 }
 
-func NewReceptionService(repo reception_domain.ReceptionRepository, l *logger.ZerologLogger) *ReceptionService {
+func NewReceptionService(repo reception_domain.ReceptionRepository, auditor audit_domain.Auditor, l *logger.ZerologLogger) *ReceptionService {
 	return &ReceptionService{
-		repo: repo,
-		log:  l,
+		repo:    repo,
+		auditor: auditor,
+		log:     l,
 	}
 }
 
-func (s *ReceptionService) Create(ctx context.Context, params CreateParams) (*reception_domain.Reception, error) {
+func (s *ReceptionService) Create(ctx context.Context, params CreateParams) (_ *reception_domain.Reception, err error) {
+	ctx, span := tracing.Start(ctx, "ReceptionService.Create")
+	defer func() { tracing.End(span, err) }()
+
 	if err := params.Validate(); err != nil {
-		s.log.Error().Any("params", params).Err(err).Msg("CreateReception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("CreateReception")
 		return nil, err
 	}
 
 	last, err := s.repo.FindLastOpenByPVZ(ctx, params.PVZID)
 	if err != nil && !errors.Is(err, reception_domain.ErrNoOpenReception) {
-		s.log.Error().Any("params", params).Err(err).Msg("Error checking last open reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(err).Msg("Error checking last open reception")
 		return nil, err
 	}
 
 	if last != nil {
-		s.log.Error().Any("params", params).Err(reception_domain.ErrFoundOpenedReception).Msg("Opened reception already exists")
+		s.log.Error().Ctx(ctx).Any("params", params).Err(reception_domain.ErrFoundOpenedReception).Msg("Opened reception already exists")
 		return nil, reception_domain.ErrFoundOpenedReception
 	}
 
@@ -47,15 +54,28 @@ func (s *ReceptionService) Create(ctx context.Context, params CreateParams) (*re
 		PVZID:    params.PVZID,
 		Status:   reception_domain.InProgress,
 	}
+	if params.ID != nil {
+		reception.ID = *params.ID
+	}
+	if params.DateTime != nil {
+		reception.DateTime = *params.DateTime
+	}
 
 	created, err := s.repo.Create(ctx, &reception)
 	if err != nil {
-		s.log.Error().Any("params", params).Any("reception", reception).Err(err).Msg("Error creating reception")
+		s.log.Error().Ctx(ctx).Any("params", params).Any("reception", reception).Err(err).Msg("Error creating reception")
 		return nil, err
 	}
 
 	metrics.ReceptionCreatedTotal.Inc()
 
-	s.log.Info().Any("params", params).Any("reception", created).Msg("CreateReception successful")
+	s.auditor.Record(ctx, audit_domain.Event{
+		Action:     audit_domain.ActionReceptionOpen,
+		EntityType: audit_domain.EntityReception,
+		EntityID:   created.ID,
+		After:      created,
+	})
+
+	s.log.Info().Ctx(ctx).Any("params", params).Any("reception", created).Msg("CreateReception successful")
 	return created, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
 	"github.com/0x0FACED/pvz-avito/internal/reception/application"
@@ -118,7 +119,10 @@ func TestReceptionCreate(t *testing.T) {
 
 			logger := logger.NewTestLogger()
 
-			service := application.NewReceptionService(repo, logger)
+			auditor := audit_mocks.NewMockAuditor(ctrl)
+			auditor.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+
+			service := application.NewReceptionService(repo, auditor, logger)
 			_, err := service.Create(context.Background(), tt.params)
 
 			if tt.expectErr != nil {
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/gateway"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
+	"github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ReceptionService interface {
+	Create(ctx context.Context, params application.CreateParams) (*reception_domain.Reception, error)
+}
+
+// GRPCHandler implements ReceptionService, it is also served on REST by gateway.
+type GRPCHandler struct {
+	pb.UnimplementedReceptionServiceServer
+	svc ReceptionService
+}
+
+func NewGRPCHandler(svc ReceptionService) *GRPCHandler {
+	return &GRPCHandler{
+		svc: svc,
+	}
+}
+
+func (h *GRPCHandler) CreateReception(ctx context.Context, req *pb.CreateReceptionRequest) (*pb.Reception, error) {
+	claims, ok := ctx.Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		return nil, apperr.GRPCError(ctx, apperr.ErrAccessDenied)
+	}
+
+	params := application.CreateParams{
+		PVZID:        req.GetPvzId(),
+		UserRole:     auth_domain.Role(claims.Role),
+		AllowedPVZID: claims.PVZID,
+	}
+
+	reception, err := h.svc.Create(ctx, params)
+	if err != nil {
+		return nil, apperr.GRPCError(ctx, err)
+	}
+
+	gateway.SetStatus(ctx, http.StatusCreated)
+
+	return &pb.Reception{
+		Id:       reception.ID,
+		DateTime: timestamppb.New(reception.DateTime),
+		PvzId:    reception.PVZID,
+		Status:   reception.Status.String(),
+	}, nil
+}
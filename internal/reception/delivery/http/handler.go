@@ -2,71 +2,29 @@ package http
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"net/http"
 
-	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
-	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
-	"github.com/0x0FACED/pvz-avito/internal/reception/application"
-	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/gateway"
+	pb "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 )
 
-type ReceptionService interface {
-	Create(ctx context.Context, params application.CreateParams) (*reception_domain.Reception, error)
-}
-
+// Handler serves REST API of receptions, requests are transcoded
+// to ReceptionService gRPC server by gateway.
 type Handler struct {
-	svc ReceptionService
+	gw *runtime.ServeMux
 }
 
-func NewHandler(svc ReceptionService) *Handler {
+func NewHandler(srv pb.ReceptionServiceServer) *Handler {
+	gw := gateway.NewServeMux()
+	// local server registration does not fail
+	_ = pb.RegisterReceptionServiceHandlerServer(context.Background(), gw, srv)
+
 	return &Handler{
-		svc: svc,
+		gw: gw,
 	}
 }
 
 func (h Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("POST /receptions", h.Create)
-}
-
-func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
-	var req CreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request body"))
-		return
-	}
-
-	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
-	if !ok {
-		httpcommon.JSONError(w, http.StatusForbidden, errors.New("access denied"))
-		return
-	}
-
-	params := application.CreateParams{
-		PVZID:    req.PVZID,
-		UserRole: auth_domain.Role(claims.Role),
-	}
-
-	reception, err := h.svc.Create(r.Context(), params)
-	if err != nil {
-		switch {
-		case errors.Is(err, reception_domain.ErrAccessDenied):
-			httpcommon.JSONError(w, http.StatusForbidden, errors.New("access denied"))
-		case errors.Is(err, reception_domain.ErrFoundOpenedReception):
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("reception already exists"))
-		default:
-			httpcommon.JSONError(w, http.StatusBadRequest, errors.New("invalid request"))
-		}
-		return
-	}
-
-	resp := CreateResponse{
-		ID:       reception.ID,
-		DateTime: reception.DateTime,
-		PVZID:    reception.PVZID,
-		Status:   reception.Status.String(),
-	}
-
-	httpcommon.JSONResponse(w, http.StatusCreated, resp)
+	gateway.Mount(mux, h.gw, pb.File_api_proto_pvz_v1_pvz_proto.Services().ByName("ReceptionService"))
 }
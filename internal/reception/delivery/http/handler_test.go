@@ -12,6 +12,7 @@ import (
 	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_grpc "github.com/0x0FACED/pvz-avito/internal/reception/delivery/grpc"
 	reception_http "github.com/0x0FACED/pvz-avito/internal/reception/delivery/http"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	"github.com/0x0FACED/pvz-avito/internal/reception/mocks"
@@ -26,7 +27,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
 		name           string
-		request        reception_http.CreateRequest
+		request        createRequest
 		userRole       string
 		mockSetup      func(*mocks.MockReceptionService)
 		expectedStatus int
@@ -34,7 +35,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 	}{
 		{
 			name: "successful reception creation",
-			request: reception_http.CreateRequest{
+			request: createRequest{
 				PVZID: "pvz-123",
 			},
 			userRole: "employee",
@@ -56,7 +57,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 		},
 		{
 			name: "access denied for moderator",
-			request: reception_http.CreateRequest{
+			request: createRequest{
 				PVZID: "pvz-123",
 			},
 			userRole: "moderator",
@@ -69,7 +70,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 		},
 		{
 			name: "reception already exists",
-			request: reception_http.CreateRequest{
+			request: createRequest{
 				PVZID: "pvz-123",
 			},
 			userRole: "employee",
@@ -89,7 +90,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 				tt.mockSetup(receptionSvcMock)
 			}
 
-			handler := reception_http.NewHandler(receptionSvcMock)
+			handler := reception_http.NewHandler(reception_grpc.NewGRPCHandler(receptionSvcMock))
 
 			body, _ := json.Marshal(tt.request)
 			req := httptest.NewRequest(nethttp.MethodPost, "/receptions", bytes.NewReader(body))
@@ -100,7 +101,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 			req = req.WithContext(ctx)
 			rec := httptest.NewRecorder()
 
-			handler.Create(rec, req)
+			serve(handler, rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 
@@ -109,7 +110,7 @@ func TestReceptionHandler_Create(t *testing.T) {
 				_ = json.NewDecoder(rec.Body).Decode(&errResp)
 				assert.Contains(t, errResp.Error(), tt.expectError)
 			} else {
-				var resp reception_http.CreateResponse
+				var resp createResponse
 				_ = json.NewDecoder(rec.Body).Decode(&resp)
 				assert.NotEmpty(t, resp.ID)
 				assert.Equal(t, tt.request.PVZID, resp.PVZID)
@@ -124,12 +125,12 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		receptionSvcMock := mocks.NewMockReceptionService(ctrl)
-		handler := reception_http.NewHandler(receptionSvcMock)
+		handler := reception_http.NewHandler(reception_grpc.NewGRPCHandler(receptionSvcMock))
 
 		req := httptest.NewRequest(nethttp.MethodPost, "/receptions", bytes.NewReader([]byte("{invalid}")))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusBadRequest, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -139,9 +140,9 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("missing claims in context", func(t *testing.T) {
 		receptionSvcMock := mocks.NewMockReceptionService(ctrl)
-		handler := reception_http.NewHandler(receptionSvcMock)
+		handler := reception_http.NewHandler(reception_grpc.NewGRPCHandler(receptionSvcMock))
 
-		validRequest := reception_http.CreateRequest{
+		validRequest := createRequest{
 			PVZID: "pvz-123",
 		}
 		body, _ := json.Marshal(validRequest)
@@ -149,7 +150,7 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 		req := httptest.NewRequest(nethttp.MethodPost, "/receptions", bytes.NewReader(body))
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -159,9 +160,9 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 
 	t.Run("invalid claims type in context", func(t *testing.T) {
 		receptionSvcMock := mocks.NewMockReceptionService(ctrl)
-		handler := reception_http.NewHandler(receptionSvcMock)
+		handler := reception_http.NewHandler(reception_grpc.NewGRPCHandler(receptionSvcMock))
 
-		validRequest := reception_http.CreateRequest{
+		validRequest := createRequest{
 			PVZID: "pvz-123",
 		}
 		body, _ := json.Marshal(validRequest)
@@ -170,7 +171,7 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 		req := httptest.NewRequest(nethttp.MethodPost, "/receptions", bytes.NewReader(body)).WithContext(ctx)
 		rec := httptest.NewRecorder()
 
-		handler.Create(rec, req)
+		serve(handler, rec, req)
 
 		assert.Equal(t, nethttp.StatusForbidden, rec.Code)
 		var errResp httpcommon.ErrorResponse
@@ -178,3 +179,23 @@ func TestReceptionHandler_Create_ErrorCases(t *testing.T) {
 		assert.Equal(t, "access denied", errResp.Error())
 	})
 }
+
+// serve sends request through routes of handler, as server mux does.
+func serve(handler *reception_http.Handler, w nethttp.ResponseWriter, req *nethttp.Request) {
+	mux := nethttp.NewServeMux()
+	handler.RegisterRoutes(mux)
+	mux.ServeHTTP(w, req)
+}
+
+// json of REST clients, it must keep working with gateway.
+
+type createRequest struct {
+	PVZID string `json:"pvzId"`
+}
+
+type createResponse struct {
+	ID       string    `json:"id"`
+	DateTime time.Time `json:"dateTime"`
+	PVZID    string    `json:"pvzId"`
+	Status   string    `json:"status"`
+}
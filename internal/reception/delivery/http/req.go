@@ -1,5 +0,0 @@
-package http
-
-type CreateRequest struct {
-	PVZID string `json:"pvzId"`
-}
@@ -1,12 +0,0 @@
-package http
-
-import (
-	"time"
-)
-
-type CreateResponse struct {
-	ID       string    `json:"id"`
-	DateTime time.Time `json:"dateTime"`
-	PVZID    string    `json:"pvzId"`
-	Status   string    `json:"status"`
-}
@@ -15,9 +15,35 @@ func (s Status) String() string {
 	return string(s)
 }
 
+// CloseReason explains why reception was closed.
+// Empty for receptions that are still in progress.
+type CloseReason string
+
+const (
+	CloseReasonManual     CloseReason = "manual"
+	CloseReasonAutoClosed CloseReason = "auto_closed"
+)
+
+func (r CloseReason) String() string {
+	return string(r)
+}
+
 type Reception struct {
-	ID       string
-	DateTime time.Time
-	PVZID    string
-	Status   Status
+	ID          string
+	DateTime    time.Time
+	PVZID       string
+	Status      Status
+	CloseReason CloseReason
+	ClosedAt    *time.Time
+	ClosedBy    string // email of user who closed reception, empty if closed automatically
+}
+
+// Duration returns how long reception was open.
+// Returns 0 for receptions in progress.
+func (r Reception) Duration() time.Duration {
+	if r.ClosedAt == nil {
+		return 0
+	}
+
+	return r.ClosedAt.Sub(r.DateTime)
 }
@@ -10,6 +10,11 @@ var (
 	ErrFoundOpenedReception = errors.New("reception: there is opened reception, cant create new one")
 )
 
+var (
+	// auto close
+	ErrCloseStaleLocked = errors.New("reception: stale receptions are being closed by another instance")
+)
+
 var (
 	ErrAccessDenied = errors.New("reception: only employees can create new reception")
 )
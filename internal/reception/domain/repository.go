@@ -1,11 +1,24 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// ReceptionRepository contract is checked by repotest.Run for every storage backend.
+// There is at most one reception in progress per pvz.
+// Lists are ordered newest first and are empty (not nil) if nothing is found.
 type ReceptionRepository interface {
+	// Create fails with ErrPVZNotFound for unknown pvz
+	// and with ErrFoundOpenedReception if pvz already has reception in progress.
 	Create(ctx context.Context, reception *Reception) (*Reception, error)
 	FindByID(ctx context.Context, id string) (*Reception, error)
+	// FindLastOpenByPVZ fails with ErrNoOpenReception if pvz has no reception in progress.
 	FindLastOpenByPVZ(ctx context.Context, pvzID string) (*Reception, error)
-	CloseLastReception(ctx context.Context, pvzID string) (*Reception, error)
+	// CloseLastReception fails with ErrNoOpenReception if pvz has no reception in progress.
+	CloseLastReception(ctx context.Context, pvzID string, closedAt time.Time, closedBy string) (*Reception, error)
+	// CloseStale closes all receptions in progress without any activity
+	// (reception opening or product adding) since idleSince.
+	CloseStale(ctx context.Context, idleSince, closedAt time.Time) ([]*Reception, error)
 	ListByPVZ(ctx context.Context, pvzID string) ([]*Reception, error)
 }
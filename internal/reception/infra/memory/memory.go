@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// ReceptionMemoryRepository keeps receptions in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type ReceptionMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewReceptionMemoryRepository(db *memdb.DB) *ReceptionMemoryRepository {
+	return &ReceptionMemoryRepository{db: db}
+}
+
+func (r *ReceptionMemoryRepository) Create(_ context.Context, reception *reception_domain.Reception) (*reception_domain.Reception, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	if r.db.PVZ(reception.PVZID) == nil {
+		return nil, fmt.Errorf("%w: id %s", reception_domain.ErrPVZNotFound, reception.PVZID)
+	}
+
+	// only one reception in progress per pvz
+	if reception.Status == reception_domain.InProgress && r.db.OpenReception(reception.PVZID) != nil {
+		return nil, fmt.Errorf("%w: pvz %s", reception_domain.ErrFoundOpenedReception, reception.PVZID)
+	}
+
+	row := &reception_domain.Reception{
+		ID:       reception.ID,
+		DateTime: reception.DateTime,
+		PVZID:    reception.PVZID,
+		Status:   reception.Status,
+	}
+	r.db.Receptions = append(r.db.Receptions, row)
+
+	return cloneReception(row), nil
+}
+
+func (r *ReceptionMemoryRepository) FindByID(_ context.Context, id string) (*reception_domain.Reception, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	row := r.db.Reception(id)
+	if row == nil {
+		return nil, fmt.Errorf("%w: id %s", reception_domain.ErrReceptionNotFound, id)
+	}
+
+	return cloneReception(row), nil
+}
+
+func (r *ReceptionMemoryRepository) FindLastOpenByPVZ(_ context.Context, pvzID string) (*reception_domain.Reception, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	row := r.db.OpenReception(pvzID)
+	if row == nil {
+		return nil, fmt.Errorf("%w: pvz %s", reception_domain.ErrNoOpenReception, pvzID)
+	}
+
+	return cloneReception(row), nil
+}
+
+func (r *ReceptionMemoryRepository) CloseLastReception(_ context.Context, pvzID string, closedAt time.Time, closedBy string) (*reception_domain.Reception, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	row := r.db.OpenReception(pvzID)
+	if row == nil {
+		return nil, fmt.Errorf("%w: pvz %s", reception_domain.ErrNoOpenReception, pvzID)
+	}
+
+	row.Status = reception_domain.Close
+	row.CloseReason = reception_domain.CloseReasonManual
+	row.ClosedAt = &closedAt
+	row.ClosedBy = closedBy
+
+	return cloneReception(row), nil
+}
+
+// CloseStale never returns ErrCloseStaleLocked, memory storage is single instance.
+func (r *ReceptionMemoryRepository) CloseStale(_ context.Context, idleSince, closedAt time.Time) ([]*reception_domain.Reception, error) {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	closed := make([]*reception_domain.Reception, 0)
+	for _, row := range r.db.Receptions {
+		if row.Status != reception_domain.InProgress {
+			continue
+		}
+
+		lastActivity := row.DateTime
+		if products := r.db.AliveProducts(row.ID); len(products) > 0 && products[0].DateTime.After(lastActivity) {
+			lastActivity = products[0].DateTime
+		}
+
+		if !lastActivity.Before(idleSince) {
+			continue
+		}
+
+		row.Status = reception_domain.Close
+		row.CloseReason = reception_domain.CloseReasonAutoClosed
+		row.ClosedAt = &closedAt
+		closed = append(closed, cloneReception(row))
+	}
+
+	return closed, nil
+}
+
+func (r *ReceptionMemoryRepository) ListByPVZ(_ context.Context, pvzID string) ([]*reception_domain.Reception, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	receptions := make([]*reception_domain.Reception, 0)
+	for _, row := range r.db.Receptions {
+		if row.PVZID == pvzID {
+			receptions = append(receptions, cloneReception(row))
+		}
+	}
+
+	memdb.SortNewestFirst(receptions, func(r *reception_domain.Reception) time.Time { return r.DateTime })
+
+	return receptions, nil
+}
+
+func cloneReception(r *reception_domain.Reception) *reception_domain.Reception {
+	c := *r
+	if r.ClosedAt != nil {
+		closedAt := *r.ClosedAt
+		c.ClosedAt = &closedAt
+	}
+
+	return &c
+}
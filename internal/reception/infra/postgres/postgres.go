@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
 	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	pgx "github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -34,7 +36,7 @@ func (r *ReceptionPostgresRepository) Create(ctx context.Context, reception *rec
 	}
 
 	var created reception_domain.Reception
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&created.ID, &created.DateTime, &created.PVZID, &created.Status,
 	)
 	if err != nil {
@@ -43,6 +45,9 @@ func (r *ReceptionPostgresRepository) Create(ctx context.Context, reception *rec
 			switch pgErr.Code {
 			case "23503":
 				return nil, fmt.Errorf("%w: %w", reception_domain.ErrPVZNotFound, err)
+			case "23505":
+				// idx_unique_active_reception, another reception was opened concurrently
+				return nil, fmt.Errorf("%w: %w", reception_domain.ErrFoundOpenedReception, err)
 			}
 		}
 		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
@@ -53,7 +58,7 @@ func (r *ReceptionPostgresRepository) Create(ctx context.Context, reception *rec
 
 func (r *ReceptionPostgresRepository) FindByID(ctx context.Context, id string) (*reception_domain.Reception, error) {
 	query := `
-		SELECT id, date_time, pvz_id, status
+		SELECT id, date_time, pvz_id, status, COALESCE(close_reason::text, ''), closed_at, COALESCE(closed_by, '')
 		FROM avito.receptions
 		WHERE id = @id
 	`
@@ -63,11 +68,14 @@ func (r *ReceptionPostgresRepository) FindByID(ctx context.Context, id string) (
 	}
 
 	reception := reception_domain.Reception{}
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&reception.ID,
 		&reception.DateTime,
 		&reception.PVZID,
 		&reception.Status,
+		&reception.CloseReason,
+		&reception.ClosedAt,
+		&reception.ClosedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -81,7 +89,7 @@ func (r *ReceptionPostgresRepository) FindByID(ctx context.Context, id string) (
 
 func (r *ReceptionPostgresRepository) FindLastOpenByPVZ(ctx context.Context, pvzID string) (*reception_domain.Reception, error) {
 	query := `
-		SELECT id, date_time, pvz_id, status
+		SELECT id, date_time, pvz_id, status, COALESCE(close_reason::text, ''), closed_at, COALESCE(closed_by, '')
 		FROM avito.receptions
 		WHERE pvz_id = @pvz_id AND status = 'in_progress'
 		ORDER BY date_time DESC
@@ -94,11 +102,14 @@ func (r *ReceptionPostgresRepository) FindLastOpenByPVZ(ctx context.Context, pvz
 
 	reception := reception_domain.Reception{}
 
-	err := r.pool.QueryRow(ctx, query, args).Scan(
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, args).Scan(
 		&reception.ID,
 		&reception.DateTime,
 		&reception.PVZID,
 		&reception.Status,
+		&reception.CloseReason,
+		&reception.ClosedAt,
+		&reception.ClosedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -110,8 +121,8 @@ func (r *ReceptionPostgresRepository) FindLastOpenByPVZ(ctx context.Context, pvz
 	return &reception, nil
 }
 
-func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pvzID string) (*reception_domain.Reception, error) {
-	tx, err := r.pool.Begin(ctx)
+func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pvzID string, closedAt time.Time, closedBy string) (*reception_domain.Reception, error) {
+	tx, err := database.Pgx(ctx, r.pool).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
 	}
@@ -119,7 +130,7 @@ func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pv
 
 	query := `
 		UPDATE avito.receptions
-		SET status = 'close'
+		SET status = 'close', close_reason = 'manual', closed_at = @closed_at, closed_by = NULLIF(@closed_by, '')
 		WHERE id = (
 			SELECT id
 			FROM avito.receptions
@@ -127,11 +138,13 @@ func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pv
 			ORDER BY date_time DESC
 			LIMIT 1
 		)
-		RETURNING id, date_time, pvz_id, status
+		RETURNING id, date_time, pvz_id, status, close_reason, closed_at, COALESCE(closed_by, '')
 	`
 
 	args := pgx.NamedArgs{
-		"pvz_id": pvzID,
+		"pvz_id":    pvzID,
+		"closed_at": closedAt,
+		"closed_by": closedBy,
 	}
 
 	reception := reception_domain.Reception{}
@@ -140,10 +153,13 @@ func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pv
 		&reception.DateTime,
 		&reception.PVZID,
 		&reception.Status,
+		&reception.CloseReason,
+		&reception.ClosedAt,
+		&reception.ClosedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("%w: %w", reception_domain.ErrReceptionNotFound, err)
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrNoOpenReception, err)
 		}
 		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
 	}
@@ -155,9 +171,81 @@ func (r *ReceptionPostgresRepository) CloseLastReception(ctx context.Context, pv
 	return &reception, nil
 }
 
+// closeStaleLockKey is the key of transaction-level advisory lock,
+// so only one replica closes stale receptions at a time.
+const closeStaleLockKey int64 = 0x5057_0026
+
+func (r *ReceptionPostgresRepository) CloseStale(ctx context.Context, idleSince, closedAt time.Time) ([]*reception_domain.Reception, error) {
+	tx, err := database.Pgx(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(@key)`, pgx.NamedArgs{"key": closeStaleLockKey}).Scan(&locked)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	if !locked {
+		return nil, reception_domain.ErrCloseStaleLocked
+	}
+
+	query := `
+		UPDATE avito.receptions r
+		SET status = 'close', close_reason = 'auto_closed', closed_at = @closed_at
+		WHERE r.status = 'in_progress'
+		  AND GREATEST(
+		      r.date_time,
+		      COALESCE((SELECT MAX(p.date_time) FROM avito.products p WHERE p.reception_id = r.id AND p.deleted_at IS NULL), r.date_time)
+		  ) < @idle_since
+		RETURNING r.id, r.date_time, r.pvz_id, r.status, r.close_reason, r.closed_at, COALESCE(r.closed_by, '')
+	`
+
+	args := pgx.NamedArgs{
+		"idle_since": idleSince,
+		"closed_at":  closedAt,
+	}
+
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	receptions := make([]*reception_domain.Reception, 0)
+	for rows.Next() {
+		var reception reception_domain.Reception
+		err := rows.Scan(
+			&reception.ID,
+			&reception.DateTime,
+			&reception.PVZID,
+			&reception.Status,
+			&reception.CloseReason,
+			&reception.ClosedAt,
+			&reception.ClosedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+		}
+		receptions = append(receptions, &reception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return receptions, nil
+}
+
 func (r *ReceptionPostgresRepository) ListByPVZ(ctx context.Context, pvzID string) ([]*reception_domain.Reception, error) {
 	query := `
-		SELECT id, date_time, pvz_id, status
+		SELECT id, date_time, pvz_id, status, COALESCE(close_reason::text, ''), closed_at, COALESCE(closed_by, '')
 		FROM avito.receptions
 		WHERE pvz_id = @pvz_id
 		ORDER BY date_time DESC
@@ -167,13 +255,13 @@ func (r *ReceptionPostgresRepository) ListByPVZ(ctx context.Context, pvzID strin
 		"pvz_id": pvzID,
 	}
 
-	rows, err := r.pool.Query(ctx, query, args)
+	rows, err := database.Pgx(ctx, r.pool).Query(ctx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
 	}
 	defer rows.Close()
 
-	var receptions []*reception_domain.Reception
+	receptions := make([]*reception_domain.Reception, 0)
 	for rows.Next() {
 		var reception reception_domain.Reception
 		err := rows.Scan(
@@ -181,6 +269,9 @@ func (r *ReceptionPostgresRepository) ListByPVZ(ctx context.Context, pvzID strin
 			&reception.DateTime,
 			&reception.PVZID,
 			&reception.Status,
+			&reception.CloseReason,
+			&reception.ClosedAt,
+			&reception.ClosedBy,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
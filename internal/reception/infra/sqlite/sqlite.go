@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type ReceptionSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewReceptionSQLiteRepository(db *sql.DB) *ReceptionSQLiteRepository {
+	return &ReceptionSQLiteRepository{db: db}
+}
+
+const receptionColumns = `id, date_time, pvz_id, status, COALESCE(close_reason, ''), closed_at, COALESCE(closed_by, '')`
+
+func (r *ReceptionSQLiteRepository) Create(ctx context.Context, reception *reception_domain.Reception) (*reception_domain.Reception, error) {
+	query := `
+		INSERT INTO receptions (id, date_time, pvz_id, status)
+		VALUES (@id, @date_time, @pvz_id, @status)
+		RETURNING id, date_time, pvz_id, status
+	`
+
+	var created reception_domain.Reception
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query,
+		sql.Named("id", reception.ID),
+		sql.Named("date_time", database.SQLiteTime(reception.DateTime)),
+		sql.Named("pvz_id", reception.PVZID),
+		sql.Named("status", reception.Status.String()),
+	).Scan(&created.ID, &created.DateTime, &created.PVZID, &created.Status)
+	if err != nil {
+		switch database.SQLiteErrorCode(err) {
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrPVZNotFound, err)
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			// idx_unique_active_reception, another reception was opened concurrently
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrFoundOpenedReception, err)
+		}
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return &created, nil
+}
+
+func (r *ReceptionSQLiteRepository) FindByID(ctx context.Context, id string) (*reception_domain.Reception, error) {
+	query := `SELECT ` + receptionColumns + ` FROM receptions WHERE id = @id`
+
+	reception, err := scanReception(database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("id", id)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrReceptionNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return reception, nil
+}
+
+func (r *ReceptionSQLiteRepository) FindLastOpenByPVZ(ctx context.Context, pvzID string) (*reception_domain.Reception, error) {
+	query := `
+		SELECT ` + receptionColumns + `
+		FROM receptions
+		WHERE pvz_id = @pvz_id AND status = 'in_progress'
+		ORDER BY date_time DESC
+		LIMIT 1
+	`
+
+	reception, err := scanReception(database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("pvz_id", pvzID)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrNoOpenReception, err)
+		}
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return reception, nil
+}
+
+func (r *ReceptionSQLiteRepository) CloseLastReception(ctx context.Context, pvzID string, closedAt time.Time, closedBy string) (*reception_domain.Reception, error) {
+	query := `
+		UPDATE receptions
+		SET status = 'close', close_reason = 'manual', closed_at = @closed_at, closed_by = NULLIF(@closed_by, '')
+		WHERE id = (
+			SELECT id
+			FROM receptions
+			WHERE pvz_id = @pvz_id AND status = 'in_progress'
+			ORDER BY date_time DESC
+			LIMIT 1
+		)
+		RETURNING ` + receptionColumns
+
+	reception, err := scanReception(database.SQLite(ctx, r.db).QueryRowContext(ctx, query,
+		sql.Named("pvz_id", pvzID),
+		sql.Named("closed_at", database.SQLiteTime(closedAt)),
+		sql.Named("closed_by", closedBy),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrNoOpenReception, err)
+		}
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return reception, nil
+}
+
+// CloseStale never returns ErrCloseStaleLocked, sqlite serializes writers,
+// so concurrent calls can not close same reception twice.
+func (r *ReceptionSQLiteRepository) CloseStale(ctx context.Context, idleSince, closedAt time.Time) ([]*reception_domain.Reception, error) {
+	query := `
+		UPDATE receptions
+		SET status = 'close', close_reason = 'auto_closed', closed_at = @closed_at
+		WHERE status = 'in_progress'
+		  AND MAX(
+		      date_time,
+		      COALESCE((SELECT MAX(p.date_time) FROM products p WHERE p.reception_id = receptions.id AND p.deleted_at IS NULL), date_time)
+		  ) < @idle_since
+		RETURNING ` + receptionColumns
+
+	return r.list(ctx, query,
+		sql.Named("idle_since", database.SQLiteTime(idleSince)),
+		sql.Named("closed_at", database.SQLiteTime(closedAt)),
+	)
+}
+
+func (r *ReceptionSQLiteRepository) ListByPVZ(ctx context.Context, pvzID string) ([]*reception_domain.Reception, error) {
+	query := `
+		SELECT ` + receptionColumns + `
+		FROM receptions
+		WHERE pvz_id = @pvz_id
+		ORDER BY date_time DESC
+	`
+
+	return r.list(ctx, query, sql.Named("pvz_id", pvzID))
+}
+
+func (r *ReceptionSQLiteRepository) list(ctx context.Context, query string, args ...any) ([]*reception_domain.Reception, error) {
+	rows, err := database.SQLite(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+	defer rows.Close()
+
+	receptions := make([]*reception_domain.Reception, 0)
+	for rows.Next() {
+		reception, err := scanReception(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+		}
+		receptions = append(receptions, reception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", reception_domain.ErrInternalDatabase, err)
+	}
+
+	return receptions, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReception(row scanner) (*reception_domain.Reception, error) {
+	var reception reception_domain.Reception
+	err := row.Scan(
+		&reception.ID,
+		&reception.DateTime,
+		&reception.PVZID,
+		&reception.Status,
+		&reception.CloseReason,
+		&reception.ClosedAt,
+		&reception.ClosedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reception, nil
+}
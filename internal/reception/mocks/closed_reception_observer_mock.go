@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/reception/application/auto_closer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/reception/application/auto_closer.go -destination=internal/reception/mocks/closed_reception_observer_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClosedReceptionObserver is a mock of ClosedReceptionObserver interface.
+type MockClosedReceptionObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockClosedReceptionObserverMockRecorder
+	isgomock struct{}
+}
+
+// MockClosedReceptionObserverMockRecorder is the mock recorder for MockClosedReceptionObserver.
+type MockClosedReceptionObserverMockRecorder struct {
+	mock *MockClosedReceptionObserver
+}
+
+// NewMockClosedReceptionObserver creates a new mock instance.
+func NewMockClosedReceptionObserver(ctrl *gomock.Controller) *MockClosedReceptionObserver {
+	mock := &MockClosedReceptionObserver{ctrl: ctrl}
+	mock.recorder = &MockClosedReceptionObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClosedReceptionObserver) EXPECT() *MockClosedReceptionObserverMockRecorder {
+	return m.recorder
+}
+
+// ObserveClosedReception mocks base method.
+func (m *MockClosedReceptionObserver) ObserveClosedReception(ctx context.Context, reception *domain.Reception) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveClosedReception", ctx, reception)
+}
+
+// ObserveClosedReception indicates an expected call of ObserveClosedReception.
+func (mr *MockClosedReceptionObserverMockRecorder) ObserveClosedReception(ctx, reception any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveClosedReception", reflect.TypeOf((*MockClosedReceptionObserver)(nil).ObserveClosedReception), ctx, reception)
+}
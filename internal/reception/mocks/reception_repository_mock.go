@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -42,18 +43,33 @@ func (m *MockReceptionRepository) EXPECT() *MockReceptionRepositoryMockRecorder
 }
 
 // CloseLastReception mocks base method.
-func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pvzID string) (*domain.Reception, error) {
+func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pvzID string, closedAt time.Time, closedBy string) (*domain.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseLastReception", ctx, pvzID)
+	ret := m.ctrl.Call(m, "CloseLastReception", ctx, pvzID, closedAt, closedBy)
 	ret0, _ := ret[0].(*domain.Reception)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CloseLastReception indicates an expected call of CloseLastReception.
-func (mr *MockReceptionRepositoryMockRecorder) CloseLastReception(ctx, pvzID any) *gomock.Call {
+func (mr *MockReceptionRepositoryMockRecorder) CloseLastReception(ctx, pvzID, closedAt, closedBy any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseLastReception", reflect.TypeOf((*MockReceptionRepository)(nil).CloseLastReception), ctx, pvzID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseLastReception", reflect.TypeOf((*MockReceptionRepository)(nil).CloseLastReception), ctx, pvzID, closedAt, closedBy)
+}
+
+// CloseStale mocks base method.
+func (m *MockReceptionRepository) CloseStale(ctx context.Context, idleSince, closedAt time.Time) ([]*domain.Reception, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseStale", ctx, idleSince, closedAt)
+	ret0, _ := ret[0].([]*domain.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseStale indicates an expected call of CloseStale.
+func (mr *MockReceptionRepositoryMockRecorder) CloseStale(ctx, idleSince, closedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseStale", reflect.TypeOf((*MockReceptionRepository)(nil).CloseStale), ctx, idleSince, closedAt)
 }
 
 // Create mocks base method.
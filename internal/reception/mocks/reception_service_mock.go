@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/reception/delivery/http/handler.go
+// Source: internal/reception/delivery/grpc/handler.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/reception/delivery/http/handler.go -destination=internal/reception/mocks/reception_service_mock.go -package=mocks
+//	mockgen -source=internal/reception/delivery/grpc/handler.go -destination=internal/reception/mocks/reception_service_mock.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"fmt"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/google/uuid"
+)
+
+// MaxOperations limits journal size of one sync,
+// bigger journals must be uploaded in several requests.
+const MaxOperations = 1000
+
+type SyncParams struct {
+	Operations []sync_domain.Operation
+	UserRole   auth_domain.Role
+	UserEmail  string
+}
+
+func (p SyncParams) Validate() error {
+	if p.UserRole != auth_domain.RoleEmployee {
+		return sync_domain.ErrAccessDenied
+	}
+
+	if len(p.Operations) == 0 {
+		return sync_domain.ErrEmptyJournal
+	}
+
+	if len(p.Operations) > MaxOperations {
+		return fmt.Errorf("%w: %d > %d", sync_domain.ErrJournalTooLarge, len(p.Operations), MaxOperations)
+	}
+
+	return nil
+}
+
+// validateOperation checks fields common for all operation types,
+// the rest is validated by services operation is replayed through.
+func validateOperation(op sync_domain.Operation) error {
+	if err := uuid.Validate(op.ID); err != nil {
+		return fmt.Errorf("%w: %w", sync_domain.ErrInvalidIDFormat, err)
+	}
+
+	if err := op.Type.Validate(); err != nil {
+		return err
+	}
+
+	if op.ClientTime.IsZero() {
+		return sync_domain.ErrInvalidClientTime
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncParams_Validate(t *testing.T) {
+	op := sync_domain.Operation{
+		ID:         uuid.NewString(),
+		Type:       sync_domain.OpenReception,
+		PVZID:      uuid.NewString(),
+		ClientTime: time.Now(),
+	}
+
+	tests := []struct {
+		name      string
+		params    application.SyncParams
+		expectErr error
+	}{
+		{
+			name:   "valid",
+			params: application.SyncParams{Operations: []sync_domain.Operation{op}, UserRole: auth_domain.RoleEmployee},
+		},
+		{
+			name:      "moderator",
+			params:    application.SyncParams{Operations: []sync_domain.Operation{op}, UserRole: auth_domain.RoleModerator},
+			expectErr: sync_domain.ErrAccessDenied,
+		},
+		{
+			name:      "empty journal",
+			params:    application.SyncParams{UserRole: auth_domain.RoleEmployee},
+			expectErr: sync_domain.ErrEmptyJournal,
+		},
+		{
+			name: "too many operations",
+			params: application.SyncParams{
+				Operations: make([]sync_domain.Operation, application.MaxOperations+1),
+				UserRole:   auth_domain.RoleEmployee,
+			},
+			expectErr: sync_domain.ErrJournalTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/metrics"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/tracing"
+	product_svc "github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/google/uuid"
+)
+
+// SyncService replays journals of offline terminals.
+// Operations are replayed in order of journal, final result of every operation
+// is saved by operation id in same transaction with changes of operation,
+// so uploading same journal again changes nothing and returns same results.
+type SyncService struct {
+	journal      sync_domain.JournalRepository
+	tx           Transactor
+	receptionSvc ReceptionService
+	productSvc   ProductService
+	pvzSvc       PVZService
+
+	log *logger.ZerologLogger
+}
+
+func NewSyncService(
+	journal sync_domain.JournalRepository,
+	tx Transactor,
+	receptionSvc ReceptionService,
+	productSvc ProductService,
+	pvzSvc PVZService,
+	l *logger.ZerologLogger,
+) *SyncService {
+	return &SyncService{
+		journal:      journal,
+		tx:           tx,
+		receptionSvc: receptionSvc,
+		productSvc:   productSvc,
+		pvzSvc:       pvzSvc,
+		log:          l,
+	}
+}
+
+// Sync returns one result per operation in order of journal.
+// Replay stops at first internal error, operations after it are skipped
+// (they may depend on failed one) and can be uploaded again later.
+func (s *SyncService) Sync(ctx context.Context, params SyncParams) (_ []*sync_domain.Result, err error) {
+	ctx, span := tracing.Start(ctx, "SyncService.Sync")
+	defer func() { tracing.End(span, err) }()
+
+	if err := params.Validate(); err != nil {
+		s.log.Error().Ctx(ctx).Str("user", params.UserEmail).Int("operations", len(params.Operations)).Err(err).Msg("Sync")
+		return nil, err
+	}
+
+	results := make([]*sync_domain.Result, 0, len(params.Operations))
+	counts := make(map[sync_domain.Status]int)
+
+	for i, op := range params.Operations {
+		result := s.replay(ctx, op, params)
+		results = append(results, result)
+		counts[result.Status]++
+
+		if result.Status == sync_domain.StatusFailed {
+			for _, skipped := range params.Operations[i+1:] {
+				results = append(results, newResult(skipped, params, sync_domain.StatusSkipped, time.Now()))
+				counts[sync_domain.StatusSkipped]++
+			}
+			break
+		}
+	}
+
+	for _, result := range results {
+		status := result.Status.String()
+		if result.Duplicate {
+			status = "duplicate"
+		}
+		metrics.SyncOperationsTotal.WithLabelValues(result.Type.String(), status).Inc()
+	}
+
+	s.log.Info().Ctx(ctx).
+		Str("user", params.UserEmail).
+		Int("operations", len(params.Operations)).
+		Any("statuses", counts).
+		Msg("Sync successful")
+
+	return results, nil
+}
+
+func (s *SyncService) replay(ctx context.Context, op sync_domain.Operation, params SyncParams) *sync_domain.Result {
+	// operation without valid id cant be saved, it is rejected on every replay anyway
+	if err := uuid.Validate(op.ID); err != nil {
+		result := newResult(op, params, sync_domain.StatusRejected, time.Now())
+		result.Error = fmt.Errorf("%w: %w", sync_domain.ErrInvalidIDFormat, err).Error()
+		return result
+	}
+
+	// operation is claimed, applied and saved in one transaction,
+	// so it is either applied with saved result or not applied at all
+	var result *sync_domain.Result
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		saved, err := s.journal.Claim(ctx, op.ID)
+		if err == nil {
+			saved.Duplicate = true
+			result = saved
+			return nil
+		}
+		if !errors.Is(err, sync_domain.ErrResultNotFound) {
+			return err
+		}
+
+		result = s.applyResult(ctx, op, params)
+		if !result.Status.Final() {
+			return errNotFinal
+		}
+
+		return s.journal.Save(ctx, result)
+	})
+	if err == nil || errors.Is(err, errNotFinal) {
+		return result
+	}
+
+	// same operation was saved concurrently by storage without claim lock
+	if errors.Is(err, sync_domain.ErrResultAlreadySaved) {
+		if saved, err := s.journal.Find(ctx, op.ID); err == nil {
+			saved.Duplicate = true
+			return saved
+		}
+	}
+
+	// changes of operation are rolled back, it is replayed again on next upload
+	s.log.Error().Ctx(ctx).Any("operation", op).Err(err).Msg("Error replaying operation")
+
+	return newResult(op, params, sync_domain.StatusFailed, time.Now())
+}
+
+// errNotFinal rolls back transaction of operation which result is not saved.
+var errNotFinal = errors.New("sync: operation result is not final")
+
+// applyResult applies operation in savepoint, so operation which is not applied
+// leaves no changes, but its result can still be saved in transaction of ctx.
+func (s *SyncService) applyResult(ctx context.Context, op sync_domain.Operation, params SyncParams) *sync_domain.Result {
+	now := time.Now()
+
+	var entityID string
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		entityID, err = s.apply(ctx, op, params, now)
+		return err
+	})
+
+	// product was created by earlier replay, which result was not saved
+	if errors.Is(err, product_domain.ErrProductAlreadyExists) {
+		s.log.Info().Ctx(ctx).Any("operation", op).Msg("Operation is already applied")
+		entityID, err = op.ID, nil
+	}
+
+	result := newResult(op, params, statusOf(err), now)
+	result.EntityID = entityID
+	if err != nil {
+		s.log.Warn().Ctx(ctx).Any("operation", op).Str("status", result.Status.String()).Err(err).Msg("Operation is not applied")
+
+		// internal errors are not shown to terminal
+		if result.Status != sync_domain.StatusFailed {
+			result.Error = err.Error()
+		}
+	}
+
+	return result
+}
+
+// apply replays operation through services and returns id of changed reception or product.
+func (s *SyncService) apply(ctx context.Context, op sync_domain.Operation, params SyncParams, now time.Time) (string, error) {
+	if err := validateOperation(op); err != nil {
+		return "", err
+	}
+
+	// terminal clock may be ahead of server, operations are never dated in future
+	at := op.ClientTime
+	if at.After(now) {
+		at = now
+	}
+
+	switch op.Type {
+	case sync_domain.OpenReception:
+		reception, err := s.receptionSvc.Create(ctx, reception_svc.CreateParams{
+			PVZID:    op.PVZID,
+			UserRole: params.UserRole,
+			ID:       &op.ID,
+			DateTime: &at,
+		})
+		if err != nil {
+			return "", err
+		}
+		return reception.ID, nil
+	case sync_domain.AddProduct:
+		product, err := s.productSvc.Create(ctx, product_svc.CreateParams{
+			Type:     op.ProductType,
+			PVZID:    op.PVZID,
+			UserRole: params.UserRole,
+			ID:       &op.ID,
+			DateTime: &at,
+		})
+		if err != nil {
+			return "", err
+		}
+		return product.ID, nil
+	case sync_domain.DeleteLastProduct:
+		return "", s.pvzSvc.DeleteLastProduct(ctx, pvz_svc.DeleteLastProductParams{
+			PVZID:     op.PVZID,
+			UserRole:  params.UserRole,
+			UserEmail: params.UserEmail,
+			DeletedAt: &at,
+		})
+	case sync_domain.CloseReception:
+		reception, err := s.pvzSvc.CloseLastReception(ctx, pvz_svc.CloseLastReceptionParams{
+			PVZID:     op.PVZID,
+			UserRole:  params.UserRole,
+			UserEmail: params.UserEmail,
+			ClosedAt:  &at,
+		})
+		if err != nil {
+			return "", err
+		}
+		return reception.ID, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", sync_domain.ErrInvalidOperationType, op.Type)
+}
+
+func newResult(op sync_domain.Operation, params SyncParams, status sync_domain.Status, now time.Time) *sync_domain.Result {
+	return &sync_domain.Result{
+		OperationID: op.ID,
+		Type:        op.Type,
+		PVZID:       op.PVZID,
+		Status:      status,
+		UserEmail:   params.UserEmail,
+		ClientTime:  op.ClientTime,
+		AppliedAt:   now,
+	}
+}
+
+// statusOf maps error of service to operation status.
+// Conflicts are caused by state changed by other terminals or by earlier operations,
+// rejected operations are invalid by themselves.
+func statusOf(err error) sync_domain.Status {
+	switch {
+	case err == nil:
+		return sync_domain.StatusApplied
+	case errors.Is(err, reception_domain.ErrFoundOpenedReception),
+		errors.Is(err, reception_domain.ErrNoOpenReception),
+		errors.Is(err, product_domain.ErrNoProductsToDelete),
+		errors.Is(err, product_domain.ErrReceptionNotFound),
+		errors.Is(err, product_domain.ErrReceptionClosed):
+		return sync_domain.StatusConflict
+	case errors.Is(err, sync_domain.ErrInvalidIDFormat),
+		errors.Is(err, sync_domain.ErrInvalidOperationType),
+		errors.Is(err, sync_domain.ErrInvalidClientTime),
+		errors.Is(err, reception_domain.ErrPVZNotFound),
+		errors.Is(err, reception_domain.ErrInvalidIDFormat),
+		errors.Is(err, reception_domain.ErrAccessDenied),
+		errors.Is(err, product_domain.ErrInvalidProductType),
+		errors.Is(err, product_domain.ErrInvalidIDFormat),
+		errors.Is(err, product_domain.ErrAccessDenied),
+		errors.Is(err, pvz_domain.ErrInvalidIDFormat),
+		errors.Is(err, pvz_domain.ErrAccessDenied):
+		return sync_domain.StatusRejected
+	}
+
+	return sync_domain.StatusFailed
+}
@@ -0,0 +1,372 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_mocks "github.com/0x0FACED/pvz-avito/internal/audit/mocks"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	events_svc "github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_domain "github.com/0x0FACED/pvz-avito/internal/events/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	product_svc "github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	pvz_mocks "github.com/0x0FACED/pvz-avito/internal/pvz/mocks"
+	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_mocks "github.com/0x0FACED/pvz-avito/internal/reception/mocks"
+	"github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/0x0FACED/pvz-avito/internal/sync/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type syncMocks struct {
+	journal   *mocks.MockJournalRepository
+	tx        *mocks.MockTransactor
+	reception *mocks.MockReceptionService
+	product   *mocks.MockProductService
+	pvz       *mocks.MockPVZService
+
+	// rolledBack are errors returned by transactions, savepoints included
+	rolledBack *[]error
+}
+
+func newSyncService(ctrl *gomock.Controller) (*application.SyncService, syncMocks) {
+	m := syncMocks{
+		journal:    mocks.NewMockJournalRepository(ctrl),
+		tx:         mocks.NewMockTransactor(ctrl),
+		reception:  mocks.NewMockReceptionService(ctrl),
+		product:    mocks.NewMockProductService(ctrl),
+		pvz:        mocks.NewMockPVZService(ctrl),
+		rolledBack: new([]error),
+	}
+
+	// after commit callbacks are run or dropped as by real transactors
+	m.tx.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			err := database.RunInTx(ctx, fn, func() error { return nil })
+			if err != nil {
+				*m.rolledBack = append(*m.rolledBack, err)
+			}
+			return err
+		}).AnyTimes()
+
+	return application.NewSyncService(m.journal, m.tx, m.reception, m.product, m.pvz, logger.NewTestLogger()), m
+}
+
+func operation(typ sync_domain.OperationType, pvzID string, clientTime time.Time) sync_domain.Operation {
+	op := sync_domain.Operation{
+		ID:         uuid.NewString(),
+		Type:       typ,
+		PVZID:      pvzID,
+		ClientTime: clientTime,
+	}
+	if typ == sync_domain.AddProduct {
+		op.ProductType = product_domain.Shoes
+	}
+
+	return op
+}
+
+func syncParams(ops ...sync_domain.Operation) application.SyncParams {
+	return application.SyncParams{
+		Operations: ops,
+		UserRole:   auth_domain.RoleEmployee,
+		UserEmail:  "employee@example.com",
+	}
+}
+
+func statuses(results []*sync_domain.Result) []sync_domain.Status {
+	out := make([]sync_domain.Status, 0, len(results))
+	for _, r := range results {
+		out = append(out, r.Status)
+	}
+
+	return out
+}
+
+func TestSync_AppliesJournalInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	pvzID := uuid.NewString()
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	open := operation(sync_domain.OpenReception, pvzID, start)
+	add := operation(sync_domain.AddProduct, pvzID, start.Add(time.Minute))
+	del := operation(sync_domain.DeleteLastProduct, pvzID, start.Add(2*time.Minute))
+	closeOp := operation(sync_domain.CloseReception, pvzID, start.Add(3*time.Minute))
+
+	m.journal.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(nil, sync_domain.ErrResultNotFound).Times(4)
+
+	var saved []*sync_domain.Result
+	m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, r *sync_domain.Result) error {
+			saved = append(saved, r)
+			return nil
+		}).Times(4)
+
+	gomock.InOrder(
+		m.reception.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p reception_svc.CreateParams) (*reception_domain.Reception, error) {
+				assert.Equal(t, pvzID, p.PVZID)
+				assert.Equal(t, open.ID, *p.ID)
+				assert.Equal(t, open.ClientTime, *p.DateTime)
+				return &reception_domain.Reception{ID: *p.ID, PVZID: p.PVZID}, nil
+			}),
+		m.product.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p product_svc.CreateParams) (*product_domain.Product, error) {
+				assert.Equal(t, product_domain.Shoes, p.Type)
+				assert.Equal(t, add.ID, *p.ID)
+				assert.Equal(t, add.ClientTime, *p.DateTime)
+				return &product_domain.Product{ID: *p.ID}, nil
+			}),
+		m.pvz.EXPECT().DeleteLastProduct(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p pvz_svc.DeleteLastProductParams) error {
+				assert.Equal(t, "employee@example.com", p.UserEmail)
+				assert.Equal(t, del.ClientTime, *p.DeletedAt)
+				return nil
+			}),
+		m.pvz.EXPECT().CloseLastReception(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p pvz_svc.CloseLastReceptionParams) (*reception_domain.Reception, error) {
+				assert.Equal(t, closeOp.ClientTime, *p.ClosedAt)
+				return &reception_domain.Reception{ID: open.ID, PVZID: p.PVZID}, nil
+			}),
+	)
+
+	results, err := svc.Sync(context.Background(), syncParams(open, add, del, closeOp))
+	require.NoError(t, err)
+
+	assert.Equal(t, []sync_domain.Status{
+		sync_domain.StatusApplied, sync_domain.StatusApplied, sync_domain.StatusApplied, sync_domain.StatusApplied,
+	}, statuses(results))
+	assert.Equal(t, open.ID, results[0].EntityID)
+	assert.Equal(t, add.ID, results[1].EntityID)
+	assert.Equal(t, open.ID, results[3].EntityID)
+	assert.Equal(t, results, saved)
+}
+
+func TestSync_ReplayReturnsSavedResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	op := operation(sync_domain.OpenReception, uuid.NewString(), time.Now())
+	m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(&sync_domain.Result{
+		OperationID: op.ID,
+		Type:        op.Type,
+		Status:      sync_domain.StatusApplied,
+		EntityID:    op.ID,
+	}, nil)
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, sync_domain.StatusApplied, results[0].Status)
+	assert.True(t, results[0].Duplicate)
+}
+
+func TestSync_ConflictsAndRejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	pvzID := uuid.NewString()
+	open := operation(sync_domain.OpenReception, pvzID, time.Now())
+	unknown := operation("scan_barcode", pvzID, time.Now())
+	badID := operation(sync_domain.CloseReception, pvzID, time.Now())
+	badID.ID = "not-uuid"
+	noTime := operation(sync_domain.CloseReception, pvzID, time.Time{})
+
+	// operation with invalid id is neither looked up nor saved
+	m.journal.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(nil, sync_domain.ErrResultNotFound).Times(3)
+	m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	m.reception.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, reception_domain.ErrFoundOpenedReception)
+
+	results, err := svc.Sync(context.Background(), syncParams(open, unknown, badID, noTime))
+	require.NoError(t, err)
+
+	assert.Equal(t, []sync_domain.Status{
+		sync_domain.StatusConflict, sync_domain.StatusRejected, sync_domain.StatusRejected, sync_domain.StatusRejected,
+	}, statuses(results))
+	assert.Equal(t, reception_domain.ErrFoundOpenedReception.Error(), results[0].Error)
+	for _, r := range results {
+		assert.NotEmpty(t, r.Error)
+		assert.Empty(t, r.EntityID)
+	}
+}
+
+func TestSync_StopsOnInternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	pvzID := uuid.NewString()
+	add := operation(sync_domain.AddProduct, pvzID, time.Now())
+	closeOp := operation(sync_domain.CloseReception, pvzID, time.Now())
+
+	m.journal.EXPECT().Claim(gomock.Any(), add.ID).Return(nil, sync_domain.ErrResultNotFound)
+	m.product.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, product_domain.ErrInternalDatabase)
+
+	results, err := svc.Sync(context.Background(), syncParams(add, closeOp))
+	require.NoError(t, err)
+
+	assert.Equal(t, []sync_domain.Status{sync_domain.StatusFailed, sync_domain.StatusSkipped}, statuses(results))
+	assert.Empty(t, results[0].Error, "internal errors are not shown")
+	// savepoint of operation and then transaction with claim are rolled back
+	assert.Len(t, *m.rolledBack, 2)
+}
+
+func TestSync_SaveErrorRollsBackOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	op := operation(sync_domain.OpenReception, uuid.NewString(), time.Now())
+
+	gomock.InOrder(
+		m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(nil, sync_domain.ErrResultNotFound),
+		m.reception.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&reception_domain.Reception{ID: op.ID}, nil),
+		m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(sync_domain.ErrInternalDatabase),
+	)
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+
+	assert.Equal(t, sync_domain.StatusFailed, results[0].Status)
+	assert.Empty(t, results[0].EntityID)
+	require.Len(t, *m.rolledBack, 1)
+	assert.ErrorIs(t, (*m.rolledBack)[0], sync_domain.ErrInternalDatabase)
+}
+
+func TestSync_SaveErrorDropsEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	pvzID := uuid.NewString()
+	op := operation(sync_domain.OpenReception, pvzID, time.Now())
+
+	auditor := audit_mocks.NewMockAuditor(ctrl)
+	auditor.EXPECT().Record(gomock.Any(), gomock.Any()).Times(1)
+	pvzRepo := pvz_mocks.NewMockPVZRepository(ctrl)
+	pvzRepo.EXPECT().GetByID(gomock.Any(), pvzID).Return(&pvz_domain.PVZ{ID: &pvzID, City: pvz_domain.Kazan}, nil)
+
+	broker := events_svc.NewBroker(10, 10)
+	sub, err := broker.Subscribe(events_domain.Filter{}, "")
+	require.NoError(t, err)
+	publisher := events_svc.NewPublisher(auditor, broker, reception_mocks.NewMockReceptionRepository(ctrl), pvzRepo, logger.NewTestLogger())
+
+	gomock.InOrder(
+		m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(nil, sync_domain.ErrResultNotFound),
+		m.reception.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, p reception_svc.CreateParams) (*reception_domain.Reception, error) {
+				reception := &reception_domain.Reception{ID: *p.ID, DateTime: *p.DateTime, PVZID: p.PVZID, Status: reception_domain.InProgress}
+				publisher.Record(ctx, audit_domain.Event{Action: audit_domain.ActionReceptionOpen, After: reception})
+				return reception, nil
+			}),
+		m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(sync_domain.ErrInternalDatabase),
+	)
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+
+	assert.Equal(t, sync_domain.StatusFailed, results[0].Status)
+	assert.Empty(t, sub.Events(), "event of rolled back operation is not published")
+}
+
+func TestSync_ProductAlreadyCreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	op := operation(sync_domain.AddProduct, uuid.NewString(), time.Now())
+
+	m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(nil, sync_domain.ErrResultNotFound)
+	m.product.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, product_domain.ErrProductAlreadyExists)
+	m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+
+	assert.Equal(t, sync_domain.StatusApplied, results[0].Status)
+	assert.Equal(t, op.ID, results[0].EntityID)
+	assert.Empty(t, results[0].Error)
+}
+
+func TestSync_ClientTimeInFuture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	op := operation(sync_domain.OpenReception, uuid.NewString(), time.Now().Add(time.Hour))
+
+	m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(nil, sync_domain.ErrResultNotFound)
+	m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	m.reception.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p reception_svc.CreateParams) (*reception_domain.Reception, error) {
+			assert.False(t, p.DateTime.After(time.Now()))
+			return &reception_domain.Reception{ID: *p.ID}, nil
+		})
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+	assert.Equal(t, sync_domain.StatusApplied, results[0].Status)
+}
+
+func TestSync_ConcurrentReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, m := newSyncService(ctrl)
+
+	op := operation(sync_domain.CloseReception, uuid.NewString(), time.Now())
+	first := &sync_domain.Result{OperationID: op.ID, Type: op.Type, Status: sync_domain.StatusApplied, EntityID: uuid.NewString()}
+
+	gomock.InOrder(
+		m.journal.EXPECT().Claim(gomock.Any(), op.ID).Return(nil, sync_domain.ErrResultNotFound),
+		m.pvz.EXPECT().CloseLastReception(gomock.Any(), gomock.Any()).Return(nil, reception_domain.ErrNoOpenReception),
+		m.journal.EXPECT().Save(gomock.Any(), gomock.Any()).Return(sync_domain.ErrResultAlreadySaved),
+		m.journal.EXPECT().Find(gomock.Any(), op.ID).Return(first, nil),
+	)
+
+	results, err := svc.Sync(context.Background(), syncParams(op))
+	require.NoError(t, err)
+
+	assert.Equal(t, sync_domain.StatusApplied, results[0].Status)
+	assert.Equal(t, first.EntityID, results[0].EntityID)
+	assert.True(t, results[0].Duplicate)
+}
+
+func TestSync_AccessDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc, _ := newSyncService(ctrl)
+
+	params := syncParams(operation(sync_domain.OpenReception, uuid.NewString(), time.Now()))
+	params.UserRole = auth_domain.RoleModerator
+
+	_, err := svc.Sync(context.Background(), params)
+	assert.ErrorIs(t, err, sync_domain.ErrAccessDenied)
+}
@@ -0,0 +1,33 @@
+package application
+
+import (
+	"context"
+
+	product_svc "github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+)
+
+// Transactor runs fn in one transaction, repositories called with ctx passed
+// to fn take part in it, nested calls run in savepoint (see database.Transactor).
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Operations are replayed through same services as online requests,
+// so they are validated, audited and counted in metrics the same way.
+
+type ReceptionService interface {
+	Create(ctx context.Context, params reception_svc.CreateParams) (*reception_domain.Reception, error)
+}
+
+type ProductService interface {
+	Create(ctx context.Context, params product_svc.CreateParams) (*product_domain.Product, error)
+}
+
+type PVZService interface {
+	CloseLastReception(ctx context.Context, params pvz_svc.CloseLastReceptionParams) (*reception_domain.Reception, error)
+	DeleteLastProduct(ctx context.Context, params pvz_svc.DeleteLastProductParams) error
+}
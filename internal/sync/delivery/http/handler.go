@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/apperr"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	"github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+)
+
+type SyncService interface {
+	Sync(ctx context.Context, params application.SyncParams) ([]*sync_domain.Result, error)
+}
+
+type Handler struct {
+	svc SyncService
+}
+
+func NewHandler(svc SyncService) *Handler {
+	return &Handler{
+		svc: svc,
+	}
+}
+
+func (h Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /sync", h.Sync)
+}
+
+// Sync replays journal of offline terminal. Response is 200 even if some
+// operations are not applied, status of every operation is in results.
+func (h *Handler) Sync(w http.ResponseWriter, r *http.Request) {
+	var req SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperr.WriteHTTP(w, r, apperr.ErrInvalidBody)
+		return
+	}
+
+	claims, ok := r.Context().Value(httpcommon.DefaultUserKey).(*httpcommon.Claims)
+	if !ok {
+		apperr.WriteHTTP(w, r, apperr.ErrAccessDenied)
+		return
+	}
+
+	operations := make([]sync_domain.Operation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		operations = append(operations, sync_domain.Operation{
+			ID:          op.ID,
+			Type:        sync_domain.OperationType(op.Type),
+			PVZID:       op.PVZID,
+			ProductType: product_domain.ProductType(op.ProductType),
+			ClientTime:  op.ClientTime,
+		})
+	}
+
+	params := application.SyncParams{
+		Operations: operations,
+		UserRole:   auth_domain.Role(claims.Role),
+		UserEmail:  claims.Email,
+	}
+
+	results, err := h.svc.Sync(r.Context(), params)
+	if err != nil {
+		apperr.WriteHTTP(w, r, err)
+		return
+	}
+
+	resp := SyncResponse{
+		Results: make([]OperationResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		resp.Results = append(resp.Results, OperationResponse{
+			ID:        result.OperationID,
+			Type:      result.Type.String(),
+			Status:    result.Status.String(),
+			EntityID:  result.EntityID,
+			Error:     result.Error,
+			AppliedAt: result.AppliedAt,
+			Duplicate: result.Duplicate,
+		})
+	}
+
+	httpcommon.JSONResponse(w, http.StatusOK, resp)
+}
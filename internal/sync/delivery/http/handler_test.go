@@ -0,0 +1,137 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	"github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_http "github.com/0x0FACED/pvz-avito/internal/sync/delivery/http"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	"github.com/0x0FACED/pvz-avito/internal/sync/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSyncHandler_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pvzID := uuid.NewString()
+	clientTime := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	open := sync_http.OperationRequest{ID: uuid.NewString(), Type: "open_reception", PVZID: pvzID, ClientTime: clientTime}
+	add := sync_http.OperationRequest{ID: uuid.NewString(), Type: "add_product", PVZID: pvzID, ProductType: "обувь", ClientTime: clientTime}
+
+	tests := []struct {
+		name           string
+		request        sync_http.SyncRequest
+		userRole       string
+		mockSetup      func(*mocks.MockSyncService)
+		expectedStatus int
+		expectError    string
+		expectResults  []sync_http.OperationResponse
+	}{
+		{
+			name:     "results of every operation",
+			request:  sync_http.SyncRequest{Operations: []sync_http.OperationRequest{open, add}},
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockSyncService) {
+				m.EXPECT().Sync(gomock.Any(), application.SyncParams{
+					Operations: []sync_domain.Operation{
+						{ID: open.ID, Type: sync_domain.OpenReception, PVZID: pvzID, ClientTime: clientTime},
+						{ID: add.ID, Type: sync_domain.AddProduct, PVZID: pvzID, ProductType: product_domain.Shoes, ClientTime: clientTime},
+					},
+					UserRole:  auth_domain.RoleEmployee,
+					UserEmail: "employee@example.com",
+				}).Return([]*sync_domain.Result{
+					{OperationID: open.ID, Type: sync_domain.OpenReception, Status: sync_domain.StatusApplied, EntityID: open.ID, Duplicate: true},
+					{OperationID: add.ID, Type: sync_domain.AddProduct, Status: sync_domain.StatusConflict, Error: "reception: no open reception found"},
+				}, nil)
+			},
+			expectedStatus: nethttp.StatusOK,
+			expectResults: []sync_http.OperationResponse{
+				{ID: open.ID, Type: "open_reception", Status: "applied", EntityID: open.ID, Duplicate: true},
+				{ID: add.ID, Type: "add_product", Status: "conflict", Error: "reception: no open reception found"},
+			},
+		},
+		{
+			name:     "empty journal",
+			request:  sync_http.SyncRequest{},
+			userRole: "employee",
+			mockSetup: func(m *mocks.MockSyncService) {
+				m.EXPECT().Sync(gomock.Any(), gomock.Any()).Return(nil, sync_domain.ErrEmptyJournal)
+			},
+			expectedStatus: nethttp.StatusBadRequest,
+			expectError:    "no operations to sync",
+		},
+		{
+			name:     "access denied",
+			request:  sync_http.SyncRequest{Operations: []sync_http.OperationRequest{open}},
+			userRole: "moderator",
+			mockSetup: func(m *mocks.MockSyncService) {
+				m.EXPECT().Sync(gomock.Any(), gomock.Any()).Return(nil, sync_domain.ErrAccessDenied)
+			},
+			expectedStatus: nethttp.StatusForbidden,
+			expectError:    "access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockSyncService(ctrl)
+			tt.mockSetup(svc)
+
+			handler := sync_http.NewHandler(svc)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/sync", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), httpcommon.DefaultUserKey, &httpcommon.Claims{
+				Email: "employee@example.com",
+				Role:  tt.userRole,
+			})
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.Sync(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectError != "" {
+				var errResp httpcommon.ErrorResponse
+				_ = json.NewDecoder(rec.Body).Decode(&errResp)
+				assert.Equal(t, tt.expectError, errResp.Error())
+			} else {
+				var resp sync_http.SyncResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				for i := range resp.Results {
+					resp.Results[i].AppliedAt = time.Time{}
+				}
+				assert.Equal(t, tt.expectResults, resp.Results)
+			}
+		})
+	}
+}
+
+func TestSyncHandler_InvalidBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := sync_http.NewHandler(mocks.NewMockSyncService(ctrl))
+
+	req := httptest.NewRequest("POST", "/sync", bytes.NewReader([]byte("{")))
+	rec := httptest.NewRecorder()
+
+	handler.Sync(rec, req)
+
+	assert.Equal(t, nethttp.StatusBadRequest, rec.Code)
+}
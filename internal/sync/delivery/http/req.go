@@ -0,0 +1,15 @@
+package http
+
+import "time"
+
+type OperationRequest struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	PVZID       string    `json:"pvzId"`
+	ProductType string    `json:"productType,omitempty"`
+	ClientTime  time.Time `json:"clientTime"`
+}
+
+type SyncRequest struct {
+	Operations []OperationRequest `json:"operations"`
+}
@@ -0,0 +1,18 @@
+package http
+
+import "time"
+
+type OperationResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	EntityID  string    `json:"entityId,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	AppliedAt time.Time `json:"appliedAt"`
+	// Duplicate is set if operation was already replayed by earlier sync.
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+type SyncResponse struct {
+	Results []OperationResponse `json:"results"`
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+)
+
+type OperationType string
+
+const (
+	OpenReception     OperationType = "open_reception"
+	AddProduct        OperationType = "add_product"
+	DeleteLastProduct OperationType = "delete_last_product"
+	CloseReception    OperationType = "close_reception"
+)
+
+func (t OperationType) String() string {
+	return string(t)
+}
+
+func (t OperationType) Validate() error {
+	switch t {
+	case OpenReception, AddProduct, DeleteLastProduct, CloseReception:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidOperationType, t)
+}
+
+// Operation is one entry of terminal journal, made while terminal was offline.
+// ID is generated by terminal and makes replay idempotent,
+// it is also used as id of created reception or product.
+type Operation struct {
+	ID          string
+	Type        OperationType
+	PVZID       string
+	ProductType product_domain.ProductType // add_product only
+	ClientTime  time.Time
+}
+
+type Status string
+
+const (
+	// StatusApplied means operation changed server state.
+	StatusApplied Status = "applied"
+	// StatusConflict means operation is valid, but server state does not allow it,
+	// e.g. reception was opened by another terminal.
+	StatusConflict Status = "conflict"
+	// StatusRejected means operation is invalid and will never be applied.
+	StatusRejected Status = "rejected"
+	// StatusFailed means internal error, operation is not saved and can be retried.
+	StatusFailed Status = "failed"
+	// StatusSkipped is set for operations after failed one, they can be retried.
+	StatusSkipped Status = "skipped"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Final reports whether result is saved and returned on every replay.
+func (s Status) Final() bool {
+	return s == StatusApplied || s == StatusConflict || s == StatusRejected
+}
+
+// Result of replaying one operation. Final results are saved in journal,
+// so replaying same operation again returns saved result with Duplicate set.
+type Result struct {
+	OperationID string
+	Type        OperationType
+	PVZID       string
+	Status      Status
+	// EntityID is id of reception or product changed by operation, may be empty.
+	EntityID   string
+	Error      string
+	UserEmail  string
+	ClientTime time.Time
+	AppliedAt  time.Time
+	Duplicate  bool
+}
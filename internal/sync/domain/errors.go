@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInternalDatabase   = errors.New("sync: internal database error")
+	ErrResultNotFound     = errors.New("sync: operation result not found")
+	ErrResultAlreadySaved = errors.New("sync: operation result already saved")
+)
+
+var (
+	ErrAccessDenied         = errors.New("sync: only employees can sync operations")
+	ErrEmptyJournal         = errors.New("sync: no operations to sync")
+	ErrJournalTooLarge      = errors.New("sync: too many operations in one sync")
+	ErrInvalidOperationType = errors.New("sync: invalid operation type")
+	ErrInvalidIDFormat      = errors.New("sync: invalid id format")
+	ErrInvalidClientTime    = errors.New("sync: invalid client time")
+)
@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// JournalRepository keeps final results of replayed operations by operation id.
+type JournalRepository interface {
+	// Save fails with ErrResultAlreadySaved if result of operation is already saved.
+	Save(ctx context.Context, result *Result) error
+	// Claim locks operation id till end of transaction of ctx, so concurrent
+	// replays of same operation run one after another, and returns saved result.
+	// It fails with ErrResultNotFound if operation was never replayed.
+	Claim(ctx context.Context, operationID string) (*Result, error)
+	// Find fails with ErrResultNotFound if operation was never replayed.
+	Find(ctx context.Context, operationID string) (*Result, error)
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+)
+
+// JournalMemoryRepository keeps operation results in shared memdb.DB.
+// Suitable for demo mode and tests only, data is lost on restart.
+type JournalMemoryRepository struct {
+	db *memdb.DB
+}
+
+func NewJournalMemoryRepository(db *memdb.DB) *JournalMemoryRepository {
+	return &JournalMemoryRepository{db: db}
+}
+
+func (r *JournalMemoryRepository) Save(_ context.Context, result *sync_domain.Result) error {
+	r.db.Lock()
+	defer r.db.Unlock()
+
+	for _, row := range r.db.SyncJournal {
+		if row.OperationID == result.OperationID {
+			return fmt.Errorf("%w: operation %s", sync_domain.ErrResultAlreadySaved, result.OperationID)
+		}
+	}
+
+	row := *result
+	row.Duplicate = false
+	r.db.SyncJournal = append(r.db.SyncJournal, &row)
+
+	return nil
+}
+
+// Claim needs no lock: memdb.DB runs transactions one by one.
+func (r *JournalMemoryRepository) Claim(ctx context.Context, operationID string) (*sync_domain.Result, error) {
+	return r.Find(ctx, operationID)
+}
+
+func (r *JournalMemoryRepository) Find(_ context.Context, operationID string) (*sync_domain.Result, error) {
+	r.db.RLock()
+	defer r.db.RUnlock()
+
+	for _, row := range r.db.SyncJournal {
+		if row.OperationID == operationID {
+			result := *row
+			return &result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: operation %s", sync_domain.ErrResultNotFound, operationID)
+}
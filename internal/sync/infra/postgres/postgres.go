@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JournalPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewJournalPostgresRepository(pgx *pgxpool.Pool) *JournalPostgresRepository {
+	return &JournalPostgresRepository{pool: pgx}
+}
+
+func (r *JournalPostgresRepository) Save(ctx context.Context, result *sync_domain.Result) error {
+	query := `
+		INSERT INTO avito.sync_journal (operation_id, type, pvz_id, status, entity_id, error, user_email, client_time, applied_at)
+		VALUES (@operation_id, @type, @pvz_id, @status, @entity_id, @error, @user_email, @client_time, @applied_at)
+	`
+
+	args := pgx.NamedArgs{
+		"operation_id": result.OperationID,
+		"type":         result.Type,
+		"pvz_id":       result.PVZID,
+		"status":       result.Status,
+		"entity_id":    result.EntityID,
+		"error":        result.Error,
+		"user_email":   result.UserEmail,
+		"client_time":  result.ClientTime,
+		"applied_at":   result.AppliedAt,
+	}
+
+	if _, err := database.Pgx(ctx, r.pool).Exec(ctx, query, args); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: %w", sync_domain.ErrResultAlreadySaved, err)
+		}
+		return fmt.Errorf("%w: %w", sync_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+// Claim takes transaction-level advisory lock keyed by operation id,
+// ctx must carry transaction of database.PgxTransactor.
+func (r *JournalPostgresRepository) Claim(ctx context.Context, operationID string) (*sync_domain.Result, error) {
+	query := `SELECT pg_advisory_xact_lock(hashtextextended(@operation_id, 0))`
+
+	if _, err := database.Pgx(ctx, r.pool).Exec(ctx, query, pgx.NamedArgs{"operation_id": operationID}); err != nil {
+		return nil, fmt.Errorf("%w: %w", sync_domain.ErrInternalDatabase, err)
+	}
+
+	return r.Find(ctx, operationID)
+}
+
+func (r *JournalPostgresRepository) Find(ctx context.Context, operationID string) (*sync_domain.Result, error) {
+	query := `
+		SELECT operation_id, type, pvz_id, status, entity_id, error, user_email, client_time, applied_at
+		FROM avito.sync_journal
+		WHERE operation_id = @operation_id
+	`
+
+	var result sync_domain.Result
+	err := database.Pgx(ctx, r.pool).QueryRow(ctx, query, pgx.NamedArgs{"operation_id": operationID}).Scan(
+		&result.OperationID, &result.Type, &result.PVZID, &result.Status, &result.EntityID,
+		&result.Error, &result.UserEmail, &result.ClientTime, &result.AppliedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", sync_domain.ErrResultNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", sync_domain.ErrInternalDatabase, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type JournalSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewJournalSQLiteRepository(db *sql.DB) *JournalSQLiteRepository {
+	return &JournalSQLiteRepository{db: db}
+}
+
+func (r *JournalSQLiteRepository) Save(ctx context.Context, result *sync_domain.Result) error {
+	query := `
+		INSERT INTO sync_journal (operation_id, type, pvz_id, status, entity_id, error, user_email, client_time, applied_at)
+		VALUES (@operation_id, @type, @pvz_id, @status, @entity_id, @error, @user_email, @client_time, @applied_at)
+	`
+
+	_, err := database.SQLite(ctx, r.db).ExecContext(ctx, query,
+		sql.Named("operation_id", result.OperationID),
+		sql.Named("type", result.Type.String()),
+		sql.Named("pvz_id", result.PVZID),
+		sql.Named("status", result.Status.String()),
+		sql.Named("entity_id", result.EntityID),
+		sql.Named("error", result.Error),
+		sql.Named("user_email", result.UserEmail),
+		sql.Named("client_time", database.SQLiteTime(result.ClientTime)),
+		sql.Named("applied_at", database.SQLiteTime(result.AppliedAt)),
+	)
+	if err != nil {
+		if database.SQLiteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return fmt.Errorf("%w: %w", sync_domain.ErrResultAlreadySaved, err)
+		}
+		return fmt.Errorf("%w: %w", sync_domain.ErrInternalDatabase, err)
+	}
+
+	return nil
+}
+
+// Claim needs no lock: database has one connection, so transaction
+// of ctx is the only one until it ends.
+func (r *JournalSQLiteRepository) Claim(ctx context.Context, operationID string) (*sync_domain.Result, error) {
+	return r.Find(ctx, operationID)
+}
+
+func (r *JournalSQLiteRepository) Find(ctx context.Context, operationID string) (*sync_domain.Result, error) {
+	query := `
+		SELECT operation_id, type, pvz_id, status, entity_id, error, user_email, client_time, applied_at
+		FROM sync_journal
+		WHERE operation_id = @operation_id
+	`
+
+	var result sync_domain.Result
+	err := database.SQLite(ctx, r.db).QueryRowContext(ctx, query, sql.Named("operation_id", operationID)).Scan(
+		&result.OperationID, &result.Type, &result.PVZID, &result.Status, &result.EntityID,
+		&result.Error, &result.UserEmail, &result.ClientTime, &result.AppliedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", sync_domain.ErrResultNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", sync_domain.ErrInternalDatabase, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sync/domain/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/sync/domain/repository.go -destination=internal/sync/mocks/journal_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJournalRepository is a mock of JournalRepository interface.
+type MockJournalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJournalRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockJournalRepositoryMockRecorder is the mock recorder for MockJournalRepository.
+type MockJournalRepositoryMockRecorder struct {
+	mock *MockJournalRepository
+}
+
+// NewMockJournalRepository creates a new mock instance.
+func NewMockJournalRepository(ctrl *gomock.Controller) *MockJournalRepository {
+	mock := &MockJournalRepository{ctrl: ctrl}
+	mock.recorder = &MockJournalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJournalRepository) EXPECT() *MockJournalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockJournalRepository) Claim(ctx context.Context, operationID string) (*domain.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, operationID)
+	ret0, _ := ret[0].(*domain.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockJournalRepositoryMockRecorder) Claim(ctx, operationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockJournalRepository)(nil).Claim), ctx, operationID)
+}
+
+// Find mocks base method.
+func (m *MockJournalRepository) Find(ctx context.Context, operationID string) (*domain.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, operationID)
+	ret0, _ := ret[0].(*domain.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockJournalRepositoryMockRecorder) Find(ctx, operationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockJournalRepository)(nil).Find), ctx, operationID)
+}
+
+// Save mocks base method.
+func (m *MockJournalRepository) Save(ctx context.Context, result *domain.Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockJournalRepositoryMockRecorder) Save(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockJournalRepository)(nil).Save), ctx, result)
+}
@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sync/application/services.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/sync/application/services.go -destination=internal/sync/mocks/services_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	application "github.com/0x0FACED/pvz-avito/internal/product/application"
+	domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	application0 "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	application1 "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	domain0 "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockTransactor) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockTransactorMockRecorder) InTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTransactor)(nil).InTx), ctx, fn)
+}
+
+// MockReceptionService is a mock of ReceptionService interface.
+type MockReceptionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceptionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReceptionServiceMockRecorder is the mock recorder for MockReceptionService.
+type MockReceptionServiceMockRecorder struct {
+	mock *MockReceptionService
+}
+
+// NewMockReceptionService creates a new mock instance.
+func NewMockReceptionService(ctrl *gomock.Controller) *MockReceptionService {
+	mock := &MockReceptionService{ctrl: ctrl}
+	mock.recorder = &MockReceptionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceptionService) EXPECT() *MockReceptionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReceptionService) Create(ctx context.Context, params application1.CreateParams) (*domain0.Reception, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, params)
+	ret0, _ := ret[0].(*domain0.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReceptionServiceMockRecorder) Create(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReceptionService)(nil).Create), ctx, params)
+}
+
+// MockProductService is a mock of ProductService interface.
+type MockProductService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductServiceMockRecorder is the mock recorder for MockProductService.
+type MockProductServiceMockRecorder struct {
+	mock *MockProductService
+}
+
+// NewMockProductService creates a new mock instance.
+func NewMockProductService(ctrl *gomock.Controller) *MockProductService {
+	mock := &MockProductService{ctrl: ctrl}
+	mock.recorder = &MockProductServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductService) Create(ctx context.Context, params application.CreateParams) (*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, params)
+	ret0, _ := ret[0].(*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductServiceMockRecorder) Create(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductService)(nil).Create), ctx, params)
+}
+
+// MockPVZService is a mock of PVZService interface.
+type MockPVZService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPVZServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPVZServiceMockRecorder is the mock recorder for MockPVZService.
+type MockPVZServiceMockRecorder struct {
+	mock *MockPVZService
+}
+
+// NewMockPVZService creates a new mock instance.
+func NewMockPVZService(ctrl *gomock.Controller) *MockPVZService {
+	mock := &MockPVZService{ctrl: ctrl}
+	mock.recorder = &MockPVZServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPVZService) EXPECT() *MockPVZServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseLastReception mocks base method.
+func (m *MockPVZService) CloseLastReception(ctx context.Context, params application0.CloseLastReceptionParams) (*domain0.Reception, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseLastReception", ctx, params)
+	ret0, _ := ret[0].(*domain0.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseLastReception indicates an expected call of CloseLastReception.
+func (mr *MockPVZServiceMockRecorder) CloseLastReception(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseLastReception", reflect.TypeOf((*MockPVZService)(nil).CloseLastReception), ctx, params)
+}
+
+// DeleteLastProduct mocks base method.
+func (m *MockPVZService) DeleteLastProduct(ctx context.Context, params application0.DeleteLastProductParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLastProduct", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLastProduct indicates an expected call of DeleteLastProduct.
+func (mr *MockPVZServiceMockRecorder) DeleteLastProduct(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockPVZService)(nil).DeleteLastProduct), ctx, params)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE avito.receptions DROP COLUMN IF EXISTS close_reason;
+
+DROP TYPE IF EXISTS avito.close_reason_enum;
@@ -0,0 +1,3 @@
+CREATE TYPE avito.close_reason_enum AS ENUM ('manual', 'auto_closed');
+
+ALTER TABLE avito.receptions ADD COLUMN IF NOT EXISTS close_reason avito.close_reason_enum;
@@ -0,0 +1,2 @@
+ALTER TABLE avito.receptions DROP COLUMN IF EXISTS closed_by;
+ALTER TABLE avito.receptions DROP COLUMN IF EXISTS closed_at;
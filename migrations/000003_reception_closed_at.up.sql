@@ -0,0 +1,2 @@
+ALTER TABLE avito.receptions ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+ALTER TABLE avito.receptions ADD COLUMN IF NOT EXISTS closed_by VARCHAR(320);
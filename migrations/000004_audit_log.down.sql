@@ -0,0 +1 @@
+DROP TABLE IF EXISTS avito.audit_log;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS avito.audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    actor_email VARCHAR(320),
+    actor_role VARCHAR(32),
+    action VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(320),
+    client_ip VARCHAR(64),
+    request_id VARCHAR(128),
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON avito.audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_email ON avito.audit_log(actor_email);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON avito.audit_log(entity_type, entity_id);
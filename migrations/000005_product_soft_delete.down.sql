@@ -0,0 +1,7 @@
+-- soft deleted rows become visible again, remove them physically first
+DELETE FROM avito.products WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS avito.idx_products_reception_id_alive;
+
+ALTER TABLE avito.products DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE avito.products DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+ALTER TABLE avito.products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE avito.products ADD COLUMN IF NOT EXISTS deleted_by VARCHAR(320);
+
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_alive ON avito.products(reception_id, date_time) WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS avito.login_attempts (
+    key VARCHAR(400) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
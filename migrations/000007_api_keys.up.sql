@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS avito.api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    key_hash VARCHAR(128) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    pvz_id UUID REFERENCES avito.pvz(id) ON DELETE CASCADE,
+    created_by VARCHAR(320) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
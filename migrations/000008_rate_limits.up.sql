@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS avito.rate_limits (
+    key VARCHAR(600) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON avito.rate_limits(expires_at);
@@ -0,0 +1,13 @@
+-- results of operations replayed from offline terminals journals,
+-- type and pvz_id are not checked, rejected operations are saved as they came
+CREATE TABLE IF NOT EXISTS avito.sync_journal (
+    operation_id UUID PRIMARY KEY,
+    type TEXT NOT NULL,
+    pvz_id TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('applied', 'conflict', 'rejected')),
+    entity_id TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    user_email VARCHAR(320) NOT NULL,
+    client_time TIMESTAMP NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS avito.idx_pvz_registration_date_id;
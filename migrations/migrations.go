@@ -0,0 +1,68 @@
+// Package migrations embeds sql migrations in golang-migrate format
+// (<version>_<name>.up.sql / .down.sql), so binary knows schema version it expects.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLiteFS is migrations of sqlite storage driver. Schema is same,
+// but versions are independent from postgres ones.
+var SQLiteFS = mustSub(sqliteFS, "sqlite")
+
+// LatestVersion returns version of newest up migration.
+func LatestVersion() (uint, error) {
+	return latestVersion(FS)
+}
+
+// SQLiteLatestVersion returns version of newest sqlite up migration.
+func SQLiteLatestVersion() (uint, error) {
+	return latestVersion(SQLiteFS)
+}
+
+func latestVersion(fsys fs.FS) (uint, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".up.sql") {
+			continue
+		}
+
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			return 0, fmt.Errorf("invalid migration name %q", e.Name())
+		}
+
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration version %q: %w", e.Name(), err)
+		}
+
+		latest = max(latest, uint(v))
+	}
+
+	return latest, nil
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+
+	return sub
+}
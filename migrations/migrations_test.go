@@ -0,0 +1,40 @@
+package migrations_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	v, err := migrations.LatestVersion()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, v, uint(10))
+}
+
+func TestSQLiteLatestVersion(t *testing.T) {
+	v, err := migrations.SQLiteLatestVersion()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, v, uint(3))
+}
+
+func TestEveryUpHasDown(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"postgres": migrations.FS, "sqlite": migrations.SQLiteFS} {
+		entries, err := fs.ReadDir(fsys, ".")
+		require.NoError(t, err, name)
+
+		for _, e := range entries {
+			migration, ok := strings.CutSuffix(e.Name(), ".up.sql")
+			if !ok {
+				continue
+			}
+
+			_, err := fs.Stat(fsys, migration+".down.sql")
+			assert.NoError(t, err, "missing %s down migration for %s", name, e.Name())
+		}
+	}
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS receptions;
+DROP TABLE IF EXISTS pvz;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,84 @@
+-- sqlite schema matches postgres one after 000008,
+-- enums are emulated with CHECK constraints, uuids and timestamps are TEXT.
+-- Timestamps are written by repositories in fixed width UTC format,
+-- so they are compared as strings.
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    email VARCHAR(320) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('employee', 'moderator')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS pvz (
+    id TEXT PRIMARY KEY,
+    registration_date TIMESTAMP NOT NULL,
+    city TEXT NOT NULL CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'))
+);
+
+CREATE TABLE IF NOT EXISTS receptions (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    pvz_id TEXT NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('in_progress', 'close')),
+    close_reason TEXT CHECK (close_reason IN ('manual', 'auto_closed')),
+    closed_at TIMESTAMP,
+    closed_by VARCHAR(320)
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id TEXT PRIMARY KEY,
+    date_time TIMESTAMP NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('электроника', 'одежда', 'обувь')),
+    reception_id TEXT NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    deleted_at TIMESTAMP,
+    deleted_by VARCHAR(320)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_active_reception ON receptions(pvz_id) WHERE status = 'in_progress';
+
+CREATE INDEX IF NOT EXISTS idx_receptions_pvz_id ON receptions(pvz_id);
+CREATE INDEX IF NOT EXISTS idx_receptions_status ON receptions(status);
+CREATE INDEX IF NOT EXISTS idx_products_reception_id ON products(reception_id);
+CREATE INDEX IF NOT EXISTS idx_products_date_time ON products(date_time);
+CREATE INDEX IF NOT EXISTS idx_products_reception_id_alive ON products(reception_id, date_time) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    actor_email VARCHAR(320),
+    actor_role VARCHAR(32),
+    action VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(320),
+    client_ip VARCHAR(64),
+    request_id VARCHAR(128),
+    before TEXT CHECK (before IS NULL OR json_valid(before)),
+    after TEXT CHECK (after IS NULL OR json_valid(after))
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_email ON audit_log(actor_email);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(400) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+
+-- scopes is json array of strings
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    key_hash VARCHAR(128) NOT NULL,
+    scopes TEXT NOT NULL CHECK (json_valid(scopes)),
+    pvz_id TEXT REFERENCES pvz(id) ON DELETE CASCADE,
+    created_by VARCHAR(320) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
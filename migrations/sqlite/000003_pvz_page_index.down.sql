@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pvz_registration_date_id;
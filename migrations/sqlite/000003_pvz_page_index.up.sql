@@ -0,0 +1,2 @@
+-- keyset paging of ListPage reads pvz in (registration_date, id) order
+CREATE INDEX IF NOT EXISTS idx_pvz_registration_date_id ON pvz(registration_date, id);
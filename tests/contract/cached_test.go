@@ -0,0 +1,35 @@
+package contract
+
+import (
+	"testing"
+	"time"
+
+	auth_memory "github.com/0x0FACED/pvz-avito/internal/auth/infra/memory"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/cache"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/repotest"
+	product_memory "github.com/0x0FACED/pvz-avito/internal/product/infra/memory"
+	"github.com/0x0FACED/pvz-avito/internal/pvz/infra/cached"
+	pvz_memory "github.com/0x0FACED/pvz-avito/internal/pvz/infra/memory"
+	reception_memory "github.com/0x0FACED/pvz-avito/internal/reception/infra/memory"
+	sync_memory "github.com/0x0FACED/pvz-avito/internal/sync/infra/memory"
+)
+
+// TestCachedRepositories checks that cache decorators do not change repository contract,
+// listings must be invalidated by every write made in suite.
+func TestCachedRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := memdb.New()
+		pvzRepo := cached.NewPVZRepository(pvz_memory.NewPVZMemoryRepository(db), cache.NewLRU(128), time.Hour)
+
+		return repotest.Repositories{
+			PVZ:           pvzRepo,
+			Reception:     cached.NewReceptionRepository(reception_memory.NewReceptionMemoryRepository(db), pvzRepo),
+			Product:       cached.NewProductRepository(product_memory.NewProductMemoryRepository(db), pvzRepo),
+			User:          auth_memory.NewUserMemoryRepository(db),
+			Journal:       sync_memory.NewJournalMemoryRepository(db),
+			LoginAttempts: auth_memory.NewLoginAttemptMemoryRepository(),
+			Tx:            db,
+		}
+	})
+}
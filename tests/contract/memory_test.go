@@ -0,0 +1,29 @@
+package contract
+
+import (
+	"testing"
+
+	auth_memory "github.com/0x0FACED/pvz-avito/internal/auth/infra/memory"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/repotest"
+	product_memory "github.com/0x0FACED/pvz-avito/internal/product/infra/memory"
+	pvz_memory "github.com/0x0FACED/pvz-avito/internal/pvz/infra/memory"
+	reception_memory "github.com/0x0FACED/pvz-avito/internal/reception/infra/memory"
+	sync_memory "github.com/0x0FACED/pvz-avito/internal/sync/infra/memory"
+)
+
+func TestMemoryRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := memdb.New()
+
+		return repotest.Repositories{
+			PVZ:           pvz_memory.NewPVZMemoryRepository(db),
+			Reception:     reception_memory.NewReceptionMemoryRepository(db),
+			Product:       product_memory.NewProductMemoryRepository(db),
+			User:          auth_memory.NewUserMemoryRepository(db),
+			Journal:       sync_memory.NewJournalMemoryRepository(db),
+			LoginAttempts: auth_memory.NewLoginAttemptMemoryRepository(),
+			Tx:            db,
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package contract
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	auth_db "github.com/0x0FACED/pvz-avito/internal/auth/infra/postgres"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/repotest"
+	product_db "github.com/0x0FACED/pvz-avito/internal/product/infra/postgres"
+	pvz_db "github.com/0x0FACED/pvz-avito/internal/pvz/infra/postgres"
+	reception_db "github.com/0x0FACED/pvz-avito/internal/reception/infra/postgres"
+	sync_db "github.com/0x0FACED/pvz-avito/internal/sync/infra/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresRepositories uses test database and truncates it before every case,
+// so it must not run in parallel with integration tests (see make run-tests).
+func TestPostgresRepositories(t *testing.T) {
+	if driver := os.Getenv("STORAGE_DRIVER"); driver != "" && driver != "postgres" {
+		t.Skipf("storage driver is %s", driver)
+	}
+
+	ctx := context.Background()
+
+	pool, err := database.ConnectPool(ctx, config.LoadTest().Database)
+	if err != nil {
+		t.Skipf("test database is unavailable: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := database.NewMigrator(pool)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Close())
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		_, err := pool.Exec(ctx, "TRUNCATE avito.products, avito.receptions, avito.pvz, avito.users, avito.sync_journal CASCADE")
+		require.NoError(t, err)
+
+		return repotest.Repositories{
+			PVZ:           pvz_db.NewPVZPostgresRepository(pool),
+			Reception:     reception_db.NewReceptionPostgresRepository(pool),
+			Product:       product_db.NewProductPostgresRepository(pool),
+			User:          auth_db.NewAuthPostgresRepository(pool),
+			Journal:       sync_db.NewJournalPostgresRepository(pool),
+			LoginAttempts: auth_db.NewLoginAttemptPostgresRepository(pool),
+			Tx:            database.NewPgxTransactor(pool),
+			RollsBack:     true,
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	auth_sqlite "github.com/0x0FACED/pvz-avito/internal/auth/infra/sqlite"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/repotest"
+	product_sqlite "github.com/0x0FACED/pvz-avito/internal/product/infra/sqlite"
+	pvz_sqlite "github.com/0x0FACED/pvz-avito/internal/pvz/infra/sqlite"
+	reception_sqlite "github.com/0x0FACED/pvz-avito/internal/reception/infra/sqlite"
+	sync_sqlite "github.com/0x0FACED/pvz-avito/internal/sync/infra/sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteRepositories(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	n := 0
+
+	// every case gets new database file
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		n++
+		cfg := config.SQLiteConfig{
+			Path:        filepath.Join(dir, fmt.Sprintf("contract_%d.db", n)),
+			BusyTimeout: 5 * time.Second,
+		}
+
+		migrator, err := database.NewSQLiteMigrator(ctx, cfg)
+		require.NoError(t, err)
+		require.NoError(t, migrator.Up())
+		require.NoError(t, migrator.Close())
+
+		db, err := database.OpenSQLite(ctx, cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		return repotest.Repositories{
+			PVZ:           pvz_sqlite.NewPVZSQLiteRepository(db),
+			Reception:     reception_sqlite.NewReceptionSQLiteRepository(db),
+			Product:       product_sqlite.NewProductSQLiteRepository(db),
+			User:          auth_sqlite.NewAuthSQLiteRepository(db),
+			Journal:       sync_sqlite.NewJournalSQLiteRepository(db),
+			LoginAttempts: auth_sqlite.NewLoginAttemptSQLiteRepository(db),
+			Tx:            database.NewSQLiteTransactor(db),
+			RollsBack:     true,
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	events_http "github.com/0x0FACED/pvz-avito/internal/events/delivery/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscribeEvents opens event stream and returns channel of received events.
+func subscribeEvents(t *testing.T, ctx context.Context, url, token string) <-chan events_http.EventResponse {
+	t.Helper()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan events_http.EventResponse, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var e events_http.EventResponse
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				return
+			}
+			events <- e
+		}
+	}()
+
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan events_http.EventResponse) events_http.EventResponse {
+	t.Helper()
+
+	select {
+	case e, ok := <-events:
+		require.True(t, ok, "stream closed")
+		return e
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no event received")
+		return events_http.EventResponse{}
+	}
+}
+
+func TestIntegration_Events(t *testing.T) {
+	baseURL := "http://localhost:8080"
+
+	moderatorToken := authUserDummy(t, baseURL, "moderator")
+	employeeToken := authUserDummy(t, baseURL, "employee")
+
+	pvzID := createPVZ(t, baseURL, moderatorToken)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := subscribeEvents(t, ctx, baseURL+"/events?pvzId="+pvzID, moderatorToken)
+
+	reception := createReception(t, baseURL, employeeToken, pvzID)
+	product := createProduct(t, baseURL, employeeToken, pvzID)
+	closeReception(t, baseURL, employeeToken, pvzID)
+
+	opened := nextEvent(t, events)
+	assert.Equal(t, "reception.opened", opened.Type)
+	assert.Equal(t, pvzID, opened.PVZID)
+	assert.Equal(t, reception.ID, opened.ReceptionID)
+	assert.NotEmpty(t, opened.City)
+
+	added := nextEvent(t, events)
+	assert.Equal(t, "product.added", added.Type)
+	assert.Equal(t, product.ID, added.ProductID)
+	assert.Equal(t, reception.ID, added.ReceptionID)
+
+	closed := nextEvent(t, events)
+	assert.Equal(t, "reception.closed", closed.Type)
+	assert.Equal(t, reception.ID, closed.ReceptionID)
+
+	t.Run("backfill from cursor", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		resumed := subscribeEvents(t, ctx, baseURL+"/events?pvzId="+pvzID+"&cursor="+opened.Cursor, moderatorToken)
+
+		assert.Equal(t, added.Cursor, nextEvent(t, resumed).Cursor)
+		assert.Equal(t, closed.Cursor, nextEvent(t, resumed).Cursor)
+	})
+
+	t.Run("expired cursor", func(t *testing.T) {
+		req, err := nethttp.NewRequest(nethttp.MethodGet, baseURL+"/events?cursor=deadbeef-1", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+moderatorToken)
+
+		resp, err := nethttp.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, nethttp.StatusGone, resp.StatusCode)
+	})
+}
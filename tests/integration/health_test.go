@@ -0,0 +1,34 @@
+package integration
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"os"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegration_Health(t *testing.T) {
+	baseURL := "http://localhost:8080"
+
+	resp, err := nethttp.Get(baseURL + "/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, nethttp.StatusOK, resp.StatusCode)
+
+	resp, err = nethttp.Get(baseURL + "/readyz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body health.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, nethttp.StatusOK, resp.StatusCode, body.Checks)
+	// memory storage has no database checks
+	if os.Getenv("STORAGE_DRIVER") != "memory" {
+		assert.Equal(t, "ok", body.Checks["database"])
+		assert.Equal(t, "ok", body.Checks["migrations"])
+	}
+}
@@ -10,13 +10,50 @@ import (
 	"testing"
 	"time"
 
-	product_http "github.com/0x0FACED/pvz-avito/internal/product/delivery/http"
 	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
-	pvz_http "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/http"
-	reception_http "github.com/0x0FACED/pvz-avito/internal/reception/delivery/http"
 	"github.com/google/uuid"
 )
 
+// json of REST api as clients see it, tests decode responses
+// into these types to check compatibility of gateway json.
+
+type pvzCreateRequest struct {
+	ID               *string    `json:"id,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
+	City             string     `json:"city"`
+}
+
+type pvzResponse struct {
+	ID               *string    `json:"id,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
+	City             string     `json:"city"`
+}
+
+type receptionCreateRequest struct {
+	PVZID string `json:"pvzId"`
+}
+
+type receptionResponse struct {
+	ID       string     `json:"id"`
+	DateTime time.Time  `json:"dateTime"`
+	PVZID    string     `json:"pvzId"`
+	Status   string     `json:"status"`
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+	ClosedBy string     `json:"closedBy,omitempty"`
+}
+
+type productCreateRequest struct {
+	Type  string `json:"type"`
+	PVZID string `json:"pvzId"`
+}
+
+type productResponse struct {
+	ID          string    `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	Type        string    `json:"type"`
+	ReceptionID string    `json:"receptionId"`
+}
+
 func authUserDummy(t *testing.T, baseURL string, role string) string {
 	reqBody := map[string]string{"role": role}
 	data, err := json.Marshal(reqBody)
@@ -47,7 +84,7 @@ func createPVZ(t *testing.T, baseURL string, token string) string {
 	id := uuid.NewString()
 	regDate := time.Now()
 
-	reqBody := pvz_http.CreateRequest{
+	reqBody := pvzCreateRequest{
 		ID:               &id,
 		RegistrationDate: &regDate,
 		City:             "Москва",
@@ -79,7 +116,7 @@ func createPVZ(t *testing.T, baseURL string, token string) string {
 		t.Fatalf("unexpected status code from pvz create: %v", resp.StatusCode)
 	}
 
-	var result pvz_http.CreateResponse
+	var result pvzResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("failed to decode pvz response: %v", err)
 	}
@@ -87,8 +124,8 @@ func createPVZ(t *testing.T, baseURL string, token string) string {
 	return *result.ID
 }
 
-func createReception(t *testing.T, baseURL string, token string, pvzID string) reception_http.CreateResponse {
-	reqBody := reception_http.CreateRequest{
+func createReception(t *testing.T, baseURL string, token string, pvzID string) receptionResponse {
+	reqBody := receptionCreateRequest{
 		PVZID: pvzID,
 	}
 
@@ -118,7 +155,7 @@ func createReception(t *testing.T, baseURL string, token string, pvzID string) r
 		t.Fatalf("unexpected status code from reception create: %v", resp.StatusCode)
 	}
 
-	var result reception_http.CreateResponse
+	var result receptionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("failed to decode reception response: %v", err)
 	}
@@ -126,8 +163,8 @@ func createReception(t *testing.T, baseURL string, token string, pvzID string) r
 	return result
 }
 
-func createProduct(t *testing.T, baseURL string, token string, pvzID string) product_http.CreateResponse {
-	reqBody := product_http.CreateRequest{
+func createProduct(t *testing.T, baseURL string, token string, pvzID string) productResponse {
+	reqBody := productCreateRequest{
 		Type:  string(product_domain.Electronics),
 		PVZID: pvzID,
 	}
@@ -158,7 +195,7 @@ func createProduct(t *testing.T, baseURL string, token string, pvzID string) pro
 		t.Fatalf("unexpected status code from product create: %v", resp.StatusCode)
 	}
 
-	var result product_http.CreateResponse
+	var result productResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("failed to decode product response: %v", err)
 	}
@@ -166,7 +203,7 @@ func createProduct(t *testing.T, baseURL string, token string, pvzID string) pro
 	return result
 }
 
-func closeReception(t *testing.T, baseURL string, token string, pvzID string) reception_http.CreateResponse {
+func closeReception(t *testing.T, baseURL string, token string, pvzID string) receptionResponse {
 	addr, err := url.JoinPath(baseURL, "pvz", pvzID, "close_last_reception")
 	if err != nil {
 		t.Fatalf("failed to join baseURL and pvz: %v", err)
@@ -191,7 +228,7 @@ func closeReception(t *testing.T, baseURL string, token string, pvzID string) re
 		t.Fatalf("unexpected status code from close reception: %v", resp.StatusCode)
 	}
 
-	var result reception_http.CreateResponse
+	var result receptionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		t.Fatalf("failed to decode close reception response: %v", err)
 	}
@@ -200,7 +237,7 @@ func closeReception(t *testing.T, baseURL string, token string, pvzID string) re
 }
 
 func createProductAfterCloseReception(t *testing.T, baseURL string, token string, pvzID string) int {
-	reqBody := product_http.CreateRequest{
+	reqBody := productCreateRequest{
 		Type:  string(product_domain.Electronics),
 		PVZID: pvzID,
 	}
@@ -8,31 +8,78 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/debug"
 	"syscall"
 	"time"
 
+	apikey_svc "github.com/0x0FACED/pvz-avito/internal/apikey/application"
+	apikey_http "github.com/0x0FACED/pvz-avito/internal/apikey/delivery/http"
+	apikey_domain "github.com/0x0FACED/pvz-avito/internal/apikey/domain"
+	apikey_memory "github.com/0x0FACED/pvz-avito/internal/apikey/infra/memory"
+	apikey_db "github.com/0x0FACED/pvz-avito/internal/apikey/infra/postgres"
+	apikey_sqlite "github.com/0x0FACED/pvz-avito/internal/apikey/infra/sqlite"
 	"github.com/0x0FACED/pvz-avito/internal/app"
+	audit_svc "github.com/0x0FACED/pvz-avito/internal/audit/application"
+	audit_http "github.com/0x0FACED/pvz-avito/internal/audit/delivery/http"
+	audit_domain "github.com/0x0FACED/pvz-avito/internal/audit/domain"
+	audit_memory "github.com/0x0FACED/pvz-avito/internal/audit/infra/memory"
+	audit_db "github.com/0x0FACED/pvz-avito/internal/audit/infra/postgres"
+	audit_sqlite "github.com/0x0FACED/pvz-avito/internal/audit/infra/sqlite"
 	auth_svc "github.com/0x0FACED/pvz-avito/internal/auth/application"
 	auth_http "github.com/0x0FACED/pvz-avito/internal/auth/delivery/http"
+	auth_domain "github.com/0x0FACED/pvz-avito/internal/auth/domain"
+	auth_memory "github.com/0x0FACED/pvz-avito/internal/auth/infra/memory"
+	auth_oidc "github.com/0x0FACED/pvz-avito/internal/auth/infra/oidc"
 	auth_db "github.com/0x0FACED/pvz-avito/internal/auth/infra/postgres"
+	auth_sqlite "github.com/0x0FACED/pvz-avito/internal/auth/infra/sqlite"
+	events_svc "github.com/0x0FACED/pvz-avito/internal/events/application"
+	events_http "github.com/0x0FACED/pvz-avito/internal/events/delivery/http"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/cache"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/config"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/database"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/health"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/httpcommon"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/logger"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/memdb"
 	"github.com/0x0FACED/pvz-avito/internal/pkg/middleware"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/oidctest"
+	"github.com/0x0FACED/pvz-avito/internal/pkg/ratelimit"
 	product_svc "github.com/0x0FACED/pvz-avito/internal/product/application"
+	product_grpc "github.com/0x0FACED/pvz-avito/internal/product/delivery/grpc"
 	product_http "github.com/0x0FACED/pvz-avito/internal/product/delivery/http"
+	product_domain "github.com/0x0FACED/pvz-avito/internal/product/domain"
+	product_memory "github.com/0x0FACED/pvz-avito/internal/product/infra/memory"
 	product_db "github.com/0x0FACED/pvz-avito/internal/product/infra/postgres"
+	product_sqlite "github.com/0x0FACED/pvz-avito/internal/product/infra/sqlite"
 	pvz_svc "github.com/0x0FACED/pvz-avito/internal/pvz/application"
+	pvz_grpc "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/grpc"
 	pvz_http "github.com/0x0FACED/pvz-avito/internal/pvz/delivery/http"
+	pvz_domain "github.com/0x0FACED/pvz-avito/internal/pvz/domain"
+	"github.com/0x0FACED/pvz-avito/internal/pvz/infra/cached"
+	pvz_memory "github.com/0x0FACED/pvz-avito/internal/pvz/infra/memory"
 	pvz_db "github.com/0x0FACED/pvz-avito/internal/pvz/infra/postgres"
+	pvz_sqlite "github.com/0x0FACED/pvz-avito/internal/pvz/infra/sqlite"
 	reception_svc "github.com/0x0FACED/pvz-avito/internal/reception/application"
+	reception_grpc "github.com/0x0FACED/pvz-avito/internal/reception/delivery/grpc"
 	reception_http "github.com/0x0FACED/pvz-avito/internal/reception/delivery/http"
+	reception_domain "github.com/0x0FACED/pvz-avito/internal/reception/domain"
+	reception_memory "github.com/0x0FACED/pvz-avito/internal/reception/infra/memory"
 	reception_db "github.com/0x0FACED/pvz-avito/internal/reception/infra/postgres"
+	reception_sqlite "github.com/0x0FACED/pvz-avito/internal/reception/infra/sqlite"
+	sync_svc "github.com/0x0FACED/pvz-avito/internal/sync/application"
+	sync_http "github.com/0x0FACED/pvz-avito/internal/sync/delivery/http"
+	sync_domain "github.com/0x0FACED/pvz-avito/internal/sync/domain"
+	sync_memory "github.com/0x0FACED/pvz-avito/internal/sync/infra/memory"
+	sync_db "github.com/0x0FACED/pvz-avito/internal/sync/infra/postgres"
+	sync_sqlite "github.com/0x0FACED/pvz-avito/internal/sync/infra/sqlite"
+	"github.com/0x0FACED/pvz-avito/migrations"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// oidcProvider is fake identity provider for sso tests
+var oidcProvider *oidctest.Provider
+
 func TestMain(m *testing.M) {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -54,65 +101,253 @@ func TestMain(m *testing.M) {
 	pvzSvcLogger := logger.WithFeature("pvz_svc")
 	productSvcLogger := logger.WithFeature("product_svc")
 	receptionSvcLogger := logger.WithFeature("reception_svc")
+	auditSvcLogger := logger.WithFeature("audit_svc")
+	apiKeySvcLogger := logger.WithFeature("apikey_svc")
+	syncSvcLogger := logger.WithFeature("sync_svc")
+	eventsSvcLogger := logger.WithFeature("events_svc")
 
-	// connect to db pool
-	pool, err := database.ConnectPool(ctx, cfg.Database)
-	if err != nil {
-		return
+	// STORAGE_DRIVER=memory runs tests without postgres
+	if driver := os.Getenv("STORAGE_DRIVER"); driver != "" {
+		cfg.Storage.Driver = driver
+	}
+
+	var (
+		pool          *pgxpool.Pool
+		dbCheck       health.CheckFunc
+		schemaCheck   health.CheckFunc
+		authRepo      auth_domain.UserRepository
+		pvzRepo       pvz_domain.PVZRepository
+		productRepo   product_domain.ProductRepository
+		receptionRepo reception_domain.ReceptionRepository
+		auditRepo     audit_domain.AuditRepository
+		apiKeyRepo    apikey_domain.APIKeyRepository
+		journalRepo   sync_domain.JournalRepository
+		transactor    database.Transactor
+	)
+
+	switch cfg.Storage.Driver {
+	case "memory":
+		db := memdb.New()
+		authRepo = auth_memory.NewUserMemoryRepository(db)
+		pvzRepo = pvz_memory.NewPVZMemoryRepository(db)
+		productRepo = product_memory.NewProductMemoryRepository(db)
+		receptionRepo = reception_memory.NewReceptionMemoryRepository(db)
+		auditRepo = audit_memory.NewAuditMemoryRepository(db)
+		apiKeyRepo = apikey_memory.NewAPIKeyMemoryRepository(db)
+		journalRepo = sync_memory.NewJournalMemoryRepository(db)
+		transactor = db
+	case "sqlite":
+		// fresh database file for every run
+		dir, err := os.MkdirTemp("", "pvz-avito-integration")
+		if err != nil {
+			return
+		}
+		defer os.RemoveAll(dir)
+		cfg.SQLite.Path = filepath.Join(dir, cfg.SQLite.Path)
+
+		migrator, err := database.NewSQLiteMigrator(ctx, cfg.SQLite)
+		if err != nil {
+			return
+		}
+		if err := migrator.Up(); err != nil {
+			return
+		}
+		_ = migrator.Close()
+
+		db, err := database.OpenSQLite(ctx, cfg.SQLite)
+		if err != nil {
+			return
+		}
+		defer db.Close()
+
+		expectedVersion, err := migrations.SQLiteLatestVersion()
+		if err != nil {
+			return
+		}
+		dbCheck = db.PingContext
+		schemaCheck = health.MigrationCheck(expectedVersion, func(ctx context.Context) (uint, bool, error) {
+			return database.SQLiteSchemaVersion(ctx, db)
+		})
+
+		authRepo = auth_sqlite.NewAuthSQLiteRepository(db)
+		pvzRepo = pvz_sqlite.NewPVZSQLiteRepository(db)
+		productRepo = product_sqlite.NewProductSQLiteRepository(db)
+		receptionRepo = reception_sqlite.NewReceptionSQLiteRepository(db)
+		auditRepo = audit_sqlite.NewAuditSQLiteRepository(db)
+		apiKeyRepo = apikey_sqlite.NewAPIKeySQLiteRepository(db)
+		journalRepo = sync_sqlite.NewJournalSQLiteRepository(db)
+		transactor = database.NewSQLiteTransactor(db)
+	default:
+		// connect to db pool
+		var err error
+		pool, err = database.ConnectPool(ctx, cfg.Database)
+		if err != nil {
+			return
+		}
+		// test db is migrated with embedded migrations
+		migrator, err := database.NewMigrator(pool)
+		if err != nil {
+			return
+		}
+		if err := migrator.Up(); err != nil {
+			return
+		}
+		_ = migrator.Close()
+
+		// clearing db before test
+		clearDB(ctx, pool)
+
+		expectedVersion, err := migrations.LatestVersion()
+		if err != nil {
+			return
+		}
+		dbCheck = pool.Ping
+		schemaCheck = health.MigrationCheck(expectedVersion, func(ctx context.Context) (uint, bool, error) {
+			return database.SchemaVersion(ctx, pool)
+		})
+
+		// creating all repos
+		authRepo = auth_db.NewAuthPostgresRepository(pool)
+		pvzRepo = pvz_db.NewPVZPostgresRepository(pool)
+		productRepo = product_db.NewProductPostgresRepository(pool)
+		receptionRepo = reception_db.NewReceptionPostgresRepository(pool)
+		auditRepo = audit_db.NewAuditPostgresRepository(pool)
+		apiKeyRepo = apikey_db.NewAPIKeyPostgresRepository(pool)
+		journalRepo = sync_db.NewJournalPostgresRepository(pool)
+		transactor = database.NewPgxTransactor(pool)
 	}
-	// clearing db before test
-	clearDB(ctx, pool)
 
-	// creating all repos
-	authRepo := auth_db.NewAuthPostgresRepository(pool)
-	pvzRepo := pvz_db.NewPVZPostgresRepository(pool)
-	productRepo := product_db.NewProductPostgresRepository(pool)
-	receptionRepo := reception_db.NewReceptionPostgresRepository(pool)
+	// pvz listings are cached, reception and product writes invalidate them
+	if cfg.PVZCache.Enabled {
+		cachedPVZRepo := cached.NewPVZRepository(pvzRepo, cache.NewLRU(cfg.PVZCache.Size), cfg.PVZCache.TTL)
+		pvzRepo = cachedPVZRepo
+		receptionRepo = cached.NewReceptionRepository(receptionRepo, cachedPVZRepo)
+		productRepo = cached.NewProductRepository(productRepo, cachedPVZRepo)
+	}
 
 	// creating all svcs
-	authSvc := auth_svc.NewAuthService(authRepo, authSvcLogger)
-	pvzSvc := pvz_svc.NewPVZService(pvzRepo, receptionRepo, productRepo, pvzSvcLogger)
-	productSvc := product_svc.NewProductService(productRepo, receptionRepo, productSvcLogger)
-	receptionSvc := reception_svc.NewReceptionService(receptionRepo, receptionSvcLogger)
+	auditSvc := audit_svc.NewAuditService(auditRepo, auditSvcLogger)
+	broker := events_svc.NewBroker(cfg.Events.BackfillSize, cfg.Events.SubscriberBuffer)
+	publisher := events_svc.NewPublisher(auditSvc, broker, receptionRepo, pvzRepo, eventsSvcLogger)
+	loginAttemptRepo := auth_memory.NewLoginAttemptMemoryRepository()
+
+	loginGuard := auth_svc.NewLoginGuard(loginAttemptRepo, auth_svc.LoginPolicy{
+		FreeAttempts:    cfg.Auth.LoginFreeAttempts,
+		MaxAttempts:     cfg.Auth.LoginMaxAttempts,
+		IPMaxAttempts:   cfg.Auth.LoginIPMaxAttempts,
+		BaseDelay:       cfg.Auth.LoginBaseDelay,
+		MaxDelay:        cfg.Auth.LoginMaxDelay,
+		LockoutDuration: cfg.Auth.LoginLockoutDuration,
+		Window:          cfg.Auth.LoginAttemptsWindow,
+	}, auditSvc, authSvcLogger)
+
+	hasher := auth_svc.NewBcryptHasher(cfg.Auth.BcryptCost)
+
+	authSvc := auth_svc.NewAuthService(authRepo, loginGuard, hasher, auth_svc.PasswordPolicy{}, auditSvc, authSvcLogger)
+	pvzSvc := pvz_svc.NewPVZService(pvzRepo, receptionRepo, productRepo, publisher, pvzSvcLogger)
+	productSvc := product_svc.NewProductService(productRepo, receptionRepo, publisher, productSvcLogger)
+	receptionSvc := reception_svc.NewReceptionService(receptionRepo, publisher, receptionSvcLogger)
+	apiKeySvc := apikey_svc.NewAPIKeyService(apiKeyRepo, auditSvc, apiKeySvcLogger)
+	syncSvc := sync_svc.NewSyncService(journalRepo, transactor, receptionSvc, productSvc, pvzSvc, syncSvcLogger)
+	eventsSvc := events_svc.NewEventsService(broker, eventsSvcLogger)
 
 	// jwt manager (move diration to cfg)
 	jwt := httpcommon.NewManager(cfg.Server.JWTSecret, time.Hour*240)
 
+	// access log with redacted secrets
+	accessLog := middleware.AccessLogConfig{
+		LogBody:      cfg.Logger.AccessLogBody,
+		MaxBodySize:  cfg.Logger.MaxBodySize,
+		RedactFields: cfg.Logger.RedactFields,
+	}
+
 	// create middleware
-	middleware := middleware.NewMiddlewareHandler(jwt, httpLogger)
+	middleware := middleware.NewMiddlewareHandler(jwt, apiKeySvc, cfg.Server.DummyLogin(), cfg.Server.DummyRejectMutating, httpLogger)
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		policy, err := ratelimit.ParsePolicy(cfg.RateLimit.Default, cfg.RateLimit.Routes)
+		if err != nil {
+			return
+		}
+		limiter = ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policy)
+	}
+	rateLimit := middleware.RateLimit(limiter)
 
 	// create all handlers
-	authHandler := auth_http.NewHandler(authSvc, jwt)
-	pvzHandler := pvz_http.NewHandler(pvzSvc)
-	productHandler := product_http.NewHandler(productSvc)
-	receptionHandler := reception_http.NewHandler(receptionSvc)
+	authHandler := auth_http.NewHandler(authSvc, jwt, cfg.Server.DummyLogin())
+	pvzHandler := pvz_http.NewHandler(pvz_grpc.NewGRPCHandler(pvzSvc))
+	productHandler := product_http.NewHandler(product_grpc.NewGRPCHandler(productSvc))
+	receptionHandler := reception_http.NewHandler(reception_grpc.NewGRPCHandler(receptionSvc))
+	auditHandler := audit_http.NewHandler(auditSvc)
+	apiKeyHandler := apikey_http.NewHandler(apiKeySvc)
+	syncHandler := sync_http.NewHandler(syncSvc)
+	eventsHandler := events_http.NewHandler(eventsSvc, cfg.Events.Heartbeat)
 
 	// registering routes with middleware
 	mux := http.NewServeMux()
 	authHandler.RegisterRoutes(mux)
 
+	oidcProvider = oidctest.New("pvz", "pvz-secret")
+	defer oidcProvider.Close()
+
+	provider, err := auth_oidc.NewProvider(ctx, auth_oidc.Config{
+		Issuer:       oidcProvider.Issuer(),
+		ClientID:     "pvz",
+		ClientSecret: "pvz-secret",
+		RedirectURL:  "http://localhost:" + cfg.Server.Port + "/auth/oidc/callback",
+		GroupsClaim:  "groups",
+	})
+	if err != nil {
+		return
+	}
+
+	ssoSvc := auth_svc.NewSSOService(authRepo, provider, auth_svc.RoleMapping{
+		Groups: map[string]auth_domain.Role{
+			"pvz-moderators": auth_domain.RoleModerator,
+			"pvz-staff":      auth_domain.RoleEmployee,
+		},
+	}, auditSvc, authSvcLogger)
+	auth_http.NewSSOHandler(ssoSvc, jwt, false).RegisterRoutes(mux)
+
 	// protected with auth middleware
 	privateMux := http.NewServeMux()
 	pvzHandler.RegisterRoutes(privateMux)
 	productHandler.RegisterRoutes(privateMux)
 	receptionHandler.RegisterRoutes(privateMux)
+	auditHandler.RegisterRoutes(privateMux)
+	apiKeyHandler.RegisterRoutes(privateMux)
+	syncHandler.RegisterRoutes(privateMux)
+	eventsHandler.RegisterRoutes(privateMux)
+	authHandler.RegisterPrivateRoutes(privateMux)
 
 	// apply auth for '/' routes (all expect public /login, /dummyLogin, /register)
-	mux.Handle("/", middleware.Auth(privateMux))
+	mux.Handle("/", middleware.Auth(middleware.Route(privateMux)))
 
 	// apply logger middleware for all routes
 	// this is final mux
-	loggedMux := middleware.Logger(mux)
+	loggedMux := middleware.Tracing(middleware.RequestInfo(middleware.Logger(accessLog)(rateLimit(middleware.Route(mux)))))
+
+	appHealth := health.New(cfg.Health.CheckTimeout, cfg.Health.GRPCInterval, logger)
+	if dbCheck != nil {
+		appHealth.AddCheck("database", dbCheck)
+		appHealth.AddCheck("migrations", schemaCheck)
+	}
+
+	rootMux := http.NewServeMux()
+	appHealth.RegisterRoutes(rootMux)
+	rootMux.Handle("/", loggedMux)
 
 	srv := &http.Server{
 		Addr:         cfg.Server.Host + ":" + cfg.Server.Port,
-		Handler:      loggedMux,
+		Handler:      rootMux,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	srv.RegisterOnShutdown(broker.Close)
 
-	app := app.New(srv, nil, nil, logger, cfg)
+	app := app.New(srv, nil, nil, logger, cfg, app.WithHealth(appHealth, cfg.Health.ShutdownDelay))
 
 	go func() {
 		if err := app.Start(ctx); err != nil {
@@ -134,4 +369,6 @@ func clearDB(ctx context.Context, db *pgxpool.Pool) {
 	_, _ = db.Exec(ctx, "DELETE FROM avito.pvz")
 	_, _ = db.Exec(ctx, "DELETE FROM avito.products")
 	_, _ = db.Exec(ctx, "DELETE FROM avito.receptions")
+	_, _ = db.Exec(ctx, "DELETE FROM avito.audit_log")
+	_, _ = db.Exec(ctx, "DELETE FROM avito.api_keys")
 }
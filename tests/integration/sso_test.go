@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"io"
+	nethttp "net/http"
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/0x0FACED/pvz-avito/internal/pkg/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegration_SSO_Flow(t *testing.T) {
+	baseURL := "http://localhost:8080"
+	verified := true
+
+	tests := []struct {
+		name         string
+		user         oidctest.User
+		expectStatus int
+	}{
+		{
+			name:         "employee is provisioned",
+			user:         oidctest.User{Subject: "sso-1", Email: "sso.employee@example.com", EmailVerified: &verified, Groups: []string{"pvz-staff"}},
+			expectStatus: nethttp.StatusOK,
+		},
+		{
+			name:         "role is synced on next login",
+			user:         oidctest.User{Subject: "sso-1", Email: "sso.employee@example.com", EmailVerified: &verified, Groups: []string{"pvz-moderators"}},
+			expectStatus: nethttp.StatusOK,
+		},
+		{
+			name:         "no mapped group",
+			user:         oidctest.User{Subject: "sso-2", Email: "sso.other@example.com", EmailVerified: &verified, Groups: []string{"sales"}},
+			expectStatus: nethttp.StatusForbidden,
+		},
+		{
+			name:         "email_verified claim missing",
+			user:         oidctest.User{Subject: "sso-3", Email: "sso.unverified@example.com", Groups: []string{"pvz-staff"}},
+			expectStatus: nethttp.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidcProvider.SetUser(tt.user)
+
+			// cookie jar carries sso cookie from /auth/oidc/login to callback
+			jar, err := cookiejar.New(nil)
+			require.NoError(t, err)
+			client := &nethttp.Client{Jar: jar}
+
+			resp, err := client.Get(baseURL + "/auth/oidc/login")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			require.Equal(t, tt.expectStatus, resp.StatusCode, string(body))
+
+			if tt.expectStatus == nethttp.StatusOK {
+				assert.NotEmpty(t, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	nethttp "net/http"
+	"testing"
+	"time"
+
+	sync_http "github.com/0x0FACED/pvz-avito/internal/sync/delivery/http"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syncJournal(t *testing.T, baseURL, token string, ops ...sync_http.OperationRequest) sync_http.SyncResponse {
+	t.Helper()
+
+	data, err := json.Marshal(sync_http.SyncRequest{Operations: ops})
+	require.NoError(t, err)
+
+	req, err := nethttp.NewRequest(nethttp.MethodPost, baseURL+"/sync", bytes.NewBuffer(data))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+
+	var syncResp sync_http.SyncResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&syncResp))
+	require.Len(t, syncResp.Results, len(ops))
+
+	return syncResp
+}
+
+func syncStatuses(resp sync_http.SyncResponse) []string {
+	out := make([]string, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		out = append(out, r.Status)
+	}
+
+	return out
+}
+
+func TestIntegration_OfflineSync(t *testing.T) {
+	baseURL := "http://localhost:8080"
+
+	moderatorToken := authUserDummy(t, baseURL, "moderator")
+	employeeToken := authUserDummy(t, baseURL, "employee")
+
+	pvzID := createPVZ(t, baseURL, moderatorToken)
+
+	start := time.Now().Add(-time.Hour).UTC()
+	op := func(typ string, minute int) sync_http.OperationRequest {
+		req := sync_http.OperationRequest{
+			ID:         uuid.NewString(),
+			Type:       typ,
+			PVZID:      pvzID,
+			ClientTime: start.Add(time.Duration(minute) * time.Minute),
+		}
+		if typ == "add_product" {
+			req.ProductType = "обувь"
+		}
+		return req
+	}
+
+	open := op("open_reception", 0)
+	first := op("add_product", 1)
+	second := op("add_product", 2)
+	journal := []sync_http.OperationRequest{open, first, second, op("delete_last_product", 3)}
+
+	resp := syncJournal(t, baseURL, employeeToken, journal...)
+	assert.Equal(t, []string{"applied", "applied", "applied", "applied"}, syncStatuses(resp))
+	assert.Equal(t, open.ID, resp.Results[0].EntityID, "reception gets id of operation")
+	assert.Equal(t, first.ID, resp.Results[1].EntityID, "product gets id of operation")
+
+	// terminal is online again, reception opened offline is used
+	product := createProduct(t, baseURL, employeeToken, pvzID)
+	assert.Equal(t, open.ID, product.ReceptionID)
+
+	// terminal did not get response and uploads same journal again,
+	// nothing is replayed, so online product is not deleted
+	replay := syncJournal(t, baseURL, employeeToken, journal...)
+	assert.Equal(t, syncStatuses(resp), syncStatuses(replay))
+	for i, r := range replay.Results {
+		assert.True(t, r.Duplicate)
+		assert.Equal(t, resp.Results[i].EntityID, r.EntityID)
+	}
+
+	closeOp := op("close_reception", 10)
+	late := op("add_product", 11)
+	unknown := op("scan_barcode", 12)
+
+	resp = syncJournal(t, baseURL, employeeToken, closeOp, late, unknown)
+	assert.Equal(t, []string{"applied", "conflict", "rejected"}, syncStatuses(resp))
+	assert.Equal(t, open.ID, resp.Results[0].EntityID)
+	assert.NotEmpty(t, resp.Results[1].Error)
+	assert.NotEmpty(t, resp.Results[2].Error)
+
+	// conflicts are final too
+	replay = syncJournal(t, baseURL, employeeToken, late)
+	assert.Equal(t, "conflict", replay.Results[0].Status)
+	assert.True(t, replay.Results[0].Duplicate)
+}